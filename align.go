@@ -0,0 +1,194 @@
+package scribus
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Rect is a rectangle in document coordinates (the same as XPOS and YPOS of items)
+type Rect struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// Right returns the x coordinate of the right edge
+func (r Rect) Right() float64 { return r.X + r.Width }
+
+// Bottom returns the y coordinate of the bottom edge
+func (r Rect) Bottom() float64 { return r.Y + r.Height }
+
+// Center returns the centre of the rectangle
+func (r Rect) Center() Point { return Point{r.X + r.Width/2, r.Y + r.Height/2} }
+
+// Union returns the smallest rectangle that contains r and other
+func (r Rect) Union(other Rect) Rect {
+	x, y := math.Min(r.X, other.X), math.Min(r.Y, other.Y)
+	return Rect{x, y, math.Max(r.Right(), other.Right()) - x, math.Max(r.Bottom(), other.Bottom()) - y}
+}
+
+// BoundingBox returns the smallest rectangle that contains the item, taking its rotation
+// into account
+func (po PAGEOBJECT) BoundingBox() Rect {
+	min, max := po.boundingBox()
+	return Rect{min.X, min.Y, max.X - min.X, max.Y - min.Y}
+}
+
+// SelectionBounds returns the smallest rectangle that contains the bounding boxes of the items
+func SelectionBounds(items []*PAGEOBJECT) Rect {
+	var r Rect
+	for i, po := range items {
+		if i == 0 {
+			r = po.BoundingBox()
+		} else {
+			r = r.Union(po.BoundingBox())
+		}
+	}
+	return r
+}
+
+// PageBounds returns the rectangle of the page with the index page, error
+func (doc DOCUMENT) PageBounds(page int) (Rect, error) {
+	if page < 0 || page >= len(doc.PAGE) {
+		return Rect{}, fmt.Errorf("page %v not found, the document has %v pages", page, len(doc.PAGE))
+	}
+	p := doc.PAGE[page]
+	return Rect{parseFloat(p.PAGEXPOS), parseFloat(p.PAGEYPOS), parseFloat(p.PAGEWIDTH), parseFloat(p.PAGEHEIGHT)}, nil
+}
+
+// MarginBounds returns the rectangle within the margins of the page with the index page, error
+func (doc DOCUMENT) MarginBounds(page int) (Rect, error) {
+	r, err := doc.PageBounds(page)
+	if err != nil {
+		return r, err
+	}
+	p := doc.PAGE[page]
+	left, top := parseFloat(p.BORDERLEFT), parseFloat(p.BORDERTOP)
+	return Rect{r.X + left, r.Y + top, r.Width - left - parseFloat(p.BORDERRIGHT), r.Height - top - parseFloat(p.BORDERBOTTOM)}, nil
+}
+
+// Alignment is an edge or the centre line that items are aligned or distributed by
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+	AlignTop
+	AlignMiddle
+	AlignBottom
+)
+
+// vertical tells whether the alignment moves items up and down
+func (a Alignment) vertical() bool { return a >= AlignTop }
+
+// edge returns the coordinate of r the alignment refers to
+func (a Alignment) edge(r Rect) float64 {
+	switch a {
+	case AlignLeft:
+		return r.X
+	case AlignCenter:
+		return r.X + r.Width/2
+	case AlignRight:
+		return r.Right()
+	case AlignTop:
+		return r.Y
+	case AlignMiddle:
+		return r.Y + r.Height/2
+	}
+	return r.Bottom()
+}
+
+// moveTo moves the item so that the edge or centre line a of its bounding box is at position
+func (a Alignment) moveTo(po *PAGEOBJECT, position float64) {
+	d := position - a.edge(po.BoundingBox())
+	if a.vertical() {
+		shiftPageObject(po, 0, d)
+	} else {
+		shiftPageObject(po, d, 0)
+	}
+}
+
+// Align moves the items so that the edge or centre line of their bounding boxes lines up with
+// the one of reference, e.g., SelectionBounds(items), doc.PageBounds(page), doc.MarginBounds(page)
+// or the BoundingBox() of a key item
+func Align(items []*PAGEOBJECT, alignment Alignment, reference Rect) {
+	position := alignment.edge(reference)
+	for _, po := range items {
+		alignment.moveTo(po, position)
+	}
+}
+
+// sortedByPosition returns the items sorted by the left (or top) edge of their bounding boxes
+func sortedByPosition(items []*PAGEOBJECT, vertical bool) []*PAGEOBJECT {
+	sorted := append([]*PAGEOBJECT{}, items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].BoundingBox(), sorted[j].BoundingBox()
+		if vertical {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+	return sorted
+}
+
+// Distribute moves the items so that the edges or centre lines of their bounding boxes are
+// equally far apart. The items at the ends stay where they are
+func Distribute(items []*PAGEOBJECT, alignment Alignment) {
+	if len(items) < 3 {
+		return
+	}
+	sorted := sortedByPosition(items, alignment.vertical())
+	first := alignment.edge(sorted[0].BoundingBox())
+	step := (alignment.edge(sorted[len(sorted)-1].BoundingBox()) - first) / float64(len(sorted)-1)
+	for i, po := range sorted[1 : len(sorted)-1] {
+		alignment.moveTo(po, first+float64(i+1)*step)
+	}
+}
+
+// DistributeSpacing moves the items so that the gaps between their bounding boxes are equal,
+// side by side or, if vertical, one below the other. The items at the ends stay where they are
+func DistributeSpacing(items []*PAGEOBJECT, vertical bool) {
+	if len(items) < 3 {
+		return
+	}
+	sorted := sortedByPosition(items, vertical)
+	span, start, end := 0.0, sorted[0].BoundingBox(), sorted[len(sorted)-1].BoundingBox()
+	for _, po := range sorted {
+		r := po.BoundingBox()
+		if vertical {
+			span += r.Height
+		} else {
+			span += r.Width
+		}
+	}
+	if vertical {
+		distributeGap(sorted, true, (end.Bottom()-start.Y-span)/float64(len(sorted)-1))
+	} else {
+		distributeGap(sorted, false, (end.Right()-start.X-span)/float64(len(sorted)-1))
+	}
+}
+
+// DistributeGap moves the items so that there is gap between their bounding boxes, side by
+// side or, if vertical, one below the other. The first item stays where it is
+func DistributeGap(items []*PAGEOBJECT, vertical bool, gap float64) {
+	if len(items) < 2 {
+		return
+	}
+	distributeGap(sortedByPosition(items, vertical), vertical, gap)
+}
+
+// distributeGap puts gap between the bounding boxes of the sorted items
+func distributeGap(sorted []*PAGEOBJECT, vertical bool, gap float64) {
+	previous := sorted[0].BoundingBox()
+	for _, po := range sorted[1:] {
+		if vertical {
+			AlignTop.moveTo(po, previous.Bottom()+gap)
+		} else {
+			AlignLeft.moveTo(po, previous.Right()+gap)
+		}
+		previous = po.BoundingBox()
+	}
+}
@@ -0,0 +1,60 @@
+package scribus
+
+import (
+	"testing"
+)
+
+func TestAlignAndDistribute(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := &document.DOCUMENT
+	// Items at 140, 60 (118.5 x 52.5), 140, 113, 140, 181 (150.25 x 163.5) and 140, 368
+	a, b, c, d := &doc.PAGEOBJECT[0], &doc.PAGEOBJECT[1], &doc.PAGEOBJECT[2], &doc.PAGEOBJECT[3]
+
+	if got := SelectionBounds([]*PAGEOBJECT{a, c}); got != (Rect{140, 60, 150.25, 284.5}) {
+		t.Errorf("SelectionBounds() was incorrect, got: %+v", got)
+	}
+	Align([]*PAGEOBJECT{a, b}, AlignRight, c.BoundingBox())
+	if a.XPOS != "171.75" || b.XPOS != "171.75" || a.YPOS != "60" {
+		t.Errorf("Align() to a key item was incorrect, got: %v, %v", a.XPOS, b.XPOS)
+	}
+	margins, err := doc.MarginBounds(0)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if margins != (Rect{140, 60, 532, 712}) {
+		t.Errorf("MarginBounds() was incorrect, got: %+v", margins)
+	}
+	Align([]*PAGEOBJECT{a}, AlignRight, margins)
+	if a.XPOS != "553.5" {
+		t.Errorf("Align() to the margins was incorrect, got: %v", a.XPOS)
+	}
+	if _, err := doc.PageBounds(1); err == nil {
+		t.Errorf("PageBounds() of a missing page should be an error")
+	}
+
+	// A rotated item is aligned by its bounding box, 52.5 wide and left of XPOS
+	d.ROT = "90"
+	page, _ := doc.PageBounds(0)
+	Align([]*PAGEOBJECT{d}, AlignLeft, page)
+	if d.XPOS != "152.5" {
+		t.Errorf("Align() of a rotated item was incorrect, got: %v", d.XPOS)
+	}
+
+	// The items are 60 - 112.5, 113 - 165.5 and 181 - 344.5 high, with 268.5 of 284.5 taken
+	DistributeSpacing([]*PAGEOBJECT{c, b, a}, true)
+	if a.YPOS != "60" || b.YPOS != "120.5" || c.YPOS != "181" {
+		t.Errorf("DistributeSpacing() was incorrect, got: %v, %v, %v", a.YPOS, b.YPOS, c.YPOS)
+	}
+	DistributeGap([]*PAGEOBJECT{a, b, c}, true, 10)
+	if b.YPOS != "122.5" || c.YPOS != "185" {
+		t.Errorf("DistributeGap() was incorrect, got: %v, %v", b.YPOS, c.YPOS)
+	}
+	// The bottom edges at 112.5 and 348.5 put the middle one at 230.5
+	Distribute([]*PAGEOBJECT{a, b, c}, AlignBottom)
+	if a.YPOS != "60" || b.YPOS != "178" || c.YPOS != "185" {
+		t.Errorf("Distribute() was incorrect, got: %v, %v, %v", a.YPOS, b.YPOS, c.YPOS)
+	}
+}
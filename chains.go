@@ -0,0 +1,220 @@
+package scribus
+
+import (
+	"fmt"
+)
+
+// Story is a chain of linked text frames that share one text. The frames are linked
+// through NEXTITEM and BACKITEM, which hold the ItemID of the next and the previous
+// frame or "-1". Scribus keeps the text of the whole chain in the first frame
+type Story struct {
+	Frames []*PAGEOBJECT
+}
+
+// linked tells whether a NEXTITEM or BACKITEM value refers to another frame
+func linked(id string) bool {
+	return id != "" && id != "-1"
+}
+
+// pageObjectsByID returns pointers to all MASTEROBJECTs, PAGEOBJECTs and FRAMEOBJECTs
+// (including the items inside groups) by ItemID
+func (doc DOCUMENT) pageObjectsByID() map[string]*PAGEOBJECT {
+	items := map[string]*PAGEOBJECT{}
+	doc.walkAllPageObjects(func(po *PAGEOBJECT) {
+		if po.ItemID != "" {
+			items[po.ItemID] = po
+		}
+	})
+	return items
+}
+
+// Stories returns all stories of the document, i.e., every chain of text frames
+// and every text frame that is not linked, in document order: those on master
+// pages first and those in inline frames last
+func (doc DOCUMENT) Stories() []Story {
+	items := doc.pageObjectsByID()
+	var stories []Story
+	doc.walkAllPageObjects(func(po *PAGEOBJECT) {
+		if po.PTYPE != "4" {
+			return
+		}
+		if _, ok := items[po.BACKITEM]; ok && linked(po.BACKITEM) {
+			return // Not the first frame of its chain
+		}
+		stories = append(stories, doc.storyFrom(po, items))
+	})
+	return stories
+}
+
+// GetStory returns the Story that the text frame po belongs to
+func (doc DOCUMENT) GetStory(po *PAGEOBJECT) Story {
+	items := doc.pageObjectsByID()
+	first := po
+	seen := map[*PAGEOBJECT]bool{first: true}
+	for linked(first.BACKITEM) {
+		prev, ok := items[first.BACKITEM]
+		if !ok || seen[prev] {
+			break
+		}
+		seen[prev] = true
+		first = prev
+	}
+	return doc.storyFrom(first, items)
+}
+
+func (doc DOCUMENT) storyFrom(first *PAGEOBJECT, items map[string]*PAGEOBJECT) Story {
+	story := Story{Frames: []*PAGEOBJECT{first}}
+	seen := map[*PAGEOBJECT]bool{first: true}
+	for po := first; linked(po.NEXTITEM); {
+		next, ok := items[po.NEXTITEM]
+		if !ok || seen[next] {
+			break
+		}
+		seen[next] = true
+		story.Frames = append(story.Frames, next)
+		po = next
+	}
+	return story
+}
+
+// First returns the first frame of the Story, which holds its text
+func (s Story) First() *PAGEOBJECT {
+	if len(s.Frames) == 0 {
+		return nil
+	}
+	return s.Frames[0]
+}
+
+// Text returns the full text of the Story (see StoryText.PlainText)
+func (s Story) Text() string {
+	text := ""
+	for _, po := range s.Frames {
+		if t := po.StoryText.PlainText(); t != "" {
+			if text != "" {
+				text += "\n"
+			}
+			text += t
+		}
+	}
+	return text
+}
+
+// SetText replaces the text of the Story. The text is put into the first frame,
+// any text in the other frames of the chain is removed
+func (s Story) SetText(text string) {
+	if len(s.Frames) == 0 {
+		return
+	}
+	s.Frames[0].StoryText.SetPlainText(text)
+	for _, po := range s.Frames[1:] {
+		po.StoryText.resetContent()
+		po.StoryText.StoryTextSpan = nil
+	}
+}
+
+// LinkFrames links the text frame to after the text frame from, so that the text of
+// from's chain flows on into to. Like in Scribus, from must be the last frame of its
+// chain and to must be empty and must not be linked to a previous frame. Returns error
+func (doc DOCUMENT) LinkFrames(from *PAGEOBJECT, to *PAGEOBJECT) error {
+	if err := doc.checkLink(from, to); err != nil {
+		return err
+	}
+	if linked(from.NEXTITEM) {
+		return fmt.Errorf("frame %v is already linked to frame %v", from.ItemID, from.NEXTITEM)
+	}
+	if linked(to.BACKITEM) {
+		return fmt.Errorf("frame %v is already linked from frame %v", to.ItemID, to.BACKITEM)
+	}
+	from.NEXTITEM = to.ItemID
+	to.BACKITEM = from.ItemID
+	return nil
+}
+
+// checkLink returns error if the text frame to cannot follow the text frame from: both must be
+// text frames with an ItemID, and to and the frames after it must be empty and must not
+// include from
+func (doc DOCUMENT) checkLink(from *PAGEOBJECT, to *PAGEOBJECT) error {
+	if from.PTYPE != "4" || to.PTYPE != "4" {
+		return fmt.Errorf("only text frames can be linked")
+	}
+	if from.ItemID == "" || to.ItemID == "" {
+		return fmt.Errorf("frames without ItemID cannot be linked")
+	}
+	for _, po := range doc.storyFrom(to, doc.pageObjectsByID()).Frames {
+		if po == from {
+			return fmt.Errorf("linking frame %v to frame %v would create a loop", from.ItemID, to.ItemID)
+		}
+		if po.StoryText.PlainText() != "" {
+			return fmt.Errorf("frame %v is not empty", po.ItemID)
+		}
+	}
+	return nil
+}
+
+// UnlinkFrame breaks the link after the text frame po; the text stays in the
+// first frame of po's chain. Returns error
+func (doc DOCUMENT) UnlinkFrame(po *PAGEOBJECT) error {
+	if !linked(po.NEXTITEM) {
+		return fmt.Errorf("frame %v is not linked to a next frame", po.ItemID)
+	}
+	if next, ok := doc.pageObjectsByID()[po.NEXTITEM]; ok && next.BACKITEM == po.ItemID {
+		next.BACKITEM = "-1"
+	}
+	po.NEXTITEM = "-1"
+	return nil
+}
+
+// RelinkFrame links the text frame from to the text frame to instead of its current
+// next frame, returns error. Nothing is changed if to cannot follow from
+func (doc DOCUMENT) RelinkFrame(from *PAGEOBJECT, to *PAGEOBJECT) error {
+	if err := doc.checkLink(from, to); err != nil {
+		return err
+	}
+	if linked(from.NEXTITEM) {
+		if err := doc.UnlinkFrame(from); err != nil {
+			return err
+		}
+	}
+	if linked(to.BACKITEM) {
+		if prev, ok := doc.pageObjectsByID()[to.BACKITEM]; ok {
+			if err := doc.UnlinkFrame(prev); err != nil {
+				return err
+			}
+		} else {
+			to.BACKITEM = "-1"
+		}
+	}
+	return doc.LinkFrames(from, to)
+}
+
+// RepairChains makes the NEXTITEM and BACKITEM values of all frames consistent: links to
+// frames that do not exist are removed and one-sided links are completed. Returns the
+// ItemIDs of the frames that were changed
+func (doc DOCUMENT) RepairChains() []string {
+	items := doc.pageObjectsByID()
+	var changed []string
+	doc.walkAllPageObjects(func(po *PAGEOBJECT) {
+		if linked(po.NEXTITEM) {
+			next, ok := items[po.NEXTITEM]
+			switch {
+			case !ok || next == po:
+				po.NEXTITEM = "-1"
+				changed = append(changed, po.ItemID)
+			case !linked(next.BACKITEM):
+				next.BACKITEM = po.ItemID
+				changed = append(changed, next.ItemID)
+			}
+		}
+		if linked(po.BACKITEM) {
+			prev, ok := items[po.BACKITEM]
+			if !ok || prev == po || (linked(prev.NEXTITEM) && prev.NEXTITEM != po.ItemID) {
+				po.BACKITEM = "-1"
+				changed = append(changed, po.ItemID)
+			} else if !linked(prev.NEXTITEM) {
+				prev.NEXTITEM = po.ItemID
+				changed = append(changed, prev.ItemID)
+			}
+		}
+	})
+	return changed
+}
@@ -0,0 +1,119 @@
+package scribus
+
+import (
+	"testing"
+)
+
+func TestStoryText(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	st := &document.DOCUMENT.PAGEOBJECT[3].StoryText
+	if st.PlainText() != "Three\nFour\nFive" {
+		t.Errorf("PlainText() was incorrect, got: %q, want: %q.", st.PlainText(), "Three\nFour\nFive")
+	}
+
+	st.SetPlainText("A\tB\nC")
+	clone, err := document.Clone()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	st = &clone.DOCUMENT.PAGEOBJECT[3].StoryText
+	if st.PlainText() != "A\tB\nC" {
+		t.Errorf("PlainText() was incorrect, got: %q, want: %q.", st.PlainText(), "A\tB\nC")
+	}
+	if len(st.Elements) != 1 || st.Elements[0].XMLName.Local != "tab" {
+		t.Errorf("st.Elements was incorrect, got: %v", st.Elements)
+	}
+
+	// Runs and paragraphs added or removed directly keep the order of the others
+	st.ITEXT = append(st.ITEXT, ITEXT{CH: "D"})
+	st.Para = append(st.Para, Para{})
+	st.ITEXT = append(st.ITEXT, ITEXT{CH: "E"})
+	if st.PlainText() != "A\tB\nCD\nE" {
+		t.Errorf("PlainText() after appending was incorrect, got: %q", st.PlainText())
+	}
+	st.ITEXT = st.ITEXT[:len(st.ITEXT)-1]
+	st.Para = st.Para[:len(st.Para)-1]
+	if st.PlainText() != "A\tB\nCD" {
+		t.Errorf("PlainText() after removing was incorrect, got: %q", st.PlainText())
+	}
+}
+
+func TestStories(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := document.DOCUMENT
+	if len(doc.Stories()) != 3 {
+		t.Errorf("len(doc.Stories()) was incorrect, got: %v, want: %v.", len(doc.Stories()), 3)
+	}
+
+	one, two := &doc.PAGEOBJECT[0], &doc.PAGEOBJECT[1]
+	if err := doc.LinkFrames(one, two); err == nil {
+		t.Errorf("expected an error when linking to a frame that is not empty")
+	}
+	two.StoryText.SetPlainText("")
+	if err := doc.LinkFrames(one, two); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if two.BACKITEM != one.ItemID || one.NEXTITEM != two.ItemID {
+		t.Errorf("frames were not linked")
+	}
+	if err := doc.LinkFrames(two, one); err == nil {
+		t.Errorf("expected an error when creating a loop")
+	}
+	if err := doc.RelinkFrame(one, &doc.PAGEOBJECT[2]); err == nil || one.NEXTITEM != two.ItemID || two.BACKITEM != one.ItemID {
+		t.Errorf("RelinkFrame() to an image should be an error and keep the link")
+	}
+
+	story := doc.GetStory(two)
+	if len(story.Frames) != 2 || story.First() != one {
+		t.Errorf("story was incorrect, got: %v", story.Frames)
+	}
+	story.SetText("First\nSecond")
+	if doc.Stories()[0].Text() != "First\nSecond" {
+		t.Errorf("Text() was incorrect, got: %q", doc.Stories()[0].Text())
+	}
+
+	if err := doc.UnlinkFrame(one); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if two.BACKITEM != "-1" || len(doc.Stories()) != 3 {
+		t.Errorf("frames were not unlinked")
+	}
+
+	two.BACKITEM = "12345"
+	if changed := doc.RepairChains(); len(changed) != 1 || two.BACKITEM != "-1" {
+		t.Errorf("RepairChains() was incorrect, got: %v", changed)
+	}
+
+	// Chains on master pages and of inline frames are stories too
+	frame := func(id string, back string, next string) PAGEOBJECT {
+		return PAGEOBJECT{ItemID: id, PTYPE: "4", BACKITEM: back, NEXTITEM: next}
+	}
+	doc.MASTEROBJECT = []PAGEOBJECT{frame("1", "-1", "2"), frame("2", "1", "-1")}
+	doc.FRAMEOBJECT = []PAGEOBJECT{frame("3", "-1", "4"), frame("4", "3", "-1")}
+	if changed := doc.RepairChains(); len(changed) != 0 {
+		t.Errorf("RepairChains() cut the links of master pages and inline frames, got: %v", changed)
+	}
+	if len(doc.Stories()) != 5 || len(doc.StoriesInReadingOrder()) != 3 {
+		t.Errorf("len(doc.Stories()) was incorrect, got: %v, want: %v.", len(doc.Stories()), 5)
+	}
+	if story := doc.GetStory(&doc.FRAMEOBJECT[1]); len(story.Frames) != 2 || story.First() != &doc.FRAMEOBJECT[0] {
+		t.Errorf("story of inline frames was incorrect, got: %v", story.Frames)
+	}
+	if err := doc.UnlinkFrame(&doc.MASTEROBJECT[0]); err != nil || doc.MASTEROBJECT[1].BACKITEM != "-1" {
+		t.Errorf("UnlinkFrame() on a master page was incorrect, got: %v", err)
+	}
+	document.DOCUMENT = doc
+	clone, err := document.Clone()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if inline := clone.DOCUMENT.FRAMEOBJECT; len(inline) != 2 || inline[1].BACKITEM != "3" {
+		t.Errorf("inline frames were not written, got: %+v", inline)
+	}
+}
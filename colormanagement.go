@@ -0,0 +1,409 @@
+package scribus
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultProfileDirs returns the directories in which ICC profiles are usually installed
+func DefaultProfileDirs() []string {
+	dirs := []string{
+		"/usr/share/color/icc",
+		"/usr/local/share/color/icc",
+		"/Library/ColorSync/Profiles",
+		"/System/Library/ColorSync/Profiles",
+		`C:\Windows\System32\spool\drivers\color`,
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs,
+			filepath.Join(home, ".local", "share", "color", "icc"),
+			filepath.Join(home, ".color", "icc"),
+			filepath.Join(home, "Library", "ColorSync", "Profiles"))
+	}
+	return dirs
+}
+
+// FindProfiles loads all ICC profiles (*.icc, *.icm) below dirs; unreadable files are skipped
+func FindProfiles(dirs ...string) []*Profile {
+	var profiles []*Profile
+	for _, dir := range dirs {
+		filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return nil
+			}
+			ext := strings.ToLower(filepath.Ext(path))
+			if ext != ".icc" && ext != ".icm" {
+				return nil
+			}
+			if p, err := LoadProfile(path); err == nil {
+				profiles = append(profiles, p)
+			}
+			return nil
+		})
+	}
+	return profiles
+}
+
+// findProfile returns the profile whose description (which is what Scribus stores
+// in the document) or file name matches name
+func findProfile(profiles []*Profile, name string) *Profile {
+	if name == "" {
+		return nil
+	}
+	for _, p := range profiles {
+		if p.Description == name {
+			return p
+		}
+	}
+	for _, p := range profiles {
+		base := filepath.Base(p.Path)
+		if base == name || strings.TrimSuffix(base, filepath.Ext(base)) == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// ColorManager converts colours using the ICC profiles configured in a document
+type ColorManager struct {
+	RGB      *Profile        // DPIn, used for RGB colours
+	CMYK     *Profile        // DPInCMYK, used for CMYK colours
+	Printer  *Profile        // DPPr, the output device
+	Intent   RenderingIntent // DISc, used for solid colours
+	Profiles []*Profile      // All profiles that were found, used to resolve image profiles
+}
+
+// NewColorManager loads the document's ICC profiles from dirs, or from
+// DefaultProfileDirs if no dirs are given, and returns a ColorManager, error.
+// If colour management is off in the document (HCMS "0"), the ColorManager has
+// no profiles and converts colours naively, like Scribus does then
+func (doc DOCUMENT) NewColorManager(dirs ...string) (*ColorManager, error) {
+	if doc.HCMS == "0" {
+		return &ColorManager{}, nil
+	}
+	if len(dirs) == 0 {
+		dirs = DefaultProfileDirs()
+	}
+	cm := &ColorManager{Profiles: FindProfiles(dirs...)}
+	cm.Intent = RenderingIntent(parseFloat(doc.DISc))
+
+	var missing []string
+	if cm.RGB = findProfile(cm.Profiles, doc.DPIn); cm.RGB == nil {
+		missing = append(missing, doc.DPIn)
+	}
+	if cm.CMYK = findProfile(cm.Profiles, doc.DPInCMYK); cm.CMYK == nil {
+		missing = append(missing, doc.DPInCMYK)
+	}
+	if cm.Printer = findProfile(cm.Profiles, doc.DPPr); cm.Printer == nil {
+		cm.Printer = cm.CMYK
+	}
+	if len(missing) > 0 {
+		return cm, fmt.Errorf("ICC profiles not found: %v", strings.Join(missing, ", "))
+	}
+	return cm, nil
+}
+
+// ImageProfile returns the profile to use for the picture in an image frame,
+// which is its PRFILE if set and the document's RGB profile otherwise
+func (cm *ColorManager) ImageProfile(po PAGEOBJECT) *Profile {
+	if p := findProfile(cm.Profiles, po.PRFILE); p != nil {
+		return p
+	}
+	return cm.RGB
+}
+
+// RGBToCMYK converts an RGB colour (0..1) to CMYK (0..1). Without profiles a
+// naive conversion is used
+func (cm *ColorManager) RGBToCMYK(rgb [3]float64) ([4]float64, error) {
+	var cmyk [4]float64
+	if cm == nil || cm.RGB == nil || cm.CMYK == nil {
+		return naiveRGBToCMYK(rgb), nil
+	}
+	out, err := NewTransform(cm.RGB, cm.CMYK, cm.Intent).Convert(rgb[:])
+	if err != nil {
+		return cmyk, err
+	}
+	copy(cmyk[:], out)
+	return cmyk, nil
+}
+
+// CMYKToRGB converts a CMYK colour (0..1) to RGB (0..1)
+func (cm *ColorManager) CMYKToRGB(cmyk [4]float64) ([3]float64, error) {
+	var rgb [3]float64
+	if cm == nil || cm.RGB == nil || cm.CMYK == nil {
+		return naiveCMYKToRGB(cmyk), nil
+	}
+	out, err := NewTransform(cm.CMYK, cm.RGB, cm.Intent).Convert(cmyk[:])
+	if err != nil {
+		return rgb, err
+	}
+	copy(rgb[:], out)
+	return rgb, nil
+}
+
+// RGBToLab converts an RGB colour (0..1) to D50 Lab
+func (cm *ColorManager) RGBToLab(rgb [3]float64) ([3]float64, error) {
+	if cm == nil || cm.RGB == nil {
+		return sRGBToLab(rgb), nil
+	}
+	return cm.RGB.ToLab(rgb[:], cm.Intent)
+}
+
+// LabToRGB converts a D50 Lab colour to RGB (0..1)
+func (cm *ColorManager) LabToRGB(lab [3]float64) ([3]float64, error) {
+	var rgb [3]float64
+	if cm == nil || cm.RGB == nil {
+		return labToSRGB(lab), nil
+	}
+	out, err := cm.RGB.FromLab(lab, cm.Intent)
+	if err != nil {
+		return rgb, err
+	}
+	copy(rgb[:], out)
+	return rgb, nil
+}
+
+// CMYKToLab converts a CMYK colour (0..1) to D50 Lab
+func (cm *ColorManager) CMYKToLab(cmyk [4]float64) ([3]float64, error) {
+	if cm == nil || cm.CMYK == nil {
+		return sRGBToLab(naiveCMYKToRGB(cmyk)), nil
+	}
+	return cm.CMYK.ToLab(cmyk[:], cm.Intent)
+}
+
+// LabToCMYK converts a D50 Lab colour to CMYK (0..1)
+func (cm *ColorManager) LabToCMYK(lab [3]float64) ([4]float64, error) {
+	var cmyk [4]float64
+	if cm == nil || cm.CMYK == nil {
+		return naiveRGBToCMYK(labToSRGB(lab)), nil
+	}
+	out, err := cm.CMYK.FromLab(lab, cm.Intent)
+	if err != nil {
+		return cmyk, err
+	}
+	copy(cmyk[:], out)
+	return cmyk, nil
+}
+
+// ColorModel returns "RGB", "CMYK" or "Lab" depending on how the COLOR is defined
+func (c COLOR) ColorModel() string {
+	switch {
+	case c.SPACE != "":
+		return c.SPACE
+	case c.CMYK != "":
+		return "CMYK"
+	}
+	return "RGB"
+}
+
+// RGBValues returns the components (0..1) of an RGB COLOR, returns error
+func (c COLOR) RGBValues() ([3]float64, error) {
+	var rgb [3]float64
+	if c.SPACE == "RGB" {
+		for i, s := range []string{c.R, c.G, c.B} {
+			rgb[i] = clamp01(parseFloat(s) / 255)
+		}
+		return rgb, nil
+	}
+	if c.ColorModel() != "RGB" {
+		return rgb, fmt.Errorf("colour %v is not an RGB colour", c.NAME)
+	}
+	v, err := parseHexColor(c.RGB, 3)
+	copy(rgb[:], v)
+	return rgb, err
+}
+
+// CMYKValues returns the components (0..1) of a CMYK COLOR, returns error
+func (c COLOR) CMYKValues() ([4]float64, error) {
+	var cmyk [4]float64
+	if c.SPACE == "CMYK" {
+		for i, s := range []string{c.C, c.M, c.Y, c.K} {
+			cmyk[i] = clamp01(parseFloat(s) / 100)
+		}
+		return cmyk, nil
+	}
+	if c.ColorModel() != "CMYK" {
+		return cmyk, fmt.Errorf("colour %v is not a CMYK colour", c.NAME)
+	}
+	v, err := parseHexColor(c.CMYK, 4)
+	copy(cmyk[:], v)
+	return cmyk, err
+}
+
+// LabValues returns the components of a Lab COLOR, returns error
+func (c COLOR) LabValues() ([3]float64, error) {
+	if c.SPACE != "Lab" {
+		return [3]float64{}, fmt.Errorf("colour %v is not a Lab colour", c.NAME)
+	}
+	return [3]float64{parseFloat(c.L), parseFloat(c.A), parseFloat(c.B)}, nil
+}
+
+// usesSpace tells whether the COLOR is written with the SPACE notation of
+// Scribus 1.5 rather than with hex strings
+func (c COLOR) usesSpace() bool {
+	return c.SPACE != ""
+}
+
+// SetRGB redefines the COLOR as an RGB colour (0..1), keeping its notation
+func (c *COLOR) SetRGB(rgb [3]float64) {
+	spaced := c.usesSpace()
+	c.clearValues()
+	if spaced {
+		c.SPACE = "RGB"
+		c.R = strconv.Itoa(int(math.Round(clamp01(rgb[0]) * 255)))
+		c.G = strconv.Itoa(int(math.Round(clamp01(rgb[1]) * 255)))
+		c.B = strconv.Itoa(int(math.Round(clamp01(rgb[2]) * 255)))
+		return
+	}
+	c.RGB = formatHexColor(rgb[:])
+}
+
+// SetCMYK redefines the COLOR as a CMYK colour (0..1), keeping its notation
+func (c *COLOR) SetCMYK(cmyk [4]float64) {
+	spaced := c.usesSpace()
+	c.clearValues()
+	if spaced {
+		c.SPACE = "CMYK"
+		c.C = formatFloat(clamp01(cmyk[0]) * 100)
+		c.M = formatFloat(clamp01(cmyk[1]) * 100)
+		c.Y = formatFloat(clamp01(cmyk[2]) * 100)
+		c.K = formatFloat(clamp01(cmyk[3]) * 100)
+		return
+	}
+	c.CMYK = formatHexColor(cmyk[:])
+}
+
+// SetLab redefines the COLOR as a Lab colour; Lab always uses the SPACE notation
+func (c *COLOR) SetLab(lab [3]float64) {
+	c.clearValues()
+	c.SPACE = "Lab"
+	c.L = formatFloat(lab[0])
+	c.A = formatFloat(lab[1])
+	c.B = formatFloat(lab[2])
+}
+
+func (c *COLOR) clearValues() {
+	c.SPACE, c.CMYK, c.RGB = "", "", ""
+	c.C, c.M, c.Y, c.K = "", "", "", ""
+	c.R, c.G, c.B, c.L, c.A = "", "", "", "", ""
+}
+
+// ConvertRGBColorsToCMYK converts every RGB and Lab COLOR of the document to CMYK
+// using cm (which may be nil for a naive conversion) and returns the names of the
+// converted colours, error
+func (doc *DOCUMENT) ConvertRGBColorsToCMYK(cm *ColorManager) ([]string, error) {
+	var converted []string
+	for i := range doc.COLOR {
+		c := &doc.COLOR[i]
+		if c.Register == "1" {
+			continue
+		}
+		var cmyk [4]float64
+		var err error
+		switch c.ColorModel() {
+		case "RGB":
+			var rgb [3]float64
+			if rgb, err = c.RGBValues(); err == nil {
+				cmyk, err = cm.RGBToCMYK(rgb)
+			}
+		case "Lab":
+			var lab [3]float64
+			if lab, err = c.LabValues(); err == nil {
+				cmyk, err = cm.LabToCMYK(lab)
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return converted, fmt.Errorf("colour %v: %v", c.NAME, err)
+		}
+		c.SetCMYK(cmyk)
+		converted = append(converted, c.NAME)
+	}
+	return converted, nil
+}
+
+// parseHexColor parses "#rrggbb" or "#ccmmyykk" into n components (0..1)
+func parseHexColor(s string, n int) ([]float64, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 2*n {
+		return nil, errors.New("invalid colour value " + strconv.Quote(s))
+	}
+	v := make([]float64, n)
+	for i := range v {
+		x, err := strconv.ParseUint(s[2*i:2*i+2], 16, 8)
+		if err != nil {
+			return nil, err
+		}
+		v[i] = float64(x) / 255
+	}
+	return v, nil
+}
+
+func formatHexColor(v []float64) string {
+	s := "#"
+	for _, x := range v {
+		s += fmt.Sprintf("%02x", int(math.Round(clamp01(x)*255)))
+	}
+	return s
+}
+
+func naiveRGBToCMYK(rgb [3]float64) [4]float64 {
+	k := 1 - math.Max(rgb[0], math.Max(rgb[1], rgb[2]))
+	if k >= 1 {
+		return [4]float64{0, 0, 0, 1}
+	}
+	return [4]float64{
+		(1 - rgb[0] - k) / (1 - k),
+		(1 - rgb[1] - k) / (1 - k),
+		(1 - rgb[2] - k) / (1 - k),
+		k,
+	}
+}
+
+func naiveCMYKToRGB(cmyk [4]float64) [3]float64 {
+	return [3]float64{
+		(1 - cmyk[0]) * (1 - cmyk[3]),
+		(1 - cmyk[1]) * (1 - cmyk[3]),
+		(1 - cmyk[2]) * (1 - cmyk[3]),
+	}
+}
+
+// sRGB primaries adapted to D50 (Bradford), as in the sRGB ICC profile
+var sRGBToXYZD50 = [3][3]float64{
+	{0.4360747, 0.3850649, 0.1430804},
+	{0.2225045, 0.7168786, 0.0606169},
+	{0.0139322, 0.0971045, 0.7141733},
+}
+
+func sRGBToLab(rgb [3]float64) [3]float64 {
+	var lin [3]float64
+	for i, v := range rgb {
+		if v <= 0.04045 {
+			lin[i] = v / 12.92
+		} else {
+			lin[i] = math.Pow((v+0.055)/1.055, 2.4)
+		}
+	}
+	return XYZToLab(mulMatrix(sRGBToXYZD50, lin))
+}
+
+func labToSRGB(lab [3]float64) [3]float64 {
+	inv, _ := invertMatrix(sRGBToXYZD50)
+	lin := mulMatrix(inv, LabToXYZ(lab))
+	var rgb [3]float64
+	for i, v := range lin {
+		v = clamp01(v)
+		if v <= 0.0031308 {
+			rgb[i] = v * 12.92
+		} else {
+			rgb[i] = 1.055*math.Pow(v, 1/2.4) - 0.055
+		}
+	}
+	return rgb
+}
@@ -0,0 +1,170 @@
+package scribus
+
+import (
+	"sort"
+)
+
+// ColorReference describes one place where a COLOR is used
+type ColorReference struct {
+	Color     string // NAME of the COLOR
+	Kind      string // e.g., "PAGEOBJECT", "STYLE", "CHARSTYLE", "TableStyle", "CellStyle", "Gradient", "MultiLine", "Pattern", "DOCUMENT"
+	Name      string // Name of the item or style, or the ItemID of unnamed items
+	Attribute string // Attribute that refers to the colour
+}
+
+// essentialColors are never purged, Scribus needs them
+var essentialColors = []string{"Black", "White", "Registration"}
+
+// ColorUsage walks all page and master page objects and inline frames (including groups),
+// story texts (also those of table cells), paragraph, character, table and cell styles,
+// gradients, line styles, the items of patterns and the document defaults and returns
+// every colour reference keyed by colour name. References to colours that are not defined
+// in the document are included too. Layers are not walked because their LAYERC is a plain
+// "#rrggbb" value rather than a COLOR
+func (doc DOCUMENT) ColorUsage() map[string][]ColorReference {
+	usage := map[string][]ColorReference{}
+	add := func(color, kind, name, attribute string) {
+		if color == "" || color == "None" {
+			return
+		}
+		usage[color] = append(usage[color], ColorReference{Color: color, Kind: kind, Name: name, Attribute: attribute})
+	}
+
+	for attribute, color := range map[string]string{
+		"PEN": doc.PEN, "BRUSH": doc.BRUSH, "PENLINE": doc.PENLINE, "PENTEXT": doc.PENTEXT,
+		"StrokeText": doc.StrokeText, "TextBackGround": doc.TextBackGround, "TextLineColor": doc.TextLineColor,
+		"CPICT": doc.CPICT, "CSPICT": doc.CSPICT,
+		"calligraphicPenFillColor": doc.CalligraphicPenFillColor, "calligraphicPenLineColor": doc.CalligraphicPenLineColor,
+	} {
+		add(color, "DOCUMENT", "", attribute)
+	}
+
+	for _, g := range doc.Gradient {
+		for _, stop := range g.CSTOP {
+			add(stop.NAME, "Gradient", g.Name, "CSTOP")
+		}
+	}
+	for _, l := range doc.MultiLine {
+		for _, line := range l.SubLine {
+			add(line.Color, "MultiLine", l.Name, "SubLine")
+		}
+	}
+	for _, p := range doc.Pattern {
+		walkPageObjects(p.PatternItem, func(po *PAGEOBJECT) {
+			add(po.PCOLOR, "Pattern", p.Name, "PCOLOR")
+			add(po.PCOLOR2, "Pattern", p.Name, "PCOLOR2")
+		})
+	}
+	for _, s := range doc.STYLE {
+		add(s.BCOLOR, "STYLE", s.NAME, "BCOLOR")
+		add(s.FCOLOR, "STYLE", s.NAME, "FCOLOR")
+	}
+	for _, s := range doc.CHARSTYLE {
+		add(s.FCOLOR, "CHARSTYLE", s.CNAME, "FCOLOR")
+		add(s.SCOLOR, "CHARSTYLE", s.CNAME, "SCOLOR")
+		add(s.BGCOLOR, "CHARSTYLE", s.CNAME, "BGCOLOR")
+	}
+	addBorders := func(kind, name string, borders map[string]**TableBorder) {
+		for attribute, border := range borders {
+			if *border != nil {
+				for _, line := range (*border).TableBorderLine {
+					add(line.Color, kind, name, attribute)
+				}
+			}
+		}
+	}
+	for i := range doc.TableStyle {
+		s := &doc.TableStyle[i]
+		add(s.FillColor, "TableStyle", s.NAME, "FillColor")
+		addBorders("TableStyle", s.NAME, s.Borders())
+	}
+	for i := range doc.CellStyle {
+		s := &doc.CellStyle[i]
+		add(s.FillColor, "CellStyle", s.NAME, "FillColor")
+		addBorders("CellStyle", s.NAME, s.Borders())
+	}
+
+	doc.walkAllPageObjects(func(po *PAGEOBJECT) {
+		name := po.ANNAME
+		if name == "" {
+			name = po.ItemID
+		}
+		add(po.PCOLOR, "PAGEOBJECT", name, "PCOLOR")
+		add(po.PCOLOR2, "PAGEOBJECT", name, "PCOLOR2")
+		for _, stop := range po.CSTOP {
+			add(stop.NAME, "PAGEOBJECT", name, "CSTOP")
+		}
+		for _, stop := range po.SCSTOP {
+			add(stop.NAME, "PAGEOBJECT", name, "S_CSTOP")
+		}
+		storyColors := func(st *StoryText, prefix string) {
+			storyRefs(st, func(kind resourceKind, attribute string, color *string) {
+				if kind == colorResource {
+					add(*color, "PAGEOBJECT", name, prefix+attribute)
+				}
+			})
+		}
+		if po.TableData != nil {
+			add(po.TableData.FillColor, "PAGEOBJECT", name, "TableData.FillColor")
+			addBorders("PAGEOBJECT", name, po.TableData.Borders())
+			for i := range po.TableData.Cell {
+				add(po.TableData.Cell[i].FillColor, "PAGEOBJECT", name, "Cell.FillColor")
+				addBorders("PAGEOBJECT", name, po.TableData.Cell[i].Borders())
+				storyColors(&po.TableData.Cell[i].StoryText, "Cell.")
+			}
+		}
+		storyColors(&po.StoryText, "")
+	})
+
+	for color := range usage {
+		refs := usage[color]
+		sort.SliceStable(refs, func(i, j int) bool {
+			if refs[i].Kind != refs[j].Kind {
+				return refs[i].Kind < refs[j].Kind
+			}
+			if refs[i].Name != refs[j].Name {
+				return refs[i].Name < refs[j].Name
+			}
+			return refs[i].Attribute < refs[j].Attribute
+		})
+	}
+	return usage
+}
+
+// UnusedColors returns the names of the COLORs that are not referenced anywhere
+func (doc DOCUMENT) UnusedColors() []string {
+	usage := doc.ColorUsage()
+	var unused []string
+	for _, c := range doc.COLOR {
+		if _, ok := usage[c.NAME]; !ok {
+			unused = append(unused, c.NAME)
+		}
+	}
+	return unused
+}
+
+// PurgeUnusedColors removes all unused COLORs except Black, White, Registration,
+// registration colours and the colours listed in required (e.g., spot colours that
+// the print shop needs), and returns the names of the removed colours
+func (doc *DOCUMENT) PurgeUnusedColors(required ...string) []string {
+	keep := map[string]bool{}
+	for _, name := range essentialColors {
+		keep[name] = true
+	}
+	for _, name := range required {
+		keep[name] = true
+	}
+	usage := doc.ColorUsage()
+
+	var removed []string
+	colors := doc.COLOR[:0]
+	for _, c := range doc.COLOR {
+		if _, used := usage[c.NAME]; used || keep[c.NAME] || c.Register == "1" {
+			colors = append(colors, c)
+			continue
+		}
+		removed = append(removed, c.NAME)
+	}
+	doc.COLOR = colors
+	return removed
+}
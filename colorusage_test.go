@@ -0,0 +1,45 @@
+package scribus
+
+import (
+	"testing"
+)
+
+func TestPurgeUnusedColors(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := &document.DOCUMENT
+	doc.COLOR = append(doc.COLOR,
+		COLOR{NAME: "Fill", RGB: "#ff0000"},
+		COLOR{NAME: "Unused", RGB: "#00ff00"},
+		COLOR{NAME: "Varnish", CMYK: "#00000000", Spot: "1"},
+		COLOR{NAME: "Outline", RGB: "#0000ff"},
+		COLOR{NAME: "Cell Text", RGB: "#ffff00"})
+	doc.PAGEOBJECT[0].PCOLOR = "Fill"
+	doc.MASTEROBJECT = append(doc.MASTEROBJECT, PAGEOBJECT{ItemID: "1", PCOLOR2: "Outline"})
+	var cell Paragraph
+	cell.AddRun("Total", ITEXT{FCOLOR: "Cell Text"})
+	if _, err := doc.AddRichTable(0, 0, 0, [][][]Paragraph{{{cell}}}, 100, 20); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	usage := doc.ColorUsage()
+	if len(usage["Fill"]) != 1 || usage["Fill"][0].Name != "56472688" || usage["Fill"][0].Attribute != "PCOLOR" {
+		t.Errorf("usage[\"Fill\"] was incorrect, got: %v", usage["Fill"])
+	}
+	if len(usage["Outline"]) != 1 || usage["Outline"][0].Attribute != "PCOLOR2" {
+		t.Errorf("usage[\"Outline\"] of the master page object was incorrect, got: %v", usage["Outline"])
+	}
+	if len(usage["Cell Text"]) != 1 || usage["Cell Text"][0].Attribute != "Cell.ITEXT.FCOLOR" {
+		t.Errorf("usage[\"Cell Text\"] of the table cell was incorrect, got: %v", usage["Cell Text"])
+	}
+
+	removed := doc.PurgeUnusedColors("Varnish")
+	if len(removed) != 1 || removed[0] != "Unused" {
+		t.Errorf("removed was incorrect, got: %v, want: %v.", removed, []string{"Unused"})
+	}
+	if len(doc.COLOR) != 7 {
+		t.Errorf("len(doc.COLOR) was incorrect, got: %v, want: %v.", len(doc.COLOR), 7)
+	}
+}
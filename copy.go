@@ -0,0 +1,477 @@
+package scribus
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+)
+
+// ConflictResolution decides what happens when a colour, style, gradient, line style or pattern
+// that is copied along with items has the name of a different one in the target document
+type ConflictResolution int
+
+const (
+	KeepExisting    ConflictResolution = iota // The copies use the one of the target document
+	RenameCopied                              // The copied one is added under a new name, e.g., "Accent 2"
+	ReplaceExisting                           // The copied one replaces the one of the target document
+)
+
+// CopyOptions configures CopyPageObject and MovePageObjectFrom
+type CopyOptions struct {
+	Conflicts ConflictResolution
+	Layer     string // Name of the layer of the copies, otherwise the layer with the name of the original layer or the active layer
+}
+
+// resourceKind is a kind of named definition that items refer to
+type resourceKind int
+
+const (
+	colorResource resourceKind = iota
+	paragraphStyleResource
+	charStyleResource
+	gradientResource
+	lineStyleResource
+	patternResource
+	tableStyleResource
+	cellStyleResource
+)
+
+// resource returns a pointer to the definition of the kind with the name, or nil
+func (doc *DOCUMENT) resource(kind resourceKind, name string) interface{} {
+	switch kind {
+	case colorResource:
+		for i := range doc.COLOR {
+			if doc.COLOR[i].NAME == name {
+				return &doc.COLOR[i]
+			}
+		}
+	case paragraphStyleResource:
+		if s := doc.GetParagraphStyle(name); s != nil {
+			return s
+		}
+	case charStyleResource:
+		if s := doc.GetCharStyle(name); s != nil {
+			return s
+		}
+	case gradientResource:
+		for i := range doc.Gradient {
+			if doc.Gradient[i].Name == name {
+				return &doc.Gradient[i]
+			}
+		}
+	case lineStyleResource:
+		for i := range doc.MultiLine {
+			if doc.MultiLine[i].Name == name {
+				return &doc.MultiLine[i]
+			}
+		}
+	case patternResource:
+		for i := range doc.Pattern {
+			if doc.Pattern[i].Name == name {
+				return &doc.Pattern[i]
+			}
+		}
+	case tableStyleResource:
+		if s := doc.GetTableStyle(name); s != nil {
+			return s
+		}
+	case cellStyleResource:
+		if s := doc.GetCellStyle(name); s != nil {
+			return s
+		}
+	}
+	return nil
+}
+
+// newResource returns a pointer to an empty definition of the kind
+func newResource(kind resourceKind) interface{} {
+	switch kind {
+	case colorResource:
+		return &COLOR{}
+	case paragraphStyleResource:
+		return &STYLE{}
+	case charStyleResource:
+		return &CHARSTYLE{}
+	case gradientResource:
+		return &Gradient{}
+	case lineStyleResource:
+		return &MultiLine{}
+	case patternResource:
+		return &Pattern{}
+	case tableStyleResource:
+		return &TableStyle{}
+	}
+	return &CellStyle{}
+}
+
+// resourceName returns a pointer to the name of the definition
+func resourceName(def interface{}) *string {
+	switch d := def.(type) {
+	case *COLOR:
+		return &d.NAME
+	case *STYLE:
+		return &d.NAME
+	case *CHARSTYLE:
+		return &d.CNAME
+	case *Gradient:
+		return &d.Name
+	case *MultiLine:
+		return &d.Name
+	case *Pattern:
+		return &d.Name
+	case *TableStyle:
+		return &d.NAME
+	case *CellStyle:
+		return &d.NAME
+	}
+	return nil
+}
+
+// isDefaultResource tells whether the definition is one every document has: the colours Black,
+// White and Registration and the default styles
+func isDefaultResource(def interface{}) bool {
+	switch d := def.(type) {
+	case *COLOR:
+		for _, name := range essentialColors {
+			if d.NAME == name {
+				return true
+			}
+		}
+	case *STYLE:
+		return d.DefaultStyle == "1"
+	case *CHARSTYLE:
+		return d.DefaultStyle == "1"
+	case *TableStyle:
+		return d.DefaultStyle == "1"
+	case *CellStyle:
+		return d.DefaultStyle == "1"
+	}
+	return false
+}
+
+// setResource replaces the definition with the same name as def, or adds def
+func (doc *DOCUMENT) setResource(def interface{}) {
+	switch d := def.(type) {
+	case *COLOR:
+		if c, ok := doc.resource(colorResource, d.NAME).(*COLOR); ok {
+			*c = *d
+		} else {
+			doc.COLOR = append(doc.COLOR, *d)
+		}
+	case *STYLE:
+		if s := doc.GetParagraphStyle(d.NAME); s != nil {
+			*s = *d
+		} else {
+			doc.STYLE = append(doc.STYLE, *d)
+		}
+	case *CHARSTYLE:
+		if s := doc.GetCharStyle(d.CNAME); s != nil {
+			*s = *d
+		} else {
+			doc.CHARSTYLE = append(doc.CHARSTYLE, *d)
+		}
+	case *Gradient:
+		if g, ok := doc.resource(gradientResource, d.Name).(*Gradient); ok {
+			*g = *d
+		} else {
+			doc.Gradient = append(doc.Gradient, *d)
+		}
+	case *MultiLine:
+		if l, ok := doc.resource(lineStyleResource, d.Name).(*MultiLine); ok {
+			*l = *d
+		} else {
+			doc.MultiLine = append(doc.MultiLine, *d)
+		}
+	case *Pattern:
+		if p, ok := doc.resource(patternResource, d.Name).(*Pattern); ok {
+			*p = *d
+		} else {
+			doc.Pattern = append(doc.Pattern, *d)
+		}
+	case *TableStyle:
+		if s := doc.GetTableStyle(d.NAME); s != nil {
+			*s = *d
+		} else {
+			doc.TableStyle = append(doc.TableStyle, *d)
+		}
+	case *CellStyle:
+		if s := doc.GetCellStyle(d.NAME); s != nil {
+			*s = *d
+		} else {
+			doc.CellStyle = append(doc.CellStyle, *d)
+		}
+	}
+}
+
+// resourceRefs calls fn with a pointer to every name of another definition the definition
+// refers to, e.g., the colours of a gradient or the parent of a style
+func resourceRefs(def interface{}, fn func(kind resourceKind, name *string)) {
+	switch d := def.(type) {
+	case *STYLE:
+		fn(paragraphStyleResource, &d.PARENT)
+		fn(colorResource, &d.FCOLOR)
+		fn(colorResource, &d.BCOLOR)
+	case *CHARSTYLE:
+		fn(colorResource, &d.FCOLOR)
+		fn(colorResource, &d.SCOLOR)
+		fn(colorResource, &d.BGCOLOR)
+	case *Gradient:
+		for i := range d.CSTOP {
+			fn(colorResource, &d.CSTOP[i].NAME)
+		}
+	case *MultiLine:
+		for i := range d.SubLine {
+			fn(colorResource, &d.SubLine[i].Color)
+		}
+	case *Pattern:
+		for i := range d.PatternItem {
+			itemResources(&d.PatternItem[i], fn)
+		}
+	case *TableStyle:
+		fn(tableStyleResource, &d.PARENT)
+		fn(colorResource, &d.FillColor)
+		borderResources(d.Borders(), fn)
+	case *CellStyle:
+		fn(cellStyleResource, &d.PARENT)
+		fn(colorResource, &d.FillColor)
+		borderResources(d.Borders(), fn)
+	}
+}
+
+// borderResources calls fn with a pointer to the colour of every line of the borders
+func borderResources(borders map[string]**TableBorder, fn func(kind resourceKind, name *string)) {
+	for _, border := range borders {
+		if *border != nil {
+			for i := range (*border).TableBorderLine {
+				fn(colorResource, &(*border).TableBorderLine[i].Color)
+			}
+		}
+	}
+}
+
+// itemResources calls fn with a pointer to every name of a definition the item (including
+// its story text, table and the items inside it) refers to
+func itemResources(po *PAGEOBJECT, fn func(kind resourceKind, name *string)) {
+	fn(colorResource, &po.PCOLOR)
+	fn(colorResource, &po.PCOLOR2)
+	for i := range po.CSTOP {
+		fn(colorResource, &po.CSTOP[i].NAME)
+	}
+	for i := range po.SCSTOP {
+		fn(colorResource, &po.SCSTOP[i].NAME)
+	}
+	fn(gradientResource, &po.GRNAME)
+	fn(lineStyleResource, &po.NAMEDLST)
+	fn(patternResource, &po.Pattern)
+	fn(patternResource, &po.PatternStroke)
+	fn(paragraphStyleResource, &po.PSTYLE)
+	storyResources(&po.StoryText, fn)
+	if t := po.TableData; t != nil {
+		fn(tableStyleResource, &t.Style)
+		fn(colorResource, &t.FillColor)
+		borderResources(t.Borders(), fn)
+		for i := range t.Cell {
+			c := &t.Cell[i]
+			fn(cellStyleResource, &c.Style)
+			fn(colorResource, &c.FillColor)
+			borderResources(c.Borders(), fn)
+			storyResources(&c.StoryText, fn)
+		}
+	}
+	for i := range po.PAGEOBJECT {
+		itemResources(&po.PAGEOBJECT[i], fn)
+	}
+}
+
+// storyResources calls fn with a pointer to every name of a style or colour the story text
+// refers to
+func storyResources(st *StoryText, fn func(kind resourceKind, name *string)) {
+	storyRefs(st, func(kind resourceKind, attribute string, name *string) { fn(kind, name) })
+}
+
+// storyRefs calls fn with a pointer to every name of a style or colour the story text refers
+// to and the attribute that holds it, e.g., "ITEXT.FCOLOR". It is shared by copying and the
+// colour usage, so that both know the same references
+func storyRefs(st *StoryText, fn func(kind resourceKind, attribute string, name *string)) {
+	defaultStyle := func(d *DefaultStyle) {
+		fn(paragraphStyleResource, "DefaultStyle.PARENT", &d.PARENT)
+		fn(charStyleResource, "DefaultStyle.CPARENT", &d.CPARENT)
+		fn(colorResource, "DefaultStyle.FCOLOR", &d.FCOLOR)
+	}
+	itext := func(t *ITEXT) {
+		fn(charStyleResource, "ITEXT.CPARENT", &t.CPARENT)
+		fn(colorResource, "ITEXT.FCOLOR", &t.FCOLOR)
+		fn(colorResource, "ITEXT.SCOLOR", &t.SCOLOR)
+		fn(colorResource, "ITEXT.BGCOLOR", &t.BGCOLOR)
+	}
+	para := func(p *Para) {
+		fn(paragraphStyleResource, "para.PARENT", &p.PARENT)
+		fn(charStyleResource, "para.ParagraphEffectCharStyle", &p.ParagraphEffectCharStyle)
+	}
+	defaultStyle(&st.DefaultStyle)
+	for i := range st.ITEXT {
+		itext(&st.ITEXT[i])
+	}
+	for i := range st.Para {
+		para(&st.Para[i])
+	}
+	fn(paragraphStyleResource, "trail.PARENT", &st.Trail.PARENT)
+	for i := range st.StoryTextSpan {
+		span := &st.StoryTextSpan[i]
+		defaultStyle(&span.DefaultStyle)
+		itext(&span.ITEXT)
+		para(&span.Para)
+		fn(paragraphStyleResource, "trail.PARENT", &span.Trail.PARENT)
+	}
+}
+
+// resourceCopier carries the definitions that copied items refer to over from src to dst
+type resourceCopier struct {
+	src       *DOCUMENT
+	dst       *DOCUMENT
+	conflicts ConflictResolution
+	names     map[resourceKind]map[string]string // Names in dst by names in src
+	err       error
+}
+
+// resolve copies the definition of the kind with the name from src to dst if needed and
+// changes the name to the one in dst. Names that src does not define are left as they are
+func (c *resourceCopier) resolve(kind resourceKind, name *string) {
+	if *name == "" || *name == "None" {
+		return
+	}
+	if c.names[kind] == nil {
+		c.names[kind] = map[string]string{}
+	}
+	if target, ok := c.names[kind][*name]; ok {
+		*name = target
+		return
+	}
+	def := c.src.resource(kind, *name)
+	if def == nil {
+		c.names[kind][*name] = *name
+		return
+	}
+	target := *name
+	if existing := c.dst.resource(kind, target); existing != nil {
+		if isDefaultResource(existing) || c.conflicts == KeepExisting || sameXML(existing, def) {
+			c.names[kind][*name] = target
+			return
+		}
+		if c.conflicts == RenameCopied {
+			for n := 2; c.dst.resource(kind, target) != nil; n++ {
+				target = *name + " " + strconv.Itoa(n)
+			}
+		}
+	}
+	// The name is known before the references are resolved, so that cycles end here
+	c.names[kind][*name] = target
+	copied := newResource(kind)
+	if err := copyXML(copied, def); err != nil {
+		c.err = err
+		return
+	}
+	*resourceName(copied) = target
+	resourceRefs(copied, c.resolve)
+	c.dst.setResource(copied)
+	*name = target
+}
+
+// copyXML copies src into dst, which must point to an empty value of the same type, by
+// marshalling it, so that they share nothing
+func copyXML(dst interface{}, src interface{}) error {
+	data, err := xml.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(data, dst)
+}
+
+// sameXML tells whether a and b are marshalled to the same XML
+func sameXML(a interface{}, b interface{}) bool {
+	x, errX := xml.Marshal(a)
+	y, errY := xml.Marshal(b)
+	return errX == nil && errY == nil && bytes.Equal(x, y)
+}
+
+// copyLayer returns the NUMMER of the layer for items that are copied from a layer of src
+func (doc DOCUMENT) copyLayer(src DOCUMENT, number string, name string) (string, error) {
+	if name != "" {
+		l := doc.GetLayerByName(name)
+		if l == nil {
+			return "", fmt.Errorf("layer %v not found", name)
+		}
+		return l.NUMMER, nil
+	}
+	if n, err := strconv.Atoi(number); err == nil {
+		if l := src.GetLayerByNumber(n); l != nil {
+			if own := doc.GetLayerByName(l.NAME); own != nil {
+				return own.NUMMER, nil
+			}
+		}
+	}
+	return doc.ALAYER, nil
+}
+
+// CopyPageObject copies the item (with the items inside it if it is a group) of the document
+// src onto the page with the index page, with its top left corner at x, y relative to the
+// page, and returns a pointer to the copy, error. The colours, paragraph, character, table and
+// cell styles, gradients, line styles and patterns the item refers to are copied along unless
+// the document has the same ones; options.Conflicts decides about different ones with the same
+// name. The copies get new ItemIDs; links to text frames that are not copied are dropped
+func (doc *DOCUMENT) CopyPageObject(src *DOCUMENT, po *PAGEOBJECT, page int, x float64, y float64, options CopyOptions) (*PAGEOBJECT, error) {
+	if page < 0 || page >= len(doc.PAGE) {
+		return nil, fmt.Errorf("page %v not found, the document has %v pages", page, len(doc.PAGE))
+	}
+	layer, err := doc.copyLayer(*src, po.LAYER, options.Layer)
+	if err != nil {
+		return nil, err
+	}
+	clone, err := po.Clone()
+	if err != nil {
+		return nil, err
+	}
+	copier := resourceCopier{src: src, dst: doc, conflicts: options.Conflicts, names: map[resourceKind]map[string]string{}}
+	itemResources(&clone, copier.resolve)
+	if copier.err != nil {
+		return nil, copier.err
+	}
+
+	copies := []PAGEOBJECT{clone}
+	next := doc.maxItemID() + 1
+	renumberItems(copies, &next)
+	p := doc.PAGE[page]
+	c := &copies[0]
+	shiftPageObject(c, parseFloat(p.PAGEXPOS)+x-parseFloat(c.XPOS), parseFloat(p.PAGEYPOS)+y-parseFloat(c.YPOS))
+	// The copy of an item of a group becomes an item of the page
+	c.GXpos, c.GYpos = c.XPOS, c.YPOS
+	c.GWidth, c.GHeight = "0", "0"
+	setPageObjectLayer(c, layer)
+	c.OwnPage = p.NUM
+	walkPageObjects(c.PAGEOBJECT, func(child *PAGEOBJECT) {
+		child.OwnPage = p.NUM
+	})
+	doc.PAGEOBJECT = append(doc.PAGEOBJECT, *c)
+	return &doc.PAGEOBJECT[len(doc.PAGEOBJECT)-1], nil
+}
+
+// MovePageObjectFrom moves the item from the document src (which may be the document itself)
+// onto the page with the index page at x, y (see CopyPageObject) and returns a pointer to it,
+// error. The text frames of src that were linked to it are unlinked
+func (doc *DOCUMENT) MovePageObjectFrom(src *DOCUMENT, po *PAGEOBJECT, page int, x float64, y float64, options CopyOptions) (*PAGEOBJECT, error) {
+	id := po.ItemID
+	if parent, _ := src.findItem(id); parent == nil {
+		return nil, fmt.Errorf("item %v not found", id)
+	}
+	moved, err := doc.CopyPageObject(src, po, page, x, y, options)
+	if err != nil {
+		return nil, err
+	}
+	movedID := moved.ItemID
+	parent, index := src.findItem(id)
+	*parent = append((*parent)[:index], (*parent)[index+1:]...)
+	src.RepairChains()
+	parent, index = doc.findItem(movedID)
+	return &(*parent)[index], nil
+}
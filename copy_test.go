@@ -0,0 +1,102 @@
+package scribus
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestCopyPageObject(t *testing.T) {
+	open := func() *DOCUMENT {
+		t.Helper()
+		document, err := NewScribusDocumentFromFile("Document-1.sla")
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		return &document.DOCUMENT
+	}
+	src := open()
+	src.COLOR = append(src.COLOR, COLOR{NAME: "Accent", CMYK: "#00800000"})
+	src.EnsureParagraphStyle("Body").FCOLOR = "Accent"
+	src.MultiLine = []MultiLine{{Name: "Double", SubLine: []SubLine{{Color: "Accent", Width: "1"}, {Color: "Black", Width: "3"}}}}
+	a := &src.PAGEOBJECT[0]
+	a.PCOLOR, a.NAMEDLST = "Accent", "Double"
+	a.StoryText.Para[0].PARENT = "Body"
+	// The target documents have a different colour with the same name
+	target := func() *DOCUMENT {
+		doc := open()
+		doc.COLOR = append(doc.COLOR, COLOR{NAME: "Accent", CMYK: "#ff000000"})
+		return doc
+	}
+
+	dst := target()
+	next := dst.maxItemID() + 1
+	copied, err := dst.CopyPageObject(src, a, 0, 10, 20, CopyOptions{})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if copied.XPOS != "110" || copied.YPOS != "40" || copied.GXpos != "110" || copied.ItemID != strconv.Itoa(next) || len(dst.PAGEOBJECT) != 5 {
+		t.Errorf("CopyPageObject() was incorrect, got: %v at %v, %v", copied.ItemID, copied.XPOS, copied.YPOS)
+	}
+	if copied.PCOLOR != "Accent" || len(dst.COLOR) != 4 || dst.COLOR[3].CMYK != "#ff000000" {
+		t.Errorf("CopyPageObject() did not keep the existing colour, got: %+v", dst.COLOR)
+	}
+	if s := dst.GetParagraphStyle("Body"); s == nil || s.FCOLOR != "Accent" || len(dst.STYLE) != 2 {
+		t.Errorf("CopyPageObject() did not copy the paragraph style, got: %+v", dst.STYLE)
+	}
+	if len(dst.MultiLine) != 1 || dst.MultiLine[0].Name != "Double" || len(dst.MultiLine[0].SubLine) != 2 {
+		t.Errorf("CopyPageObject() did not copy the line style, got: %+v", dst.MultiLine)
+	}
+	if a.XPOS != "140" || src.MultiLine[0].SubLine[0].Color != "Accent" {
+		t.Errorf("CopyPageObject() changed the original")
+	}
+
+	dst = target()
+	body := dst.EnsureParagraphStyle("Body")
+	body.ALIGN = "1"
+	copied, err = dst.CopyPageObject(src, a, 0, 0, 0, CopyOptions{Conflicts: RenameCopied})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if copied.PCOLOR != "Accent 2" || len(dst.COLOR) != 5 || dst.COLOR[4].NAME != "Accent 2" || dst.COLOR[4].CMYK != "#00800000" {
+		t.Errorf("CopyPageObject() did not rename the colour, got: %v, %+v", copied.PCOLOR, dst.COLOR)
+	}
+	if s := dst.GetParagraphStyle("Body 2"); s == nil || s.FCOLOR != "Accent 2" || copied.StoryText.Para[0].PARENT != "Body 2" {
+		t.Errorf("CopyPageObject() did not rename the paragraph style, got: %+v", dst.STYLE)
+	}
+	if dst.MultiLine[0].SubLine[0].Color != "Accent 2" || dst.MultiLine[0].SubLine[1].Color != "Black" {
+		t.Errorf("CopyPageObject() did not rename the colours of the line style, got: %+v", dst.MultiLine)
+	}
+
+	dst = target()
+	if _, err := dst.CopyPageObject(src, a, 0, 0, 0, CopyOptions{Conflicts: ReplaceExisting}); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(dst.COLOR) != 4 || dst.COLOR[3].CMYK != "#00800000" {
+		t.Errorf("CopyPageObject() did not replace the colour, got: %+v", dst.COLOR)
+	}
+	if _, err := dst.CopyPageObject(src, a, 1, 0, 0, CopyOptions{}); err == nil {
+		t.Errorf("CopyPageObject() onto a missing page should be an error")
+	}
+	if _, err := dst.CopyPageObject(src, a, 0, 0, 0, CopyOptions{Layer: "Missing"}); err == nil {
+		t.Errorf("CopyPageObject() onto a missing layer should be an error")
+	}
+
+	// Groups are moved with their items, which get new ItemIDs too
+	group, err := src.Group(&src.PAGEOBJECT[1], &src.PAGEOBJECT[2])
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	dst = target()
+	next = dst.maxItemID() + 1
+	moved, err := dst.MovePageObjectFrom(src, group, 0, 0, 0, CopyOptions{})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(src.PAGEOBJECT) != 2 || len(dst.PAGEOBJECT) != 5 || moved.XPOS != "100" || moved.YPOS != "20" || len(moved.PAGEOBJECT) != 2 {
+		t.Fatalf("MovePageObjectFrom() was incorrect, got: %v at %v, %v", moved.ItemID, moved.XPOS, moved.YPOS)
+	}
+	image := moved.PAGEOBJECT[1]
+	if image.XPOS != "100" || image.YPOS != "88" || image.GYpos != "68" || moved.ItemID != strconv.Itoa(next) || image.ItemID != strconv.Itoa(next+2) {
+		t.Errorf("MovePageObjectFrom() was incorrect, got: %v at %v, %v", image.ItemID, image.XPOS, image.YPOS)
+	}
+}
@@ -0,0 +1,590 @@
+package scribus
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// TextExportOptions configures the plain text export. Empty separators get the defaults
+type TextExportOptions struct {
+	ParagraphSeparator string // Between paragraphs, "\n" by default
+	LineBreak          string // For line, column and frame breaks, "\n" by default
+	StorySeparator     string // Between stories, "\n\n" by default
+}
+
+// Paragraphs returns the paragraphs of all frames of the Story
+func (s Story) Paragraphs() []Paragraph {
+	var paragraphs []Paragraph
+	for _, po := range s.Frames {
+		paragraphs = append(paragraphs, po.StoryText.Paragraphs()...)
+	}
+	return paragraphs
+}
+
+// StoriesInReadingOrder returns the stories of the document (see Stories) that start on
+// pages in reading order: by the page of their first frame, then from top to bottom and
+// from left to right. Stories on the pasteboard come last. The stories of master pages
+// and inline frames are left out
+func (doc DOCUMENT) StoriesInReadingOrder() []Story {
+	onPages := map[*PAGEOBJECT]bool{}
+	walkPageObjects(doc.PAGEOBJECT, func(po *PAGEOBJECT) { onPages[po] = true })
+	var stories []Story
+	for _, s := range doc.Stories() {
+		if onPages[s.First()] {
+			stories = append(stories, s)
+		}
+	}
+	page := func(s Story) int {
+		n, err := strconv.Atoi(s.First().OwnPage)
+		if err != nil || n < 0 {
+			return math.MaxInt32
+		}
+		return n
+	}
+	sort.SliceStable(stories, func(i, j int) bool {
+		a, b := stories[i].First(), stories[j].First()
+		if page(stories[i]) != page(stories[j]) {
+			return page(stories[i]) < page(stories[j])
+		}
+		if ya, yb := parseFloat(a.YPOS), parseFloat(b.YPOS); ya != yb {
+			return ya < yb
+		}
+		return parseFloat(a.XPOS) < parseFloat(b.XPOS)
+	})
+	return stories
+}
+
+func (doc DOCUMENT) storyParagraphs() [][]Paragraph {
+	var stories [][]Paragraph
+	for _, s := range doc.StoriesInReadingOrder() {
+		if paragraphs := s.Paragraphs(); len(paragraphs) > 0 {
+			stories = append(stories, paragraphs)
+		}
+	}
+	return stories
+}
+
+// ExportText returns the text of the StoryText with bullets and numbers (see TextExportOptions)
+func (st StoryText) ExportText(options TextExportOptions) string {
+	return exportText([][]Paragraph{st.Paragraphs()}, options)
+}
+
+// ExportText returns the text of the Story with bullets and numbers (see TextExportOptions)
+func (s Story) ExportText(options TextExportOptions) string {
+	return exportText([][]Paragraph{s.Paragraphs()}, options)
+}
+
+// ExportText returns the text of all stories of the document in reading order, with
+// bullets and numbers (see TextExportOptions)
+func (doc DOCUMENT) ExportText(options TextExportOptions) string {
+	return exportText(doc.storyParagraphs(), options)
+}
+
+// ExportMarkdown returns the StoryText as Markdown. styles tells which paragraph and
+// character styles stand for headings, emphasis and so on, like in MarkdownToParagraphs
+func (st StoryText) ExportMarkdown(styles MarkdownStyles) string {
+	return exportMarkdown([][]Paragraph{st.Paragraphs()}, styles)
+}
+
+// ExportMarkdown returns the text of the Story as Markdown (see StoryText.ExportMarkdown)
+func (s Story) ExportMarkdown(styles MarkdownStyles) string {
+	return exportMarkdown([][]Paragraph{s.Paragraphs()}, styles)
+}
+
+// ExportMarkdown returns all stories of the document in reading order as Markdown (see
+// StoryText.ExportMarkdown)
+func (doc DOCUMENT) ExportMarkdown(styles MarkdownStyles) string {
+	return exportMarkdown(doc.storyParagraphs(), styles)
+}
+
+// ExportHTML returns the StoryText as HTML. Paragraphs are p elements, or the elements
+// whose BlockTags map to their style, list items are li elements in ul and ol, line
+// breaks are br elements. Paragraph and character styles become classes, e.g.,
+// "Heading 1" becomes class="heading-1", and underline, strike, superscript and subscript
+// become u, s, sup and sub elements
+func (st StoryText) ExportHTML(styles HTMLStyles) string {
+	return exportHTML([][]Paragraph{st.Paragraphs()}, styles)
+}
+
+// ExportHTML returns the text of the Story as HTML (see StoryText.ExportHTML)
+func (s Story) ExportHTML(styles HTMLStyles) string {
+	return exportHTML([][]Paragraph{s.Paragraphs()}, styles)
+}
+
+// ExportHTML returns all stories of the document in reading order as HTML (see
+// StoryText.ExportHTML)
+func (doc DOCUMENT) ExportHTML(styles HTMLStyles) string {
+	return exportHTML(doc.storyParagraphs(), styles)
+}
+
+// exportParagraph is a paragraph with its list properties
+type exportParagraph struct {
+	Paragraph
+	list    bool
+	ordered bool
+	level   int
+	label   string // Bullet or number, e.g., "•" or "3."
+	number  int    // Number of numbered items
+}
+
+// listParagraphs works out the list properties of the paragraphs of a story. The level of
+// numbered items is their NumerationLevel, that of bullet items follows from their indent
+func listParagraphs(paragraphs []Paragraph) []exportParagraph {
+	numbers := listNumbers{}
+	var indents []float64
+	result := make([]exportParagraph, len(paragraphs))
+	for i, p := range paragraphs {
+		ep := exportParagraph{Paragraph: p}
+		switch {
+		case p.Style.Numeration == "1":
+			ep.list, ep.ordered = true, true
+			ep.level, _ = strconv.Atoi(p.Style.NumerationLevel)
+			ep.label = numbers.next(p.Style)
+			counters := numbers[p.Style.NumerationName]
+			ep.number = counters[len(counters)-1]
+		case p.Style.Bullet == "1":
+			numbers.skip()
+			ep.list = true
+			ep.label = p.Style.BulletStr
+			if ep.label == "" {
+				ep.label = "•"
+			}
+			indent := parseFloat(p.Style.INDENT)
+			for len(indents) > 0 && indents[len(indents)-1] > indent+0.01 {
+				indents = indents[:len(indents)-1]
+			}
+			if len(indents) == 0 || indent > indents[len(indents)-1]+0.01 {
+				indents = append(indents, indent)
+			}
+			ep.level = len(indents) - 1
+		default:
+			numbers.skip()
+			indents = nil
+		}
+		result[i] = ep
+	}
+	return result
+}
+
+// exportChars replaces the special characters of a run for export
+func exportChars(text string, lineBreak string) string {
+	return strings.NewReplacer("\u2028", lineBreak, "\u001a", lineBreak, "\u001b", lineBreak,
+		"\u2011", "-", "\ufeff", "", "\u200b", "").Replace(text)
+}
+
+func exportText(stories [][]Paragraph, options TextExportOptions) string {
+	if options.ParagraphSeparator == "" {
+		options.ParagraphSeparator = "\n"
+	}
+	if options.LineBreak == "" {
+		options.LineBreak = "\n"
+	}
+	if options.StorySeparator == "" {
+		options.StorySeparator = "\n\n"
+	}
+	var sb strings.Builder
+	for i, paragraphs := range stories {
+		if i > 0 {
+			sb.WriteString(options.StorySeparator)
+		}
+		for j, p := range listParagraphs(paragraphs) {
+			if j > 0 {
+				sb.WriteString(options.ParagraphSeparator)
+			}
+			if p.list {
+				sb.WriteString(strings.Repeat("\t", p.level) + p.label + "\t")
+			}
+			sb.WriteString(exportChars(p.Text(), options.LineBreak))
+		}
+	}
+	return sb.String()
+}
+
+// formatMatches tells whether run has all character attributes of format. An empty format never matches
+func formatMatches(run ITEXT, format ITEXT) bool {
+	attrs := itextAttrs(format)
+	if len(attrs) == 0 {
+		return false
+	}
+	values := map[string]string{}
+	for _, a := range itextAttrs(run) {
+		values[a.Name.Local] = a.Value
+	}
+	for _, a := range attrs {
+		if a.Name.Local == "FEATURES" {
+			for _, feature := range strings.Fields(a.Value) {
+				if feature != "inherit" && !run.HasFeature(feature) {
+					return false
+				}
+			}
+			continue
+		}
+		if values[a.Name.Local] != a.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// Kinds of Markdown inline formatting
+const (
+	mdPlain = iota
+	mdEmphasis
+	mdStrong
+	mdStrongEmphasis
+	mdCodeSpan
+)
+
+// markdownKind returns the Markdown formatting of a run. Runs whose font name contains
+// "Bold", "Italic" or "Oblique" are strong or emphasized too
+func markdownKind(run ITEXT, styles MarkdownStyles) int {
+	if formatMatches(run, styles.Code) {
+		return mdCodeSpan
+	}
+	if formatMatches(run, styles.StrongEmphasis) {
+		return mdStrongEmphasis
+	}
+	strong := formatMatches(run, styles.Strong) || strings.Contains(run.FONT, "Bold")
+	emphasis := formatMatches(run, styles.Emphasis) || strings.Contains(run.FONT, "Italic") || strings.Contains(run.FONT, "Oblique")
+	switch {
+	case strong && emphasis:
+		return mdStrongEmphasis
+	case strong:
+		return mdStrong
+	case emphasis:
+		return mdEmphasis
+	}
+	return mdPlain
+}
+
+var markdownEscaper = strings.NewReplacer("\\", "\\\\", "`", "\\`", "*", "\\*", "_", "\\_", "[", "\\[", "]", "\\]", "<", "\\<", ">", "\\>")
+
+// markdownInline returns the runs as inline Markdown; lineBreak is written for line breaks
+func markdownInline(runs []ITEXT, styles MarkdownStyles, lineBreak string) string {
+	var sb strings.Builder
+	for i := 0; i < len(runs); {
+		kind := markdownKind(runs[i], styles)
+		var text strings.Builder
+		for ; i < len(runs) && markdownKind(runs[i], styles) == kind; i++ {
+			text.WriteString(runs[i].CH)
+		}
+		t := text.String()
+		if kind == mdCodeSpan {
+			t = exportChars(t, " ")
+			ticks := "`"
+			for strings.Contains(t, ticks) {
+				ticks += "`"
+			}
+			if strings.HasPrefix(t, "`") || strings.HasSuffix(t, "`") {
+				t = " " + t + " "
+			}
+			sb.WriteString(ticks + t + ticks)
+			continue
+		}
+		t = exportChars(markdownEscaper.Replace(t), lineBreak)
+		delimiter := []string{"", "*", "**", "***"}[kind]
+		// Delimiters must be next to the text, white space goes outside
+		trimmed := strings.TrimSpace(t)
+		if delimiter == "" || trimmed == "" {
+			sb.WriteString(t)
+			continue
+		}
+		start := strings.Index(t, trimmed)
+		sb.WriteString(t[:start] + delimiter + trimmed + delimiter + t[start+len(trimmed):])
+	}
+	return sb.String()
+}
+
+func exportMarkdown(stories [][]Paragraph, styles MarkdownStyles) string {
+	headings := map[string]int{}
+	for i, name := range styles.Headings {
+		if name != "" {
+			if _, ok := headings[name]; !ok {
+				headings[name] = i + 1
+			}
+		}
+	}
+	var blocks []string
+	for _, paragraphs := range stories {
+		var previous *exportParagraph
+		for _, p := range listParagraphs(paragraphs) {
+			if len(p.Runs) == 0 {
+				continue // Markdown has no empty paragraphs
+			}
+			style := p.Style.PARENT
+			var block string
+			switch {
+			case p.list:
+				marker := "- "
+				indent := strings.Repeat("  ", p.level)
+				if p.ordered {
+					marker = strconv.Itoa(p.number) + ". "
+					indent = strings.Repeat("   ", p.level)
+				}
+				block = indent + marker + markdownInline(p.Runs, styles, "\\\n"+indent+strings.Repeat(" ", len(marker)))
+			case style != "" && style == styles.CodeBlock:
+				block = "```\n" + exportChars(p.Text(), "\n") + "\n```"
+			case headings[style] > 0:
+				block = strings.Repeat("#", headings[style]) + " " + markdownInline(p.Runs, styles, " ")
+			case style != "" && style == styles.Quote:
+				block = "> " + markdownInline(p.Runs, styles, "\\\n> ")
+			default:
+				block = markdownInline(p.Runs, styles, "\\\n")
+				if strings.HasPrefix(block, "#") || startsLikeListItem(block) {
+					block = "\\" + block
+				}
+			}
+			// Items of the same list are not separated by blank lines
+			if p.list && previous != nil && previous.list && (p.level > previous.level || p.ordered == previous.ordered) {
+				blocks[len(blocks)-1] += "\n" + block
+			} else {
+				blocks = append(blocks, block)
+			}
+			previous = &p
+		}
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// startsLikeListItem tells whether a Markdown paragraph would be read as a list item
+func startsLikeListItem(s string) bool {
+	if len(s) > 1 && strings.ContainsRune("-+", rune(s[0])) && s[1] == ' ' {
+		return true
+	}
+	digits := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) })
+	return digits > 0 && digits+1 < len(s) && (s[digits] == '.' || s[digits] == ')') && s[digits+1] == ' '
+}
+
+// cssClass returns a class name for a style name, e.g., "heading-1" for "Heading 1"
+func cssClass(style string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(style) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			dash = false
+			sb.WriteRune(r)
+		} else {
+			dash = true
+		}
+	}
+	return sb.String()
+}
+
+func classAttr(style string) string {
+	if class := cssClass(style); class != "" {
+		return ` class="` + class + `"`
+	}
+	return ""
+}
+
+// htmlInline returns the runs as inline HTML
+func htmlInline(runs []ITEXT) string {
+	var sb strings.Builder
+	for i := 0; i < len(runs); {
+		run := runs[i]
+		var text strings.Builder
+		for ; i < len(runs) && runs[i].SameFormat(run); i++ {
+			text.WriteString(runs[i].CH)
+		}
+		t := strings.ReplaceAll(exportChars(html.EscapeString(text.String()), "<br>"), "\u00a0", "&nbsp;")
+		var open, close string
+		for _, f := range []struct{ feature, tag string }{
+			{FeatureUnderline, "u"}, {FeatureStrike, "s"}, {FeatureSuperscript, "sup"}, {FeatureSubscript, "sub"},
+		} {
+			if run.HasFeature(f.feature) {
+				open += "<" + f.tag + ">"
+				close = "</" + f.tag + ">" + close
+			}
+		}
+		if run.CPARENT != "" {
+			open = "<span" + classAttr(run.CPARENT) + ">" + open
+			close += "</span>"
+		}
+		sb.WriteString(open + t + close)
+	}
+	return sb.String()
+}
+
+func exportHTML(stories [][]Paragraph, styles HTMLStyles) string {
+	// Tags of the paragraph styles, the first tag in alphabetical order wins
+	var tags []string
+	for tag := range styles.BlockTags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	blockTags := map[string]string{}
+	for _, tag := range tags {
+		name := styles.BlockTags[tag]
+		if _, ok := blockTags[name]; !ok && name != "" && tag != "li" && tag != "p" && tag != "div" {
+			blockTags[name] = tag
+		}
+	}
+
+	var sb strings.Builder
+	for _, paragraphs := range stories {
+		var lists []string // Tags of the open lists, each with an open li
+		closeLists := func(level int) {
+			for len(lists) > level {
+				sb.WriteString("</li></" + lists[len(lists)-1] + ">\n")
+				lists = lists[:len(lists)-1]
+			}
+		}
+		for _, p := range listParagraphs(paragraphs) {
+			content := htmlInline(p.Runs)
+			if !p.list {
+				closeLists(0)
+				tag := "p"
+				if t, ok := blockTags[p.Style.PARENT]; ok {
+					tag = t
+				}
+				sb.WriteString("<" + tag + classAttr(p.Style.PARENT) + ">" + content + "</" + tag + ">\n")
+				continue
+			}
+
+			tag := "ul"
+			if p.ordered {
+				tag = "ol"
+			}
+			closeLists(p.level + 1)
+			if len(lists) == p.level+1 {
+				if lists[p.level] == tag {
+					sb.WriteString("</li>\n")
+				} else {
+					closeLists(p.level)
+				}
+			}
+			for len(lists) < p.level+1 {
+				start := ""
+				if p.ordered && p.number != 1 && len(lists) == p.level {
+					start = ` start="` + strconv.Itoa(p.number) + `"`
+				}
+				if len(lists) < p.level {
+					// Nested list without an item of the level above
+					sb.WriteString("<ul><li>")
+					lists = append(lists, "ul")
+					continue
+				}
+				sb.WriteString("<" + tag + start + ">\n")
+				lists = append(lists, tag)
+			}
+			sb.WriteString("<li" + classAttr(p.Style.PARENT) + ">" + content)
+		}
+		closeLists(0)
+	}
+	return sb.String()
+}
+
+// Numbering formats of numbered paragraphs (para NumerationFormat)
+const (
+	NumberDecimal     = "0"  // 1, 2, 3
+	NumberArabic      = "1"  // Arabic-Indic digits
+	NumberLowerRoman  = "2"  // i, ii, iii
+	NumberUpperRoman  = "3"  // I, II, III
+	NumberLowerLetter = "4"  // a, b, c
+	NumberUpperLetter = "5"  // A, B, C
+	NumberAsterisk    = "7"  // *, **, ***
+	NumberNone        = "99" // No number
+)
+
+// formatNumber formats n in one of the numbering formats
+func formatNumber(n int, format string) string {
+	switch format {
+	case NumberArabic:
+		var sb strings.Builder
+		for _, r := range strconv.Itoa(n) {
+			if r >= '0' && r <= '9' {
+				r = r - '0' + '\u0660'
+			}
+			sb.WriteRune(r)
+		}
+		return sb.String()
+	case NumberLowerRoman:
+		return strings.ToLower(romanNumeral(n))
+	case NumberUpperRoman:
+		return romanNumeral(n)
+	case NumberLowerLetter:
+		return strings.ToLower(letterNumeral(n))
+	case NumberUpperLetter:
+		return letterNumeral(n)
+	case NumberAsterisk:
+		if n < 1 {
+			return ""
+		}
+		return strings.Repeat("*", n)
+	case NumberNone:
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// romanNumeral returns n in upper case roman numerals, or as a decimal number if it cannot be written so
+func romanNumeral(n int) string {
+	if n < 1 || n > 3999 {
+		return strconv.Itoa(n)
+	}
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	symbols := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+	var sb strings.Builder
+	for i, v := range values {
+		for n >= v {
+			sb.WriteString(symbols[i])
+			n -= v
+		}
+	}
+	return sb.String()
+}
+
+// letterNumeral returns n as upper case letters: A to Z, then AA, AB and so on
+func letterNumeral(n int) string {
+	if n < 1 {
+		return strconv.Itoa(n)
+	}
+	var letters []byte
+	for n > 0 {
+		n--
+		letters = append([]byte{byte('A' + n%26)}, letters...)
+		n /= 26
+	}
+	return string(letters)
+}
+
+// listNumbers keeps the counters of the numbered lists, by NumerationName and level, while
+// the paragraphs of a story are walked through
+type listNumbers map[string][]int
+
+// next counts the numbered paragraph with style and returns its label, e.g., "3."
+func (ln listNumbers) next(style Para) string {
+	level, _ := strconv.Atoi(style.NumerationLevel)
+	if level < 0 {
+		level = 0
+	}
+	counters := ln[style.NumerationName]
+	for len(counters) <= level {
+		counters = append(counters, 0)
+	}
+	// Deeper levels start again
+	counters = counters[:level+1]
+	start, err := strconv.Atoi(style.NumerationStart)
+	if err != nil {
+		start = 1
+	}
+	if counters[level] == 0 || (style.NumerationRestart == "1" && style.NumerationStart != "") {
+		counters[level] = start
+	} else {
+		counters[level]++
+	}
+	ln[style.NumerationName] = counters
+	return style.NumerationPrefix + formatNumber(counters[level], style.NumerationFormat) + style.NumerationSuffix
+}
+
+// skip is called for paragraphs that are not numbered, they end local numbered lists
+func (ln listNumbers) skip() {
+	delete(ln, "<local block>")
+}
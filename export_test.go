@@ -0,0 +1,59 @@
+package scribus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExportText(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	text := document.DOCUMENT.ExportText(TextExportOptions{})
+	if text != "One\n\n\nTwo\n\n\n•\tThree\n•\tFour\n•\tFive" {
+		t.Errorf("ExportText() was incorrect, got: %q", text)
+	}
+
+	var st StoryText
+	st.SetParagraphs([]Paragraph{
+		{Runs: []ITEXT{NewRun("Name\tValue")}},
+		{Runs: []ITEXT{NewRun("First")}, Style: listItemStyle(0, true, "", 0, ".", 18)},
+		{Runs: []ITEXT{NewRun("Second\u2028line")}, Style: listItemStyle(0, true, "", 0, ".", 18)},
+		{Runs: []ITEXT{NewRun("Sub")}, Style: Para{Numeration: "1", NumerationName: "<local block>", NumerationLevel: "1", NumerationFormat: NumberLowerRoman, NumerationSuffix: ")", NumerationStart: "3"}},
+	})
+	text = st.ExportText(TextExportOptions{ParagraphSeparator: "\n\n", LineBreak: " / "})
+	if text != "Name\tValue\n\n1.\tFirst\n\n2.\tSecond / line\n\n\tiii)\tSub" {
+		t.Errorf("ExportText() was incorrect, got: %q", text)
+	}
+}
+
+func TestExportMarkdownAndHTML(t *testing.T) {
+	markdown := "# Title\n\n" +
+		"Some *emphasis*, **strong** and `code` with a\\\nline break\n\n" +
+		"- One\n" +
+		"  - Nested\n" +
+		"- Two\n\n" +
+		"3. Three\n" +
+		"4. Four\n\n" +
+		"> Quote\n"
+	var st StoryText
+	st.SetMarkdown(markdown, DefaultMarkdownStyles())
+	if got := st.ExportMarkdown(DefaultMarkdownStyles()); got != markdown {
+		t.Errorf("ExportMarkdown() was incorrect, got: %q, want: %q.", got, markdown)
+	}
+
+	got := st.ExportHTML(DefaultHTMLStyles())
+	for _, want := range []string{
+		`<h1 class="heading-1">Title</h1>`,
+		`<span class="emphasis">emphasis</span>`,
+		"with a<br>line break</p>",
+		"<li>One<ul>\n<li>Nested</li></ul>\n</li>",
+		`<ol start="3">`,
+		`<blockquote class="quote">Quote</blockquote>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ExportHTML() does not contain %q, got: %v", want, got)
+		}
+	}
+}
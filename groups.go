@@ -0,0 +1,181 @@
+package scribus
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// findItem returns the slice that holds the item with the ItemID id (the page or master page
+// items, or the items of a group) and its index there; nil, -1 if there is no such item
+func (doc *DOCUMENT) findItem(id string) (*[]PAGEOBJECT, int) {
+	var find func(objs *[]PAGEOBJECT) (*[]PAGEOBJECT, int)
+	find = func(objs *[]PAGEOBJECT) (*[]PAGEOBJECT, int) {
+		for i := range *objs {
+			if (*objs)[i].ItemID == id {
+				return objs, i
+			}
+			if parent, j := find(&(*objs)[i].PAGEOBJECT); parent != nil {
+				return parent, j
+			}
+		}
+		return nil, -1
+	}
+	for _, objs := range []*[]PAGEOBJECT{&doc.MASTEROBJECT, &doc.PAGEOBJECT} {
+		if parent, i := find(objs); parent != nil {
+			return parent, i
+		}
+	}
+	return nil, -1
+}
+
+// boundingBox returns the corners of the smallest rectangle that contains the item, which
+// is rotated by ROT degrees around its top left corner
+func (po PAGEOBJECT) boundingBox() (min Point, max Point) {
+	x, y := parseFloat(po.XPOS), parseFloat(po.YPOS)
+	w, h := parseFloat(po.WIDTH), parseFloat(po.HEIGHT)
+	m := Rotation(parseFloat(po.ROT)).Then(Translation(x, y))
+	for i, corner := range []Point{{0, 0}, {w, 0}, {w, h}, {0, h}} {
+		p := m.Apply(corner)
+		if i == 0 {
+			min, max = p, p
+			continue
+		}
+		min = Point{math.Min(min.X, p.X), math.Min(min.Y, p.Y)}
+		max = Point{math.Max(max.X, p.X), math.Max(max.Y, p.Y)}
+	}
+	return min, max
+}
+
+// inGroup tells whether parent holds the items of a group rather than the page or master
+// page items
+func (doc *DOCUMENT) inGroup(parent *[]PAGEOBJECT) bool {
+	return parent != &doc.PAGEOBJECT && parent != &doc.MASTEROBJECT
+}
+
+// IsGroup tells whether the item is a group (PTYPE 12)
+func (po PAGEOBJECT) IsGroup() bool { return po.PTYPE == "12" }
+
+// SetGroupClips sets whether the group clips its items to its outline
+func (po *PAGEOBJECT) SetGroupClips(clips bool) { po.GroupClips = boolAttr(clips) }
+
+// Group puts the items into a new group item, which takes the place of the topmost of them in
+// the stacking order and returns a pointer to it, error. The items must be side by side:
+// all on pages, all on master pages or all in the same group. The group is as large as the
+// bounding boxes of the items; the items keep their page positions and get their positions
+// within the group in gXpos and gYpos. Inside a group, the new group gets its position within
+// that group
+func (doc *DOCUMENT) Group(items ...*PAGEOBJECT) (*PAGEOBJECT, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no items to group")
+	}
+	var parent *[]PAGEOBJECT
+	var indexes []int
+	for _, item := range items {
+		p, i := doc.findItem(item.ItemID)
+		if p == nil {
+			return nil, fmt.Errorf("item %v not found", item.ItemID)
+		}
+		if parent != nil && p != parent {
+			return nil, fmt.Errorf("item %v is not next to item %v", item.ItemID, items[0].ItemID)
+		}
+		parent = p
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	for i := 1; i < len(indexes); i++ {
+		if indexes[i] == indexes[i-1] {
+			return nil, fmt.Errorf("item %v is given twice", (*parent)[indexes[i]].ItemID)
+		}
+	}
+
+	var children []PAGEOBJECT
+	var min, max Point
+	for n, i := range indexes {
+		children = append(children, (*parent)[i])
+		lo, hi := (*parent)[i].boundingBox()
+		if n == 0 {
+			min, max = lo, hi
+			continue
+		}
+		min = Point{math.Min(min.X, lo.X), math.Min(min.Y, lo.Y)}
+		max = Point{math.Max(max.X, hi.X), math.Max(max.Y, hi.Y)}
+	}
+	top := children[len(children)-1]
+	width, height := max.X-min.X, max.Y-min.Y
+	for i := range children {
+		c := &children[i]
+		c.GXpos = formatFloat(parseFloat(c.XPOS) - min.X)
+		c.GYpos = formatFloat(parseFloat(c.YPOS) - min.Y)
+		c.GWidth, c.GHeight = formatFloat(width), formatFloat(height)
+	}
+
+	group := newPageObject("12", width, height)
+	group.ItemID = strconv.Itoa(doc.maxItemID() + 1)
+	group.OwnPage, group.LAYER = top.OwnPage, top.LAYER
+	group.XPOS, group.YPOS = formatFloat(min.X), formatFloat(min.Y)
+	group.GXpos, group.GYpos = group.XPOS, group.YPOS
+	if doc.inGroup(parent) {
+		// The items know where the group that holds them is
+		group.GXpos = formatFloat(min.X - parseFloat(top.XPOS) + parseFloat(top.GXpos))
+		group.GYpos = formatFloat(min.Y - parseFloat(top.YPOS) + parseFloat(top.GYpos))
+		group.GWidth, group.GHeight = top.GWidth, top.GHeight
+	}
+	group.GroupWidth, group.GroupHeight = group.WIDTH, group.HEIGHT
+	group.GroupClips = "0"
+	group.PAGEOBJECT = children
+
+	grouped := map[int]bool{}
+	for _, i := range indexes {
+		grouped[i] = true
+	}
+	var kept []PAGEOBJECT
+	for i, po := range *parent {
+		if i == indexes[len(indexes)-1] {
+			kept = append(kept, group)
+		} else if !grouped[i] {
+			kept = append(kept, po)
+		}
+	}
+	*parent = kept
+	_, i := doc.findItem(group.ItemID)
+	return &(*parent)[i], nil
+}
+
+// Ungroup dissolves the group, whose items take its place in the stacking order, and returns
+// error. The rotation of the group is applied to its items and the items inside them
+func (doc *DOCUMENT) Ungroup(group *PAGEOBJECT) error {
+	parent, index := doc.findItem(group.ItemID)
+	if parent == nil {
+		return fmt.Errorf("item %v not found", group.ItemID)
+	}
+	g := (*parent)[index]
+	if !g.IsGroup() {
+		return fmt.Errorf("item %v is not a group", g.ItemID)
+	}
+	rotation := parseFloat(g.ROT)
+	origin := Point{parseFloat(g.XPOS), parseFloat(g.YPOS)}
+	m := Translation(-origin.X, -origin.Y).Then(Rotation(rotation)).Then(Translation(origin.X, origin.Y))
+	children := g.PAGEOBJECT
+	if rotation != 0 {
+		walkPageObjects(children, func(c *PAGEOBJECT) {
+			p := m.Apply(Point{parseFloat(c.XPOS), parseFloat(c.YPOS)})
+			c.XPOS, c.YPOS = formatFloat(p.X), formatFloat(p.Y)
+			c.ROT = formatFloat(parseFloat(c.ROT) + rotation)
+		})
+	}
+	for i := range children {
+		c := &children[i]
+		if doc.inGroup(parent) {
+			c.GXpos = formatFloat(parseFloat(c.XPOS) - parseFloat(g.XPOS) + parseFloat(g.GXpos))
+			c.GYpos = formatFloat(parseFloat(c.YPOS) - parseFloat(g.YPOS) + parseFloat(g.GYpos))
+			c.GWidth, c.GHeight = g.GWidth, g.GHeight
+		} else {
+			c.GXpos, c.GYpos = c.XPOS, c.YPOS
+			c.GWidth, c.GHeight = "0", "0"
+		}
+	}
+	*parent = append((*parent)[:index], append(children, (*parent)[index+1:]...)...)
+	return nil
+}
@@ -0,0 +1,124 @@
+package scribus
+
+import (
+	"testing"
+)
+
+func TestGroups(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := &document.DOCUMENT
+	// Items at 140, 60 (118.5 x 52.5), 140, 113 and 140, 181 (150.25 x 163.5)
+	ids := []string{doc.PAGEOBJECT[0].ItemID, doc.PAGEOBJECT[1].ItemID, doc.PAGEOBJECT[2].ItemID, doc.PAGEOBJECT[3].ItemID}
+	group, err := doc.Group(&doc.PAGEOBJECT[2], &doc.PAGEOBJECT[0])
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(doc.PAGEOBJECT) != 3 || doc.PAGEOBJECT[0].ItemID != ids[1] || doc.PAGEOBJECT[1].ItemID != group.ItemID || doc.PAGEOBJECT[2].ItemID != ids[3] {
+		t.Fatalf("Group() did not replace the items, got: %+v", doc.PAGEOBJECT)
+	}
+	if !group.IsGroup() || group.XPOS != "140" || group.YPOS != "60" || group.WIDTH != "150.25" || group.HEIGHT != "284.5" || group.GroupWidth != "150.25" || group.GXpos != "140" {
+		t.Errorf("group was incorrect, got: %v at %v, %v, %v x %v", group.PTYPE, group.XPOS, group.YPOS, group.WIDTH, group.HEIGHT)
+	}
+	image := group.PAGEOBJECT[1]
+	if len(group.PAGEOBJECT) != 2 || group.PAGEOBJECT[0].ItemID != ids[0] || image.XPOS != "140" || image.GXpos != "0" || image.GYpos != "121" || image.GHeight != "284.5" {
+		t.Errorf("items of the group were incorrect, got: %+v", image)
+	}
+	group.SetGroupClips(true)
+	if group.GroupClips != "1" {
+		t.Errorf("SetGroupClips() was incorrect, got: %v", group.GroupClips)
+	}
+
+	// Nested groups and moves keep the positions within groups
+	outer, err := doc.Group(&doc.PAGEOBJECT[1], &doc.PAGEOBJECT[2])
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	shiftPageObject(outer, 10, 0)
+	inner := outer.PAGEOBJECT[0]
+	if inner.GXpos != "0" || inner.XPOS != "150" || inner.PAGEOBJECT[1].GXpos != "0" || inner.PAGEOBJECT[1].XPOS != "150" {
+		t.Errorf("nested group was incorrect, got: %+v", inner)
+	}
+	if _, err := doc.Group(&doc.PAGEOBJECT[0], &inner.PAGEOBJECT[0]); err == nil {
+		t.Errorf("Group() of items in different groups should be an error")
+	}
+
+	// Groups inside groups are placed within them, and so are their items when ungrouped
+	nested, err := doc.Group(&outer.PAGEOBJECT[1])
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if nested.XPOS != "150" || nested.YPOS != "368" || nested.GXpos != "0" || nested.GYpos != "308" || nested.GHeight != outer.GroupHeight {
+		t.Errorf("Group() inside a group was incorrect, got: %v, %v within the group", nested.GXpos, nested.GYpos)
+	}
+	if err := doc.Ungroup(nested); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if last := outer.PAGEOBJECT[1]; last.ItemID != ids[3] || last.GXpos != "0" || last.GYpos != "308" || last.GHeight != outer.GroupHeight {
+		t.Errorf("Ungroup() inside a group was incorrect, got: %v, %v within the group", last.GXpos, last.GYpos)
+	}
+
+	outer.ROT = "90"
+	if err := doc.Ungroup(outer); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	// The outer group at 150, 60 turns 90 degrees, so the item 308 below it ends up 308 left of it
+	ungrouped := doc.PAGEOBJECT[1]
+	if len(doc.PAGEOBJECT) != 3 || ungrouped.ItemID != group.ItemID || ungrouped.ROT != "90" || ungrouped.GXpos != "150" || ungrouped.GWidth != "0" {
+		t.Errorf("Ungroup() was incorrect, got: %+v", ungrouped)
+	}
+	last := doc.PAGEOBJECT[2]
+	if last.ItemID != ids[3] || last.XPOS != "-158" || last.YPOS != "60" || last.ROT != "90" {
+		t.Errorf("Ungroup() was incorrect, got: %v at %v, %v", last.ItemID, last.XPOS, last.YPOS)
+	}
+	// The items of the nested group turn with it
+	if image := ungrouped.PAGEOBJECT[1]; image.XPOS != "29" || image.YPOS != "60" || image.ROT != "90" || image.GYpos != "121" {
+		t.Errorf("Ungroup() did not turn the items of the nested group, got: %v at %v, %v", image.ItemID, image.XPOS, image.YPOS)
+	}
+	if err := doc.Ungroup(&doc.PAGEOBJECT[0]); err == nil {
+		t.Errorf("Ungroup() of an item that is not a group should be an error")
+	}
+}
+
+func TestGroupsOnMasterPages(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	// Items read as PAGEOBJECTs become master page items
+	doc := &document.DOCUMENT
+	doc.MASTEROBJECT = append(doc.MASTEROBJECT, doc.PAGEOBJECT[0], doc.PAGEOBJECT[1])
+	doc.PAGEOBJECT = doc.PAGEOBJECT[2:]
+	// and are read as MASTEROBJECTs again
+	if document, err = document.Clone(); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc = &document.DOCUMENT
+	if len(doc.MASTEROBJECT) != 2 {
+		t.Fatalf("master page items were not written, got: %v", len(doc.MASTEROBJECT))
+	}
+	if _, err := doc.Group(&doc.MASTEROBJECT[0], &doc.MASTEROBJECT[1]); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	clone, err := document.Clone()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	masters := clone.DOCUMENT.MASTEROBJECT
+	if len(masters) != 1 || len(masters[0].PAGEOBJECT) != 2 || len(clone.DOCUMENT.PAGEOBJECT) != 2 {
+		t.Fatalf("group of master page items was not written, got: %v master page items", len(masters))
+	}
+
+	if err := clone.DOCUMENT.Ungroup(&clone.DOCUMENT.MASTEROBJECT[0]); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	clone, err = clone.Clone()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(clone.DOCUMENT.MASTEROBJECT) != 2 || len(clone.DOCUMENT.PAGEOBJECT) != 2 {
+		t.Errorf("ungrouped master page items were not written, got: %v master page items", len(clone.DOCUMENT.MASTEROBJECT))
+	}
+}
@@ -0,0 +1,642 @@
+package scribus
+
+import (
+	"html"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// HTMLStyles configures how HTML fragments are turned into StoryText. Paragraph styles
+// are the NAMEs of STYLEs, character formats are applied to the runs (see MarkdownStyles)
+type HTMLStyles struct {
+	BlockTags  map[string]string // Paragraph styles of block elements by tag, e.g., "h1": "Heading 1"
+	InlineTags map[string]ITEXT  // Character formats of inline elements by tag, e.g., "b": ITEXT{CPARENT: "Strong"}
+
+	ParagraphClasses map[string]string // Paragraph styles of block elements by class, they win over BlockTags
+	CharClasses      map[string]ITEXT  // Character formats of elements by class, added to those of the tag
+
+	Colors map[string]string // COLOR names by CSS colour value (e.g., "#ff0000" or "red") for span style colours
+
+	Paragraph  string   // Paragraph style of block elements without a style of their own
+	ListItem   string   // Paragraph style of list items
+	Bullets    []string // BulletStr of bullet list items by list level, repeated for deeper levels
+	ListIndent float64  // Indent of list items per list level in points
+}
+
+// DefaultHTMLStyles returns HTMLStyles that use the same styles as DefaultMarkdownStyles:
+// h1 to h6 are "Heading 1" to "Heading 6", blockquote is "Quote", pre is "Code Block", b and
+// strong are "Strong", i and em are "Emphasis", code is "Code" and a is "Link". u, s, sup,
+// sub and small caps are set as character features
+func DefaultHTMLStyles() HTMLStyles {
+	md := DefaultMarkdownStyles()
+	styles := HTMLStyles{
+		BlockTags: map[string]string{"blockquote": md.Quote, "pre": md.CodeBlock},
+		InlineTags: map[string]ITEXT{
+			"b": md.Strong, "strong": md.Strong, "i": md.Emphasis, "em": md.Emphasis, "cite": md.Emphasis,
+			"code": md.Code, "kbd": md.Code, "tt": md.Code, "a": md.Link,
+			"u": {FEATURES: "inherit " + FeatureUnderline}, "ins": {FEATURES: "inherit " + FeatureUnderline},
+			"s": {FEATURES: "inherit " + FeatureStrike}, "strike": {FEATURES: "inherit " + FeatureStrike}, "del": {FEATURES: "inherit " + FeatureStrike},
+			"sup": {FEATURES: "inherit " + FeatureSuperscript}, "sub": {FEATURES: "inherit " + FeatureSubscript},
+		},
+		Bullets:    md.Bullets,
+		ListIndent: md.ListIndent,
+	}
+	for i, name := range md.Headings {
+		styles.BlockTags["h"+strconv.Itoa(i+1)] = name
+	}
+	return styles
+}
+
+// EnsureHTMLStyles creates the paragraph and character styles that styles refers to
+// and that do not exist in the document yet (see EnsureParagraphStyle and EnsureCharStyle).
+// The character styles of b and strong get a bold font, those of i, em and cite an italic one
+func (doc *DOCUMENT) EnsureHTMLStyles(styles HTMLStyles) {
+	paragraphStyles := []string{styles.Paragraph, styles.ListItem}
+	for _, name := range styles.BlockTags {
+		paragraphStyles = append(paragraphStyles, name)
+	}
+	for _, name := range styles.ParagraphClasses {
+		paragraphStyles = append(paragraphStyles, name)
+	}
+	for _, name := range paragraphStyles {
+		if name != "" {
+			doc.EnsureParagraphStyle(name)
+		}
+	}
+	for tag, format := range styles.InlineTags {
+		doc.ensureFontStyle(format.CPARENT, htmlBoldTags[tag], htmlItalicTags[tag])
+	}
+	for _, format := range styles.CharClasses {
+		doc.ensureFontStyle(format.CPARENT, false, false)
+	}
+}
+
+// Inline tags whose new character styles get a bold or italic font in EnsureHTMLStyles
+var (
+	htmlBoldTags   = map[string]bool{"b": true, "strong": true}
+	htmlItalicTags = map[string]bool{"i": true, "em": true, "cite": true}
+)
+
+// HTMLToParagraphs converts an HTML fragment into paragraphs (see StoryText.SetParagraphs).
+// Block elements (p, h1 to h6, li, div, blockquote, pre, ...) become paragraphs, ul, ol
+// and li become bullet and numbered list items, br becomes a 'breakline' and inline
+// elements get the character formats of their tag, their classes and the font-weight,
+// font-style, font-size, text-decoration, vertical-align, font-variant, text-transform,
+// color and background-color of their style attribute. Block elements get the text-align
+// of their style attribute. White space is collapsed the way a browser does, except
+// inside pre. All other markup is dropped: the text of unknown elements is kept, the
+// content of script, style and similar elements, comments and images are removed.
+// Returns the paragraphs and the links (a href) in them
+func HTMLToParagraphs(fragment string, styles HTMLStyles) ([]Paragraph, []TextLink) {
+	c := htmlConverter{styles: styles}
+	style := Para{Bullet: "0", Numeration: "0", PARENT: styles.Paragraph}
+	c.blocks = []htmlBlock{{style: style, next: style}}
+	c.elements = []htmlElement{{}}
+	for _, token := range tokenizeHTML(fragment) {
+		switch token.kind {
+		case htmlText:
+			c.text(token.data)
+		case htmlStartTag:
+			c.start(token)
+			if htmlVoidElements[token.data] || token.selfClosing {
+				c.end(token.data)
+			}
+		case htmlEndTag:
+			c.end(token.data)
+		}
+	}
+	c.endParagraph()
+	return c.paragraphs, c.links
+}
+
+// SetHTML replaces the content of the StoryText with the converted HTML fragment and
+// returns the links in it (see HTMLToParagraphs)
+func (st *StoryText) SetHTML(fragment string, styles HTMLStyles) []TextLink {
+	paragraphs, links := HTMLToParagraphs(fragment, styles)
+	st.SetParagraphs(paragraphs)
+	return links
+}
+
+// SetHTML replaces the text of the Story with the converted HTML fragment and returns
+// the links in it (see TextLink). The text is put into the first frame, like in SetText
+func (s Story) SetHTML(fragment string, styles HTMLStyles) []TextLink {
+	if len(s.Frames) == 0 {
+		return nil
+	}
+	s.SetText("")
+	return s.Frames[0].StoryText.SetHTML(fragment, styles)
+}
+
+// Kinds of HTML tokens
+const (
+	htmlText = iota
+	htmlStartTag
+	htmlEndTag
+)
+
+type htmlToken struct {
+	kind        int
+	data        string // Text or lower case tag name
+	attrs       map[string]string
+	selfClosing bool
+}
+
+var (
+	// htmlVoidElements have no end tag
+	htmlVoidElements = map[string]bool{
+		"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true, "input": true,
+		"link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+	}
+	// htmlRawElements are dropped with their content
+	htmlRawElements = map[string]bool{
+		"script": true, "style": true, "template": true, "title": true, "textarea": true, "noscript": true,
+		"iframe": true, "object": true, "svg": true, "math": true, "head": true, "select": true,
+	}
+	// htmlBlockElements end the current paragraph
+	htmlBlockElements = map[string]bool{
+		"address": true, "article": true, "aside": true, "blockquote": true, "dd": true, "div": true, "dl": true,
+		"dt": true, "figcaption": true, "figure": true, "footer": true, "h1": true, "h2": true, "h3": true,
+		"h4": true, "h5": true, "h6": true, "header": true, "hr": true, "li": true, "main": true, "nav": true,
+		"ol": true, "p": true, "pre": true, "section": true, "table": true, "tr": true, "ul": true, "body": true, "html": true,
+	}
+	// htmlClosesParagraph are the block elements that implicitly close an open p
+	htmlClosesParagraph = map[string]bool{
+		"p": true, "div": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "ul": true,
+		"ol": true, "pre": true, "blockquote": true, "table": true, "hr": true, "dl": true, "section": true,
+	}
+	htmlAlignments = map[string]string{"left": "0", "start": "0", "center": "1", "right": "2", "end": "2", "justify": "3"}
+)
+
+// tokenizeHTML splits an HTML fragment into text, start and end tags. Comments, doctypes
+// and processing instructions are dropped, as are the content of htmlRawElements.
+// Character references in text and attribute values are resolved
+func tokenizeHTML(s string) []htmlToken {
+	var tokens []htmlToken
+	text := func(t string) {
+		if t == "" {
+			return
+		}
+		t = html.UnescapeString(t)
+		if n := len(tokens); n > 0 && tokens[n-1].kind == htmlText {
+			tokens[n-1].data += t
+			return
+		}
+		tokens = append(tokens, htmlToken{kind: htmlText, data: t})
+	}
+
+	for len(s) > 0 {
+		lt := strings.IndexByte(s, '<')
+		if lt < 0 {
+			text(s)
+			break
+		}
+		text(s[:lt])
+		s = s[lt:]
+		switch {
+		case strings.HasPrefix(s, "<!--"):
+			end := strings.Index(s[4:], "-->")
+			if end < 0 {
+				return tokens
+			}
+			s = s[4+end+3:]
+		case strings.HasPrefix(s, "<!") || strings.HasPrefix(s, "<?"):
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				return tokens
+			}
+			s = s[end+1:]
+		case strings.HasPrefix(s, "</") && len(s) > 2 && isASCIILetter(s[2]):
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				return tokens
+			}
+			name, _ := htmlTagName(s[2:end])
+			tokens = append(tokens, htmlToken{kind: htmlEndTag, data: name})
+			s = s[end+1:]
+		case len(s) > 1 && isASCIILetter(s[1]):
+			token, length := parseHTMLStartTag(s)
+			if length == 0 {
+				return tokens
+			}
+			s = s[length:]
+			if htmlRawElements[token.data] && !token.selfClosing {
+				end := strings.Index(strings.ToLower(s), "</"+token.data)
+				if end < 0 {
+					return tokens
+				}
+				s = s[end:]
+				if gt := strings.IndexByte(s, '>'); gt >= 0 {
+					s = s[gt+1:]
+				} else {
+					s = ""
+				}
+				continue
+			}
+			tokens = append(tokens, token)
+		default:
+			text("<")
+			s = s[1:]
+		}
+	}
+	return tokens
+}
+
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// htmlTagName returns the lower case tag name at the start of s and its length
+func htmlTagName(s string) (string, int) {
+	n := 0
+	for n < len(s) && !strings.ContainsRune(" \t\n\r\f/>", rune(s[n])) {
+		n++
+	}
+	return strings.ToLower(s[:n]), n
+}
+
+// parseHTMLStartTag parses the start tag at the start of s and returns it and its length,
+// or a length of 0 if the tag is not closed
+func parseHTMLStartTag(s string) (htmlToken, int) {
+	name, n := htmlTagName(s[1:])
+	token := htmlToken{kind: htmlStartTag, data: name, attrs: map[string]string{}}
+	i := 1 + n
+	for i < len(s) {
+		switch c := s[i]; {
+		case c == '>':
+			return token, i + 1
+		case c == '/':
+			token.selfClosing = i+1 < len(s) && s[i+1] == '>'
+			i++
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+		default:
+			start := i
+			for i < len(s) && !strings.ContainsRune(" \t\n\r\f/>=", rune(s[i])) {
+				i++
+			}
+			key := strings.ToLower(s[start:i])
+			for i < len(s) && strings.ContainsRune(" \t\n\r\f", rune(s[i])) {
+				i++
+			}
+			value := ""
+			if i < len(s) && s[i] == '=' {
+				i++
+				for i < len(s) && strings.ContainsRune(" \t\n\r\f", rune(s[i])) {
+					i++
+				}
+				if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+					end := strings.IndexByte(s[i+1:], s[i])
+					if end < 0 {
+						return token, 0
+					}
+					value = s[i+1 : i+1+end]
+					i += end + 2
+				} else {
+					start := i
+					for i < len(s) && !strings.ContainsRune(" \t\n\r\f>", rune(s[i])) {
+						i++
+					}
+					value = s[start:i]
+				}
+			}
+			if _, ok := token.attrs[key]; !ok && key != "" {
+				token.attrs[key] = html.UnescapeString(value)
+			}
+			token.selfClosing = false
+		}
+	}
+	return token, 0
+}
+
+// htmlElement is an open element
+type htmlElement struct {
+	tag    string
+	format ITEXT // Character format of the content
+	block  bool  // Whether the element pushed a htmlBlock
+	list   bool  // Whether the element pushed a htmlList
+	link   bool  // Whether the element is a link
+	pre    bool  // Whether the element is a pre
+}
+
+// htmlBlock is an open block element
+type htmlBlock struct {
+	style   Para // Style of the first paragraph of the block
+	next    Para // Style of the further paragraphs, e.g., those after the first paragraph of a li
+	started bool // Whether the first paragraph has been started
+}
+
+// htmlList is an open ul or ol
+type htmlList struct {
+	ordered bool
+	count   int
+	start   int
+}
+
+type htmlConverter struct {
+	styles      HTMLStyles
+	paragraphs  []Paragraph
+	links       []TextLink
+	elements    []htmlElement
+	blocks      []htmlBlock
+	lists       []htmlList
+	current     *Paragraph // Paragraph that text is added to, nil between paragraphs
+	last        rune       // Last character of the current paragraph
+	space       bool       // Whether collapsed white space is pending
+	spaceFormat ITEXT      // Character format of the pending white space
+	pre         int        // Number of open pre elements
+	link        int        // Index+1 of the TextLink of the open a element, or 0
+}
+
+// startParagraph starts a paragraph in the innermost block if there is none
+func (c *htmlConverter) startParagraph() {
+	if c.current != nil {
+		return
+	}
+	block := &c.blocks[len(c.blocks)-1]
+	style := block.next
+	if !block.started {
+		style = block.style
+		block.started = true
+	}
+	c.paragraphs = append(c.paragraphs, Paragraph{Style: style})
+	c.current = &c.paragraphs[len(c.paragraphs)-1]
+	c.last = 0
+	c.space = false
+}
+
+// endParagraph ends the current paragraph; pending white space is dropped
+func (c *htmlConverter) endParagraph() {
+	c.current = nil
+	c.space = false
+}
+
+// add adds text with format to the current paragraph
+func (c *htmlConverter) add(text string, format ITEXT) {
+	if text == "" {
+		return
+	}
+	c.startParagraph()
+	addMergedRun(c.current, text, format)
+	for _, r := range text {
+		c.last = r
+	}
+	if c.link > 0 {
+		link := &c.links[c.link-1]
+		link.Text += text
+		if link.Paragraph < 0 {
+			link.Paragraph = len(c.paragraphs) - 1
+		}
+	}
+}
+
+// text adds text to the current paragraph, collapsing white space outside pre
+func (c *htmlConverter) text(text string) {
+	format := c.format()
+	if c.pre > 0 {
+		c.add(strings.NewReplacer("\r\n", "\u2028", "\n", "\u2028", "\r", "\u2028").Replace(text), format)
+		return
+	}
+	var sb strings.Builder
+	for _, r := range text {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' {
+			if !c.space {
+				c.space = true
+				c.spaceFormat = format
+			}
+			continue
+		}
+		c.startParagraph()
+		if c.space && c.last != 0 && c.last != '\u2028' && c.last != '\t' {
+			// The collapsed white space keeps the format of where it started
+			c.add(sb.String(), format)
+			sb.Reset()
+			c.add(" ", c.spaceFormat)
+		}
+		c.space = false
+		sb.WriteRune(r)
+		c.last = r
+	}
+	c.add(sb.String(), format)
+}
+
+// format returns the character format of the innermost open element
+func (c *htmlConverter) format() ITEXT {
+	return c.elements[len(c.elements)-1].format
+}
+
+// start handles a start tag
+func (c *htmlConverter) start(token htmlToken) {
+	tag := token.data
+	if htmlClosesParagraph[tag] {
+		c.closeOpen("p", "li", "ul", "ol", "td", "th")
+	}
+	if tag == "li" {
+		c.closeOpen("li", "ul", "ol")
+	}
+
+	element := htmlElement{tag: tag, format: c.elements[len(c.elements)-1].format}
+	if f, ok := c.styles.InlineTags[tag]; ok {
+		element.format = mergeFormat(element.format, f)
+	}
+	for _, class := range strings.Fields(token.attrs["class"]) {
+		if f, ok := c.styles.CharClasses[class]; ok {
+			element.format = mergeFormat(element.format, f)
+		}
+	}
+	css := parseCSS(token.attrs["style"])
+	element.format = mergeFormat(element.format, c.cssFormat(css))
+	c.elements = append(c.elements, element)
+	current := &c.elements[len(c.elements)-1]
+
+	switch {
+	case tag == "br":
+		c.add("\u2028", element.format)
+		c.space = false
+	case tag == "ul" || tag == "ol":
+		c.endParagraph()
+		list := htmlList{ordered: tag == "ol", start: 1}
+		if n, err := strconv.Atoi(token.attrs["start"]); err == nil && list.ordered {
+			list.start = n
+		}
+		c.lists = append(c.lists, list)
+		current.list = true
+	case htmlBlockElements[tag]:
+		c.endParagraph()
+		parent := &c.blocks[len(c.blocks)-1]
+		style := parent.next
+		if !parent.started {
+			// The first paragraph of, e.g., a li is inside a p
+			style = parent.style
+			parent.started = true
+		}
+		if name, ok := c.styles.BlockTags[tag]; ok {
+			style.PARENT = name
+		}
+		for _, class := range strings.Fields(token.attrs["class"]) {
+			if name, ok := c.styles.ParagraphClasses[class]; ok {
+				style.PARENT = name
+			}
+		}
+		block := htmlBlock{style: style, next: style}
+		if tag == "li" && len(c.lists) > 0 {
+			list := &c.lists[len(c.lists)-1]
+			level := len(c.lists) - 1
+			list.count++
+			start := 0
+			if list.count == 1 && list.ordered {
+				start = list.start
+			}
+			block.style = listItemStyle(level, list.ordered, bulletFor(c.styles.Bullets, level), start, ".", c.styles.ListIndent)
+			block.next = listParagraphStyle(level, c.styles.ListIndent)
+			block.style.PARENT, block.next.PARENT = c.styles.ListItem, c.styles.ListItem
+			if name, ok := c.styles.BlockTags["li"]; ok {
+				block.style.PARENT, block.next.PARENT = name, name
+			}
+		}
+		if align, ok := htmlAlignments[strings.ToLower(css["text-align"])]; ok {
+			block.style.ALIGN, block.next.ALIGN = align, align
+		}
+		c.blocks = append(c.blocks, block)
+		current.block = true
+		if tag == "pre" {
+			current.pre = true
+			c.pre++
+		}
+	case tag == "a":
+		if href, ok := token.attrs["href"]; ok && c.link == 0 {
+			c.links = append(c.links, TextLink{URL: href, Paragraph: -1})
+			c.link = len(c.links)
+			current.link = true
+		}
+	case tag == "td" || tag == "th":
+		// Cells of a row are separated by tabs
+		if c.current != nil && c.last != 0 {
+			c.add("\t", element.format)
+			c.space = false
+		}
+	}
+}
+
+// closeOpen closes the innermost open element tags[0], unless one of the other
+// tags is open inside of it
+func (c *htmlConverter) closeOpen(tags ...string) {
+	for i := len(c.elements) - 1; i > 0; i-- {
+		if c.elements[i].tag == tags[0] {
+			c.end(tags[0])
+			return
+		}
+		for _, tag := range tags[1:] {
+			if c.elements[i].tag == tag {
+				return
+			}
+		}
+	}
+}
+
+// end handles an end tag: it closes the innermost open element tag and all elements
+// inside of it. End tags of elements that are not open are dropped
+func (c *htmlConverter) end(tag string) {
+	i := len(c.elements) - 1
+	for i > 0 && c.elements[i].tag != tag {
+		i--
+	}
+	if i == 0 {
+		return
+	}
+	for len(c.elements) > i {
+		element := c.elements[len(c.elements)-1]
+		c.elements = c.elements[:len(c.elements)-1]
+		if element.block {
+			c.endParagraph()
+			c.blocks = c.blocks[:len(c.blocks)-1]
+		}
+		if element.list {
+			c.endParagraph()
+			c.lists = c.lists[:len(c.lists)-1]
+		}
+		if element.pre {
+			c.pre--
+		}
+		if element.link {
+			c.links[c.link-1].Text = strings.TrimSpace(c.links[c.link-1].Text)
+			c.link = 0
+		}
+	}
+}
+
+// parseCSS parses the declarations of a style attribute into lower case properties and values
+func parseCSS(style string) map[string]string {
+	css := map[string]string{}
+	for _, declaration := range strings.Split(style, ";") {
+		property, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!important"))
+		css[strings.ToLower(strings.TrimSpace(property))] = value
+	}
+	return css
+}
+
+// cssFormat returns the character format of the supported CSS properties
+func (c *htmlConverter) cssFormat(css map[string]string) ITEXT {
+	var format ITEXT
+	switch weight := strings.ToLower(css["font-weight"]); weight {
+	case "bold", "bolder", "600", "700", "800", "900":
+		format = mergeFormat(format, c.styles.InlineTags["b"])
+	}
+	switch strings.ToLower(css["font-style"]) {
+	case "italic", "oblique":
+		format = mergeFormat(format, c.styles.InlineTags["i"])
+	}
+	decoration := strings.ToLower(css["text-decoration"] + " " + css["text-decoration-line"])
+	if strings.Contains(decoration, "underline") {
+		format.SetFeature(FeatureUnderline, true)
+	}
+	if strings.Contains(decoration, "line-through") {
+		format.SetFeature(FeatureStrike, true)
+	}
+	switch strings.ToLower(css["vertical-align"]) {
+	case "super":
+		format.SetFeature(FeatureSuperscript, true)
+	case "sub":
+		format.SetFeature(FeatureSubscript, true)
+	}
+	if strings.ToLower(css["font-variant"]) == "small-caps" {
+		format.SetFeature(FeatureSmallCaps, true)
+	}
+	if strings.ToLower(css["text-transform"]) == "uppercase" {
+		format.SetFeature(FeatureAllCaps, true)
+	}
+	if size := cssFontSize(css["font-size"]); size > 0 {
+		format.SetFontSize(size)
+	}
+	if color, ok := c.styles.Colors[strings.ToLower(css["color"])]; ok {
+		format.FCOLOR = color
+	}
+	if color, ok := c.styles.Colors[strings.ToLower(css["background-color"])]; ok {
+		format.BGCOLOR = color
+	}
+	return format
+}
+
+// cssFontSize returns a CSS font size in pt, px or pt without unit in points, or 0
+func cssFontSize(size string) float64 {
+	size = strings.ToLower(strings.TrimSpace(size))
+	factor := 1.0
+	switch {
+	case strings.HasSuffix(size, "pt"):
+		size = strings.TrimSuffix(size, "pt")
+	case strings.HasSuffix(size, "px"):
+		size = strings.TrimSuffix(size, "px")
+		factor = 0.75
+	case strings.IndexFunc(size, unicode.IsLetter) >= 0 || strings.HasSuffix(size, "%"):
+		return 0
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(size), 64)
+	if err != nil || value <= 0 {
+		return 0
+	}
+	return value * factor
+}
@@ -0,0 +1,83 @@
+package scribus
+
+import (
+	"testing"
+)
+
+func TestHTMLToParagraphs(t *testing.T) {
+	styles := DefaultHTMLStyles()
+	styles.ParagraphClasses = map[string]string{"lead": "Lead"}
+	styles.CharClasses = map[string]ITEXT{"price": {CPARENT: "Price"}}
+	styles.Colors = map[string]string{"#ff0000": "Red"}
+
+	fragment := `<h2 style="text-align: center">Our   offer</h2>
+<p class="lead">Only <b>today</b>:
+   <span class=price>9&nbsp;EUR</span><br>
+   <span style="color:#FF0000;font-size:12px">red</span> <u>under</u><script>alert(1)</script><!-- comment --></p>
+<ul><li>One<li>Two<ul><li>Nested</li></ul></li></ul>
+<ol start="3"><li><p>Three</p><p>More</p></li><li>Four</li></ol>
+<blink>Unknown</blink> <a href="https://example.com">link</a>`
+	paragraphs, links := HTMLToParagraphs(fragment, styles)
+
+	texts := []string{"Our offer", "Only today: 9\u00a0EUR red under", "One", "Two", "Nested", "Three", "More", "Four", "Unknown link"}
+	if len(paragraphs) != len(texts) {
+		t.Fatalf("len(paragraphs) was incorrect, got: %v, want: %v.", len(paragraphs), len(texts))
+	}
+	for i, text := range texts {
+		if paragraphs[i].Text() != text {
+			t.Errorf("paragraphs[%v].Text() was incorrect, got: %q, want: %q.", i, paragraphs[i].Text(), text)
+		}
+	}
+
+	if s := paragraphs[0].Style; s.PARENT != "Heading 2" || s.ALIGN != "1" {
+		t.Errorf("heading style was incorrect, got: %+v", s)
+	}
+	if paragraphs[1].Style.PARENT != "Lead" {
+		t.Errorf("class style was incorrect, got: %v", paragraphs[1].Style.PARENT)
+	}
+	formats := map[string]ITEXT{}
+	for _, run := range paragraphs[1].Runs {
+		formats[run.CH] = run
+	}
+	if formats["today"].CPARENT != "Strong" || formats["9\u00a0EUR"].CPARENT != "Price" || !formats["under"].Underline() {
+		t.Errorf("character formats were incorrect, got: %+v", paragraphs[1].Runs)
+	}
+	if red := formats["red"]; red.FCOLOR != "Red" || red.FontSize() != 9 {
+		t.Errorf("span style was incorrect, got: %+v", red)
+	}
+
+	if s := paragraphs[2].Style; s.Bullet != "1" || s.BulletStr != "•" {
+		t.Errorf("bullet item style was incorrect, got: %+v", s)
+	}
+	if s := paragraphs[4].Style; s.BulletStr != "◦" || s.INDENT != "36" {
+		t.Errorf("nested item style was incorrect, got: %+v", s)
+	}
+	if s := paragraphs[5].Style; s.Numeration != "1" || s.NumerationStart != "3" {
+		t.Errorf("numbered item style was incorrect, got: %+v", s)
+	}
+	if s := paragraphs[6].Style; s.Numeration != "0" || s.INDENT != "18" {
+		t.Errorf("second paragraph of item style was incorrect, got: %+v", s)
+	}
+	if len(links) != 1 || links[0].Text != "link" || links[0].URL != "https://example.com" || links[0].Paragraph != 8 {
+		t.Errorf("links were incorrect, got: %+v", links)
+	}
+}
+
+func TestSetHTML(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	document.DOCUMENT.PAGEOBJECT[3].StoryText.SetHTML("<p>First<br>line</p><pre>a\n\tb</pre>", DefaultHTMLStyles())
+	clone, err := document.Clone()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	st := clone.DOCUMENT.PAGEOBJECT[3].StoryText
+	if st.PlainText() != "First line\na \tb" {
+		t.Errorf("PlainText() was incorrect, got: %q", st.PlainText())
+	}
+	if st.Trail.PARENT != "Code Block" {
+		t.Errorf("st.Trail.PARENT was incorrect, got: %v, want: %v.", st.Trail.PARENT, "Code Block")
+	}
+}
@@ -0,0 +1,896 @@
+package scribus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strings"
+	"unicode/utf16"
+)
+
+// http://www.color.org/specification/ICC1v43_2010-12.pdf
+// Only the parts of the ICC specification that are needed to convert solid colours
+// are implemented: matrix/TRC profiles (RGB and gray) and LUT based profiles
+// (lut8, lut16, lutAtoB and lutBtoA) such as the usual CMYK press profiles
+
+// RenderingIntent is an ICC rendering intent; the values match the ones Scribus
+// stores in DISc and DIIm
+type RenderingIntent int
+
+const (
+	Perceptual RenderingIntent = iota
+	RelativeColorimetric
+	Saturation
+	AbsoluteColorimetric
+)
+
+// D50 is the white point of the ICC profile connection space
+var D50 = [3]float64{0.9642, 1.0, 0.8249}
+
+// Profile is a parsed ICC profile
+type Profile struct {
+	Path        string
+	Description string
+	Class       string // e.g., "mntr", "prtr", "scnr", "spac"
+	ColorSpace  string // e.g., "RGB", "CMYK", "GRAY", "Lab"
+	PCS         string // "XYZ" or "Lab"
+	Version     uint32
+	WhitePoint  [3]float64
+
+	matrix *[3][3]float64 // device RGB to PCS XYZ, for matrix/TRC profiles
+	trc    []iccCurve     // rTRC, gTRC, bTRC or kTRC
+	aToB   [3]*iccLut     // A2B0, A2B1, A2B2
+	bToA   [3]*iccLut     // B2A0, B2A1, B2A2
+}
+
+// LoadProfile reads and parses the ICC profile at path
+func LoadProfile(path string) (*Profile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := ParseProfile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	p.Path = path
+	return p, nil
+}
+
+// ParseProfile parses an ICC profile from data
+func ParseProfile(data []byte) (*Profile, error) {
+	if len(data) < 132 || string(data[36:40]) != "acsp" {
+		return nil, errors.New("not an ICC profile")
+	}
+	p := &Profile{
+		Class:      string(data[12:16]),
+		ColorSpace: strings.TrimSpace(string(data[16:20])),
+		PCS:        strings.TrimSpace(string(data[20:24])),
+		Version:    binary.BigEndian.Uint32(data[8:12]),
+		WhitePoint: D50,
+	}
+
+	tags := map[string][]byte{}
+	count := int(binary.BigEndian.Uint32(data[128:132]))
+	for i := 0; i < count; i++ {
+		entry := 132 + 12*i
+		if entry+12 > len(data) {
+			return nil, errors.New("truncated tag table")
+		}
+		sig := string(data[entry : entry+4])
+		offset := int(binary.BigEndian.Uint32(data[entry+4:]))
+		size := int(binary.BigEndian.Uint32(data[entry+8:]))
+		if offset < 0 || size < 8 || offset+size > len(data) {
+			return nil, fmt.Errorf("tag %q out of range", sig)
+		}
+		tags[sig] = data[offset : offset+size]
+	}
+
+	if tag, ok := tags["desc"]; ok {
+		p.Description = parseTextTag(tag)
+	}
+	if tag, ok := tags["wtpt"]; ok {
+		if xyz, err := parseXYZTag(tag); err == nil {
+			p.WhitePoint = xyz
+		}
+	}
+
+	var err error
+	for i, sig := range []string{"A2B0", "A2B1", "A2B2"} {
+		if tag, ok := tags[sig]; ok {
+			if p.aToB[i], err = parseLut(tag); err != nil {
+				return nil, fmt.Errorf("%v: %v", sig, err)
+			}
+		}
+	}
+	for i, sig := range []string{"B2A0", "B2A1", "B2A2"} {
+		if tag, ok := tags[sig]; ok {
+			if p.bToA[i], err = parseLut(tag); err != nil {
+				return nil, fmt.Errorf("%v: %v", sig, err)
+			}
+		}
+	}
+
+	switch p.ColorSpace {
+	case "RGB":
+		r, okR := tags["rXYZ"]
+		g, okG := tags["gXYZ"]
+		b, okB := tags["bXYZ"]
+		if okR && okG && okB {
+			var m [3][3]float64
+			for col, tag := range [][]byte{r, g, b} {
+				xyz, err := parseXYZTag(tag)
+				if err != nil {
+					return nil, err
+				}
+				for row := 0; row < 3; row++ {
+					m[row][col] = xyz[row]
+				}
+			}
+			p.matrix = &m
+			for _, sig := range []string{"rTRC", "gTRC", "bTRC"} {
+				c, err := parseCurveTag(tags[sig])
+				if err != nil {
+					return nil, fmt.Errorf("%v: %v", sig, err)
+				}
+				p.trc = append(p.trc, c)
+			}
+		}
+	case "GRAY":
+		if tag, ok := tags["kTRC"]; ok {
+			c, err := parseCurveTag(tag)
+			if err != nil {
+				return nil, fmt.Errorf("kTRC: %v", err)
+			}
+			p.trc = []iccCurve{c}
+		}
+	}
+
+	if p.aToB[0] == nil && p.matrix == nil && p.trc == nil {
+		return nil, errors.New("profile has neither a matrix/TRC nor an A2B0 transform")
+	}
+	return p, nil
+}
+
+// Channels returns the number of device channels of the profile's colour space
+func (p *Profile) Channels() int {
+	switch p.ColorSpace {
+	case "GRAY":
+		return 1
+	case "CMYK":
+		return 4
+	}
+	return 3
+}
+
+// ToLab converts the device values in (each 0..1) to a D50 Lab colour using intent
+func (p *Profile) ToLab(in []float64, intent RenderingIntent) ([3]float64, error) {
+	if len(in) != p.Channels() {
+		return [3]float64{}, fmt.Errorf("%v profile expects %v channels, got %v", p.ColorSpace, p.Channels(), len(in))
+	}
+	var pcs [3]float64
+	isLab := p.PCS == "Lab"
+	if lut := p.lutFor(p.aToB, intent); lut != nil {
+		out := lut.apply(clampAll(in), false, isLab)
+		copy(pcs[:], decodePCS(out, lut.kind, isLab))
+	} else if p.matrix != nil {
+		var lin [3]float64
+		for i := range lin {
+			lin[i] = p.trc[i].eval(clamp01(in[i]))
+		}
+		pcs = mulMatrix(*p.matrix, lin)
+		isLab = false
+	} else if len(p.trc) == 1 {
+		y := p.trc[0].eval(clamp01(in[0]))
+		pcs = [3]float64{D50[0] * y, y, D50[2] * y}
+		isLab = false
+	} else {
+		return pcs, errors.New("profile cannot convert to PCS")
+	}
+
+	if intent == AbsoluteColorimetric {
+		if isLab {
+			pcs = XYZToLab(pcs)
+			isLab = false
+		}
+		pcs = p.relativeToAbsolute(pcs)
+	}
+	if !isLab {
+		pcs = XYZToLab(pcs)
+	}
+	return pcs, nil
+}
+
+// FromLab converts the D50 Lab colour lab to device values (each 0..1) using intent
+func (p *Profile) FromLab(lab [3]float64, intent RenderingIntent) ([]float64, error) {
+	if intent == AbsoluteColorimetric {
+		lab = XYZToLab(p.absoluteToRelative(LabToXYZ(lab)))
+	}
+	if lut := p.lutFor(p.bToA, intent); lut != nil {
+		isLab := p.PCS == "Lab"
+		var pcs [3]float64
+		if isLab {
+			pcs = lab
+		} else {
+			pcs = LabToXYZ(lab)
+		}
+		return clampAll(lut.apply(encodePCS(pcs, lut.kind, isLab), true, isLab)), nil
+	}
+	xyz := LabToXYZ(lab)
+	if p.matrix != nil {
+		inv, ok := invertMatrix(*p.matrix)
+		if !ok {
+			return nil, errors.New("profile matrix is not invertible")
+		}
+		lin := mulMatrix(inv, xyz)
+		out := make([]float64, 3)
+		for i := range out {
+			out[i] = invertCurve(p.trc[i], clamp01(lin[i]))
+		}
+		return out, nil
+	}
+	if len(p.trc) == 1 {
+		return []float64{invertCurve(p.trc[0], clamp01(xyz[1]))}, nil
+	}
+	return nil, errors.New("profile cannot convert from PCS")
+}
+
+func (p *Profile) lutFor(luts [3]*iccLut, intent RenderingIntent) *iccLut {
+	i := int(intent)
+	if intent == AbsoluteColorimetric {
+		i = int(RelativeColorimetric)
+	}
+	if i >= 0 && i < len(luts) && luts[i] != nil {
+		return luts[i]
+	}
+	return luts[0]
+}
+
+func (p *Profile) relativeToAbsolute(xyz [3]float64) [3]float64 {
+	for i := range xyz {
+		xyz[i] *= p.WhitePoint[i] / D50[i]
+	}
+	return xyz
+}
+
+func (p *Profile) absoluteToRelative(xyz [3]float64) [3]float64 {
+	for i := range xyz {
+		if p.WhitePoint[i] != 0 {
+			xyz[i] *= D50[i] / p.WhitePoint[i]
+		}
+	}
+	return xyz
+}
+
+// Transform converts colours from one profile to another
+type Transform struct {
+	Source      *Profile
+	Destination *Profile
+	Intent      RenderingIntent
+}
+
+// NewTransform returns a Transform from src to dst; a nil profile stands for D50 Lab
+func NewTransform(src, dst *Profile, intent RenderingIntent) *Transform {
+	return &Transform{Source: src, Destination: dst, Intent: intent}
+}
+
+// Convert converts the colour in (each channel 0..1, or L*a*b* for Lab)
+func (t *Transform) Convert(in []float64) ([]float64, error) {
+	var lab [3]float64
+	if t.Source == nil {
+		if len(in) != 3 {
+			return nil, fmt.Errorf("Lab expects 3 channels, got %v", len(in))
+		}
+		copy(lab[:], in)
+	} else {
+		var err error
+		if lab, err = t.Source.ToLab(in, t.Intent); err != nil {
+			return nil, err
+		}
+	}
+	if t.Destination == nil {
+		return lab[:], nil
+	}
+	return t.Destination.FromLab(lab, t.Intent)
+}
+
+// XYZToLab converts a D50 XYZ colour to Lab
+func XYZToLab(xyz [3]float64) [3]float64 {
+	f := func(t float64) float64 {
+		if t > 216.0/24389.0 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27.0*t + 16) / 116
+	}
+	fx := f(xyz[0] / D50[0])
+	fy := f(xyz[1] / D50[1])
+	fz := f(xyz[2] / D50[2])
+	return [3]float64{116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)}
+}
+
+// LabToXYZ converts a D50 Lab colour to XYZ
+func LabToXYZ(lab [3]float64) [3]float64 {
+	fy := (lab[0] + 16) / 116
+	fx := fy + lab[1]/500
+	fz := fy - lab[2]/200
+	f := func(t float64) float64 {
+		if t*t*t > 216.0/24389.0 {
+			return t * t * t
+		}
+		return (116*t - 16) * 27.0 / 24389.0
+	}
+	return [3]float64{f(fx) * D50[0], f(fy) * D50[1], f(fz) * D50[2]}
+}
+
+// iccCurve is a one-dimensional transfer function on 0..1
+type iccCurve interface {
+	eval(x float64) float64
+}
+
+type gammaCurve float64
+
+func (g gammaCurve) eval(x float64) float64 {
+	return math.Pow(x, float64(g))
+}
+
+type tableCurve []float64
+
+func (t tableCurve) eval(x float64) float64 {
+	return interpolate([]float64(t), x)
+}
+
+// parametricCurve is a 'para' curve; see table 65 of the ICC specification
+type parametricCurve struct {
+	function int
+	p        [7]float64
+}
+
+func (c parametricCurve) eval(x float64) float64 {
+	g, a, b, cc, d, e, f := c.p[0], c.p[1], c.p[2], c.p[3], c.p[4], c.p[5], c.p[6]
+	pow := func(v float64) float64 {
+		if v <= 0 {
+			return 0
+		}
+		return math.Pow(v, g)
+	}
+	switch c.function {
+	case 0:
+		return pow(x)
+	case 1:
+		if x >= -b/a {
+			return pow(a*x + b)
+		}
+		return 0
+	case 2:
+		if x >= -b/a {
+			return pow(a*x+b) + cc
+		}
+		return cc
+	case 3:
+		if x >= d {
+			return pow(a*x + b)
+		}
+		return cc * x
+	case 4:
+		if x >= d {
+			return pow(a*x+b) + e
+		}
+		return cc*x + f
+	}
+	return x
+}
+
+// invertCurve finds x so that c.eval(x) == y for a monotonic curve
+func invertCurve(c iccCurve, y float64) float64 {
+	lo, hi := 0.0, 1.0
+	increasing := c.eval(1) >= c.eval(0)
+	for i := 0; i < 40; i++ {
+		mid := (lo + hi) / 2
+		if (c.eval(mid) < y) == increasing {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// iccLut is one of the lut8, lut16, lutAtoB or lutBtoA tag types
+type iccLut struct {
+	kind      string // "mft1", "mft2", "mAB " or "mBA "
+	in, out   int
+	matrix    *[3][3]float64 // mft1/mft2, only used when the input is XYZ
+	matrix12  *[12]float64   // mAB/mBA
+	inCurves  []iccCurve     // mft1/mft2 input tables
+	outCurves []iccCurve     // mft1/mft2 output tables
+	aCurves   []iccCurve
+	mCurves   []iccCurve
+	bCurves   []iccCurve
+	clut      *iccCLUT
+}
+
+// apply runs the lut on normalized values; fromPCS tells whether the input side is the PCS
+func (l *iccLut) apply(in []float64, fromPCS bool, pcsIsLab bool) []float64 {
+	v := append([]float64(nil), in...)
+	switch l.kind {
+	case "mft1", "mft2":
+		if l.matrix != nil && fromPCS && !pcsIsLab && len(v) == 3 {
+			x := mulMatrix(*l.matrix, [3]float64{v[0], v[1], v[2]})
+			v = x[:]
+		}
+		v = applyCurves(l.inCurves, v)
+		if l.clut != nil {
+			v = l.clut.eval(v)
+		}
+		v = applyCurves(l.outCurves, v)
+	case "mAB ":
+		if l.clut != nil {
+			v = applyCurves(l.aCurves, v)
+			v = l.clut.eval(v)
+		}
+		if l.matrix12 != nil {
+			v = applyCurves(l.mCurves, v)
+			v = applyMatrix12(*l.matrix12, v)
+		}
+		v = applyCurves(l.bCurves, v)
+	case "mBA ":
+		v = applyCurves(l.bCurves, v)
+		if l.matrix12 != nil {
+			v = applyMatrix12(*l.matrix12, v)
+			v = applyCurves(l.mCurves, v)
+		}
+		if l.clut != nil {
+			v = l.clut.eval(v)
+			v = applyCurves(l.aCurves, v)
+		}
+	}
+	return v
+}
+
+func applyCurves(curves []iccCurve, v []float64) []float64 {
+	for i := range v {
+		if i < len(curves) && curves[i] != nil {
+			v[i] = clamp01(curves[i].eval(clamp01(v[i])))
+		}
+	}
+	return v
+}
+
+func applyMatrix12(m [12]float64, v []float64) []float64 {
+	if len(v) != 3 {
+		return v
+	}
+	out := make([]float64, 3)
+	for i := 0; i < 3; i++ {
+		out[i] = clamp01(m[3*i]*v[0] + m[3*i+1]*v[1] + m[3*i+2]*v[2] + m[9+i])
+	}
+	return out
+}
+
+// iccCLUT is a multi-dimensional colour lookup table with normalized entries
+type iccCLUT struct {
+	grid []int
+	out  int
+	data []float64
+}
+
+// eval interpolates the table multilinearly
+func (c *iccCLUT) eval(in []float64) []float64 {
+	n := len(c.grid)
+	out := make([]float64, c.out)
+	if len(in) < n {
+		return out
+	}
+	base := make([]int, n)
+	frac := make([]float64, n)
+	stride := make([]int, n)
+	s := c.out
+	for d := n - 1; d >= 0; d-- {
+		stride[d] = s
+		s *= c.grid[d]
+	}
+	for d := 0; d < n; d++ {
+		if c.grid[d] < 2 {
+			continue
+		}
+		x := clamp01(in[d]) * float64(c.grid[d]-1)
+		i := int(x)
+		if i >= c.grid[d]-1 {
+			i = c.grid[d] - 2
+		}
+		base[d] = i
+		frac[d] = x - float64(i)
+	}
+	for corner := 0; corner < 1<<uint(n); corner++ {
+		w := 1.0
+		offset := 0
+		for d := 0; d < n; d++ {
+			idx := base[d]
+			if corner&(1<<uint(d)) != 0 {
+				if c.grid[d] < 2 {
+					w = 0
+					break
+				}
+				idx++
+				w *= frac[d]
+			} else {
+				w *= 1 - frac[d]
+			}
+			offset += idx * stride[d]
+		}
+		if w == 0 {
+			continue
+		}
+		for o := 0; o < c.out; o++ {
+			out[o] += w * c.data[offset+o]
+		}
+	}
+	return out
+}
+
+// decodePCS converts normalized lut output to XYZ or Lab values
+func decodePCS(v []float64, kind string, isLab bool) []float64 {
+	out := make([]float64, 3)
+	if len(v) < 3 {
+		return out
+	}
+	if isLab {
+		scale := 1.0
+		if kind == "mft2" {
+			scale = 65535.0 / 65280.0 // ICC v2 legacy 16-bit Lab encoding
+		}
+		out[0] = v[0] * scale * 100
+		out[1] = v[1]*scale*255 - 128
+		out[2] = v[2]*scale*255 - 128
+		return out
+	}
+	for i := range out {
+		out[i] = v[i] * 65535.0 / 32768.0
+	}
+	return out
+}
+
+// encodePCS is the inverse of decodePCS
+func encodePCS(pcs [3]float64, kind string, isLab bool) []float64 {
+	out := make([]float64, 3)
+	if isLab {
+		scale := 1.0
+		if kind == "mft2" {
+			scale = 65535.0 / 65280.0
+		}
+		out[0] = clamp01(pcs[0] / 100 / scale)
+		out[1] = clamp01((pcs[1] + 128) / 255 / scale)
+		out[2] = clamp01((pcs[2] + 128) / 255 / scale)
+		return out
+	}
+	for i := range out {
+		out[i] = clamp01(pcs[i] * 32768.0 / 65535.0)
+	}
+	return out
+}
+
+func parseLut(tag []byte) (*iccLut, error) {
+	kind := string(tag[0:4])
+	switch kind {
+	case "mft1", "mft2":
+		return parseLut8or16(tag, kind)
+	case "mAB ", "mBA ":
+		return parseLutAB(tag, kind)
+	}
+	return nil, fmt.Errorf("unsupported lut type %q", kind)
+}
+
+func parseLut8or16(tag []byte, kind string) (*iccLut, error) {
+	if len(tag) < 52 {
+		return nil, errors.New("truncated lut")
+	}
+	l := &iccLut{kind: kind, in: int(tag[8]), out: int(tag[9])}
+	grid := int(tag[10])
+	var m [3][3]float64
+	for i := 0; i < 9; i++ {
+		m[i/3][i%3] = readS15Fixed16(tag[12+4*i:])
+	}
+	l.matrix = &m
+
+	inEntries, outEntries, size, pos := 256, 256, 1, 48
+	if kind == "mft2" {
+		inEntries = int(binary.BigEndian.Uint16(tag[48:]))
+		outEntries = int(binary.BigEndian.Uint16(tag[50:]))
+		size, pos = 2, 52
+	}
+	read := func(n int) ([]float64, error) {
+		if n < 0 || pos+n*size > len(tag) {
+			return nil, errors.New("truncated lut")
+		}
+		v := make([]float64, n)
+		for i := range v {
+			if size == 1 {
+				v[i] = float64(tag[pos+i]) / 255
+			} else {
+				v[i] = float64(binary.BigEndian.Uint16(tag[pos+2*i:])) / 65535
+			}
+		}
+		pos += n * size
+		return v, nil
+	}
+
+	for i := 0; i < l.in; i++ {
+		t, err := read(inEntries)
+		if err != nil {
+			return nil, err
+		}
+		l.inCurves = append(l.inCurves, tableCurve(t))
+	}
+	entries := l.out
+	gridDims := make([]int, l.in)
+	for i := range gridDims {
+		gridDims[i] = grid
+		entries *= grid
+	}
+	data, err := read(entries)
+	if err != nil {
+		return nil, err
+	}
+	l.clut = &iccCLUT{grid: gridDims, out: l.out, data: data}
+	for i := 0; i < l.out; i++ {
+		t, err := read(outEntries)
+		if err != nil {
+			return nil, err
+		}
+		l.outCurves = append(l.outCurves, tableCurve(t))
+	}
+	return l, nil
+}
+
+func parseLutAB(tag []byte, kind string) (*iccLut, error) {
+	if len(tag) < 32 {
+		return nil, errors.New("truncated lut")
+	}
+	l := &iccLut{kind: kind, in: int(tag[8]), out: int(tag[9])}
+	offB := int(binary.BigEndian.Uint32(tag[12:]))
+	offMatrix := int(binary.BigEndian.Uint32(tag[16:]))
+	offM := int(binary.BigEndian.Uint32(tag[20:]))
+	offCLUT := int(binary.BigEndian.Uint32(tag[24:]))
+	offA := int(binary.BigEndian.Uint32(tag[28:]))
+
+	// In lutAtoB the A side is the device (input) side, in lutBtoA it is the output side
+	nA, nB := l.in, l.out
+	if kind == "mBA " {
+		nA, nB = l.out, l.in
+	}
+
+	var err error
+	if offB != 0 {
+		if l.bCurves, err = parseCurveSequence(tag, offB, nB); err != nil {
+			return nil, err
+		}
+	}
+	if offM != 0 {
+		if l.mCurves, err = parseCurveSequence(tag, offM, nB); err != nil {
+			return nil, err
+		}
+	}
+	if offA != 0 {
+		if l.aCurves, err = parseCurveSequence(tag, offA, nA); err != nil {
+			return nil, err
+		}
+	}
+	if offMatrix != 0 {
+		if offMatrix+48 > len(tag) {
+			return nil, errors.New("truncated lut matrix")
+		}
+		var m [12]float64
+		for i := range m {
+			m[i] = readS15Fixed16(tag[offMatrix+4*i:])
+		}
+		l.matrix12 = &m
+	}
+	if offCLUT != 0 {
+		if offCLUT+20 > len(tag) {
+			return nil, errors.New("truncated clut")
+		}
+		inChannels, outChannels := l.in, l.out
+		grid := make([]int, inChannels)
+		entries := outChannels
+		for i := range grid {
+			grid[i] = int(tag[offCLUT+i])
+			entries *= grid[i]
+		}
+		precision := int(tag[offCLUT+16])
+		pos := offCLUT + 20
+		if precision != 1 && precision != 2 {
+			return nil, fmt.Errorf("invalid clut precision %v", precision)
+		}
+		if pos+entries*precision > len(tag) {
+			return nil, errors.New("truncated clut")
+		}
+		data := make([]float64, entries)
+		for i := range data {
+			if precision == 1 {
+				data[i] = float64(tag[pos+i]) / 255
+			} else {
+				data[i] = float64(binary.BigEndian.Uint16(tag[pos+2*i:])) / 65535
+			}
+		}
+		l.clut = &iccCLUT{grid: grid, out: outChannels, data: data}
+	}
+	return l, nil
+}
+
+func parseCurveSequence(tag []byte, offset int, n int) ([]iccCurve, error) {
+	var curves []iccCurve
+	for i := 0; i < n; i++ {
+		if offset+12 > len(tag) {
+			return nil, errors.New("truncated curve")
+		}
+		c, size, err := parseCurve(tag[offset:])
+		if err != nil {
+			return nil, err
+		}
+		curves = append(curves, c)
+		offset += (size + 3) &^ 3 // curves are 4-byte aligned
+	}
+	return curves, nil
+}
+
+func parseCurveTag(tag []byte) (iccCurve, error) {
+	if tag == nil {
+		return nil, errors.New("missing curve")
+	}
+	c, _, err := parseCurve(tag)
+	return c, err
+}
+
+// parseCurve parses a 'curv' or 'para' element and returns it along with its size in bytes
+func parseCurve(b []byte) (iccCurve, int, error) {
+	if len(b) < 12 {
+		return nil, 0, errors.New("truncated curve")
+	}
+	switch string(b[0:4]) {
+	case "curv":
+		n := int(binary.BigEndian.Uint32(b[8:]))
+		size := 12 + 2*n
+		if size > len(b) {
+			return nil, 0, errors.New("truncated curve")
+		}
+		switch n {
+		case 0:
+			return gammaCurve(1), size, nil
+		case 1:
+			return gammaCurve(float64(binary.BigEndian.Uint16(b[12:])) / 256), size, nil
+		}
+		t := make(tableCurve, n)
+		for i := range t {
+			t[i] = float64(binary.BigEndian.Uint16(b[12+2*i:])) / 65535
+		}
+		return t, size, nil
+	case "para":
+		function := int(binary.BigEndian.Uint16(b[8:]))
+		params := []int{1, 3, 4, 5, 7}
+		if function < 0 || function >= len(params) {
+			return nil, 0, fmt.Errorf("unknown parametric curve type %v", function)
+		}
+		size := 12 + 4*params[function]
+		if size > len(b) {
+			return nil, 0, errors.New("truncated curve")
+		}
+		c := parametricCurve{function: function}
+		for i := 0; i < params[function]; i++ {
+			c.p[i] = readS15Fixed16(b[12+4*i:])
+		}
+		return c, size, nil
+	}
+	return nil, 0, fmt.Errorf("unsupported curve type %q", string(b[0:4]))
+}
+
+func parseXYZTag(tag []byte) ([3]float64, error) {
+	var xyz [3]float64
+	if len(tag) < 20 || string(tag[0:4]) != "XYZ " {
+		return xyz, errors.New("invalid XYZ tag")
+	}
+	for i := range xyz {
+		xyz[i] = readS15Fixed16(tag[8+4*i:])
+	}
+	return xyz, nil
+}
+
+// parseTextTag reads 'desc' (ICC v2), 'mluc' (ICC v4) and 'text' tags
+func parseTextTag(tag []byte) string {
+	switch string(tag[0:4]) {
+	case "desc":
+		if len(tag) < 12 {
+			return ""
+		}
+		n := int(binary.BigEndian.Uint32(tag[8:]))
+		if 12+n > len(tag) {
+			return ""
+		}
+		return strings.TrimRight(string(tag[12:12+n]), "\x00")
+	case "mluc":
+		if len(tag) < 28 {
+			return ""
+		}
+		length := int(binary.BigEndian.Uint32(tag[20:]))
+		offset := int(binary.BigEndian.Uint32(tag[24:]))
+		if offset+length > len(tag) {
+			return ""
+		}
+		u := make([]uint16, length/2)
+		for i := range u {
+			u[i] = binary.BigEndian.Uint16(tag[offset+2*i:])
+		}
+		return strings.TrimRight(string(utf16.Decode(u)), "\x00")
+	case "text":
+		return strings.TrimRight(string(tag[8:]), "\x00")
+	}
+	return ""
+}
+
+func readS15Fixed16(b []byte) float64 {
+	return float64(int32(binary.BigEndian.Uint32(b))) / 65536
+}
+
+func mulMatrix(m [3][3]float64, v [3]float64) [3]float64 {
+	var out [3]float64
+	for i := 0; i < 3; i++ {
+		out[i] = m[i][0]*v[0] + m[i][1]*v[1] + m[i][2]*v[2]
+	}
+	return out
+}
+
+func invertMatrix(m [3][3]float64) ([3][3]float64, bool) {
+	var inv [3][3]float64
+	det := m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+	if det == 0 {
+		return inv, false
+	}
+	inv[0][0] = (m[1][1]*m[2][2] - m[1][2]*m[2][1]) / det
+	inv[0][1] = (m[0][2]*m[2][1] - m[0][1]*m[2][2]) / det
+	inv[0][2] = (m[0][1]*m[1][2] - m[0][2]*m[1][1]) / det
+	inv[1][0] = (m[1][2]*m[2][0] - m[1][0]*m[2][2]) / det
+	inv[1][1] = (m[0][0]*m[2][2] - m[0][2]*m[2][0]) / det
+	inv[1][2] = (m[0][2]*m[1][0] - m[0][0]*m[1][2]) / det
+	inv[2][0] = (m[1][0]*m[2][1] - m[1][1]*m[2][0]) / det
+	inv[2][1] = (m[0][1]*m[2][0] - m[0][0]*m[2][1]) / det
+	inv[2][2] = (m[0][0]*m[1][1] - m[0][1]*m[1][0]) / det
+	return inv, true
+}
+
+// interpolate looks up x (0..1) in an evenly spaced table
+func interpolate(t []float64, x float64) float64 {
+	if len(t) == 0 {
+		return x
+	}
+	if len(t) == 1 {
+		return t[0]
+	}
+	pos := clamp01(x) * float64(len(t)-1)
+	i := int(pos)
+	if i >= len(t)-1 {
+		return t[len(t)-1]
+	}
+	f := pos - float64(i)
+	return t[i]*(1-f) + t[i+1]*f
+}
+
+func clamp01(x float64) float64 {
+	if x < 0 || math.IsNaN(x) {
+		return 0
+	}
+	if x > 1 {
+		return 1
+	}
+	return x
+}
+
+func clampAll(v []float64) []float64 {
+	out := make([]float64, len(v))
+	for i := range v {
+		out[i] = clamp01(v[i])
+	}
+	return out
+}
@@ -0,0 +1,195 @@
+package scribus
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// buildProfile assembles a minimal ICC profile from the given tags
+func buildProfile(colorSpace, pcs string, tags map[string][]byte) []byte {
+	var sigs []string
+	for _, sig := range []string{"desc", "wtpt", "rXYZ", "gXYZ", "bXYZ", "rTRC", "gTRC", "bTRC", "A2B0", "B2A0"} {
+		if _, ok := tags[sig]; ok {
+			sigs = append(sigs, sig)
+		}
+	}
+	header := make([]byte, 132+12*len(sigs))
+	copy(header[12:], "mntr")
+	copy(header[16:], colorSpace)
+	copy(header[20:], pcs)
+	copy(header[36:], "acsp")
+	binary.BigEndian.PutUint32(header[8:], 0x02100000)
+	binary.BigEndian.PutUint32(header[128:], uint32(len(sigs)))
+	data := header
+	for i, sig := range sigs {
+		for len(data)%4 != 0 {
+			data = append(data, 0)
+		}
+		entry := 132 + 12*i
+		copy(data[entry:], sig)
+		binary.BigEndian.PutUint32(data[entry+4:], uint32(len(data)))
+		binary.BigEndian.PutUint32(data[entry+8:], uint32(len(tags[sig])))
+		data = append(data, tags[sig]...)
+	}
+	binary.BigEndian.PutUint32(data[0:], uint32(len(data)))
+	return data
+}
+
+func s15(v float64) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(int32(math.Round(v*65536))))
+	return b
+}
+
+func xyzTag(x, y, z float64) []byte {
+	b := append([]byte("XYZ \x00\x00\x00\x00"), s15(x)...)
+	b = append(b, s15(y)...)
+	return append(b, s15(z)...)
+}
+
+func descTag(s string) []byte {
+	b := append([]byte("desc\x00\x00\x00\x00"), 0, 0, 0, byte(len(s)+1))
+	return append(append(b, s...), 0)
+}
+
+func gammaTag(g float64) []byte {
+	return []byte{'c', 'u', 'r', 'v', 0, 0, 0, 0, 0, 0, 0, 1, byte(int(g*256) >> 8), byte(int(g*256) & 0xff)}
+}
+
+func testRGBProfile() []byte {
+	return buildProfile("RGB ", "XYZ ", map[string][]byte{
+		"desc": descTag("Test RGB"),
+		"wtpt": xyzTag(D50[0], D50[1], D50[2]),
+		"rXYZ": xyzTag(sRGBToXYZD50[0][0], sRGBToXYZD50[1][0], sRGBToXYZD50[2][0]),
+		"gXYZ": xyzTag(sRGBToXYZD50[0][1], sRGBToXYZD50[1][1], sRGBToXYZD50[2][1]),
+		"bXYZ": xyzTag(sRGBToXYZD50[0][2], sRGBToXYZD50[1][2], sRGBToXYZD50[2][2]),
+		"rTRC": gammaTag(2.2),
+		"gTRC": gammaTag(2.2),
+		"bTRC": gammaTag(2.2),
+	})
+}
+
+// testCMYKProfile returns a lut16 CMYK profile with a 2x2x2x2 grid built from the naive conversion
+func testCMYKProfile() []byte {
+	u16 := func(v float64) []byte {
+		return []byte{byte(int(math.Round(clamp01(v)*65535)) >> 8), byte(int(math.Round(clamp01(v)*65535)) & 0xff)}
+	}
+	lut := []byte("mft2\x00\x00\x00\x00")
+	lut = append(lut, 4, 3, 2, 0)
+	for i := 0; i < 9; i++ {
+		if i%4 == 0 {
+			lut = append(lut, s15(1)...)
+		} else {
+			lut = append(lut, s15(0)...)
+		}
+	}
+	lut = append(lut, 0, 2, 0, 2)
+	for i := 0; i < 4; i++ {
+		lut = append(lut, u16(0)...)
+		lut = append(lut, u16(1)...)
+	}
+	for corner := 0; corner < 16; corner++ {
+		var cmyk [4]float64
+		for d := 0; d < 4; d++ {
+			if corner&(1<<uint(3-d)) != 0 {
+				cmyk[d] = 1
+			}
+		}
+		enc := encodePCS(sRGBToLab(naiveCMYKToRGB(cmyk)), "mft2", true)
+		for _, v := range enc {
+			lut = append(lut, u16(v)...)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		lut = append(lut, u16(0)...)
+		lut = append(lut, u16(1)...)
+	}
+	return buildProfile("CMYK", "Lab ", map[string][]byte{
+		"desc": descTag("Test CMYK"),
+		"A2B0": lut,
+	})
+}
+
+func TestMatrixProfile(t *testing.T) {
+	p, err := ParseProfile(testRGBProfile())
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if p.Description != "Test RGB" || p.ColorSpace != "RGB" {
+		t.Errorf("header was incorrect, got: %q %q", p.Description, p.ColorSpace)
+	}
+	lab, err := p.ToLab([]float64{1, 1, 1}, Perceptual)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if math.Abs(lab[0]-100) > 0.1 || math.Abs(lab[1]) > 0.1 || math.Abs(lab[2]) > 0.1 {
+		t.Errorf("white was incorrect, got: %v", lab)
+	}
+	rgb, err := p.FromLab(lab, Perceptual)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	for _, v := range rgb {
+		if math.Abs(v-1) > 0.001 {
+			t.Errorf("round trip was incorrect, got: %v", rgb)
+		}
+	}
+}
+
+func TestLutProfile(t *testing.T) {
+	p, err := ParseProfile(testCMYKProfile())
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	lab, err := p.ToLab([]float64{0, 1, 1, 0}, RelativeColorimetric)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	want := sRGBToLab([3]float64{1, 0, 0})
+	for i := range lab {
+		if math.Abs(lab[i]-want[i]) > 0.5 {
+			t.Errorf("lab was incorrect, got: %v, want: %v.", lab, want)
+		}
+	}
+}
+
+func TestConvertRGBColorsToCMYK(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "rgb.icc"), testRGBProfile(), 0644); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := DOCUMENT{
+		DPIn:     "Test RGB",
+		DPInCMYK: "Missing CMYK",
+		COLOR: []COLOR{
+			{NAME: "Red", RGB: "#ff0000"},
+			{NAME: "Black", CMYK: "#000000ff"},
+		},
+	}
+	cm, err := doc.NewColorManager(dir)
+	if err == nil {
+		t.Errorf("expected an error for the missing CMYK profile")
+	}
+	if cm.RGB == nil || cm.RGB.Description != "Test RGB" {
+		t.Errorf("RGB profile was not found")
+	}
+	doc.HCMS = "0"
+	if cm, err := doc.NewColorManager(dir); err != nil || cm.RGB != nil || cm.CMYK != nil {
+		t.Errorf("NewColorManager() without colour management should have no profiles, got: %+v, %v", cm, err)
+	}
+	doc.HCMS = ""
+
+	converted, err := doc.ConvertRGBColorsToCMYK(nil)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(converted) != 1 || converted[0] != "Red" {
+		t.Errorf("converted was incorrect, got: %v", converted)
+	}
+	if doc.COLOR[0].CMYK != "#00ffff00" || doc.COLOR[0].RGB != "" {
+		t.Errorf("doc.COLOR[0] was incorrect, got: %+v", doc.COLOR[0])
+	}
+}
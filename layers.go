@@ -0,0 +1,335 @@
+package scribus
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// BlendMode is the blend mode of a layer as stored in its BLEND attribute
+type BlendMode int
+
+const (
+	BlendNormal BlendMode = iota
+	BlendDarken
+	BlendLighten
+	BlendMultiply
+	BlendScreen
+	BlendOverlay
+	BlendHardLight
+	BlendSoftLight
+	BlendDifference
+	BlendExclusion
+	BlendColorDodge
+	BlendColorBurn
+	BlendHue
+	BlendSaturation
+	BlendColor
+	BlendLuminosity
+)
+
+// Number returns the number of the layer that PAGEOBJECT.LAYER refers to
+func (l Layer) Number() int {
+	n, _ := strconv.Atoi(l.NUMMER)
+	return n
+}
+
+// Level returns the stacking level of the layer, 0 is at the bottom
+func (l Layer) Level() int {
+	n, _ := strconv.Atoi(l.LEVEL)
+	return n
+}
+
+// Visible tells whether the layer is shown
+func (l Layer) Visible() bool { return l.SICHTBAR == "1" }
+
+// SetVisible shows or hides the layer
+func (l *Layer) SetVisible(visible bool) { l.SICHTBAR = boolAttr(visible) }
+
+// Printable tells whether the layer is printed and exported
+func (l Layer) Printable() bool { return l.DRUCKEN == "1" }
+
+// SetPrintable sets whether the layer is printed and exported
+func (l *Layer) SetPrintable(printable bool) { l.DRUCKEN = boolAttr(printable) }
+
+// Editable tells whether the items on the layer can be edited (i.e., it is not locked)
+func (l Layer) Editable() bool { return l.EDIT == "1" }
+
+// SetEditable locks or unlocks the layer
+func (l *Layer) SetEditable(editable bool) { l.EDIT = boolAttr(editable) }
+
+// Selectable tells whether items on the layer can be selected while another layer is active
+func (l Layer) Selectable() bool { return l.SELECT == "1" }
+
+// SetSelectable sets whether items on the layer can be selected while another layer is active
+func (l *Layer) SetSelectable(selectable bool) { l.SELECT = boolAttr(selectable) }
+
+// Flow tells whether text on lower layers flows around the items on this layer
+func (l Layer) Flow() bool { return l.FLOW == "1" }
+
+// SetFlow sets whether text on lower layers flows around the items on this layer
+func (l *Layer) SetFlow(flow bool) { l.FLOW = boolAttr(flow) }
+
+// Outline tells whether the layer is shown in outline mode
+func (l Layer) Outline() bool { return l.OUTL == "1" }
+
+// SetOutline sets whether the layer is shown in outline mode
+func (l *Layer) SetOutline(outline bool) { l.OUTL = boolAttr(outline) }
+
+// Opacity returns the opacity of the layer, 1 is opaque
+func (l Layer) Opacity() float64 {
+	if l.TRANS == "" {
+		return 1
+	}
+	return parseFloat(l.TRANS)
+}
+
+// SetOpacity sets the opacity of the layer (0..1)
+func (l *Layer) SetOpacity(opacity float64) { l.TRANS = formatFloat(clamp01(opacity)) }
+
+// BlendMode returns the blend mode of the layer
+func (l Layer) BlendMode() BlendMode {
+	n, _ := strconv.Atoi(l.BLEND)
+	return BlendMode(n)
+}
+
+// SetBlendMode sets the blend mode of the layer
+func (l *Layer) SetBlendMode(mode BlendMode) { l.BLEND = strconv.Itoa(int(mode)) }
+
+func boolAttr(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// GetLayerByName returns a pointer to the layer with the given name, or nil
+func (doc DOCUMENT) GetLayerByName(name string) *Layer {
+	for i := range doc.LAYERS {
+		if doc.LAYERS[i].NAME == name {
+			return &doc.LAYERS[i]
+		}
+	}
+	return nil
+}
+
+// GetLayerByNumber returns a pointer to the layer that PAGEOBJECT.LAYER values of
+// number refer to, or nil
+func (doc DOCUMENT) GetLayerByNumber(number int) *Layer {
+	for i := range doc.LAYERS {
+		if doc.LAYERS[i].Number() == number {
+			return &doc.LAYERS[i]
+		}
+	}
+	return nil
+}
+
+// LayersByLevel returns pointers to the layers ordered from bottom to top
+func (doc DOCUMENT) LayersByLevel() []*Layer {
+	layers := make([]*Layer, len(doc.LAYERS))
+	for i := range doc.LAYERS {
+		layers[i] = &doc.LAYERS[i]
+	}
+	sort.SliceStable(layers, func(i, j int) bool { return layers[i].Level() < layers[j].Level() })
+	return layers
+}
+
+// AddLayer adds a visible, printable and editable layer on top of all other
+// layers and returns a pointer to it, error
+func (doc *DOCUMENT) AddLayer(name string) (*Layer, error) {
+	if doc.GetLayerByName(name) != nil {
+		return nil, fmt.Errorf("layer %v already exists", name)
+	}
+	number, level := 0, 0
+	for _, l := range doc.LAYERS {
+		if l.Number() >= number {
+			number = l.Number() + 1
+		}
+		if l.Level() >= level {
+			level = l.Level() + 1
+		}
+	}
+	layerColors := []string{"#000000", "#ff0000", "#00ff00", "#0000ff", "#ff00ff", "#00ffff", "#ffff00"}
+	doc.LAYERS = append(doc.LAYERS, Layer{
+		NUMMER:   strconv.Itoa(number),
+		LEVEL:    strconv.Itoa(level),
+		NAME:     name,
+		SICHTBAR: "1",
+		DRUCKEN:  "1",
+		EDIT:     "1",
+		SELECT:   "0",
+		FLOW:     "1",
+		TRANS:    "1",
+		BLEND:    "0",
+		OUTL:     "0",
+		LAYERC:   layerColors[number%len(layerColors)],
+	})
+	return &doc.LAYERS[len(doc.LAYERS)-1], nil
+}
+
+// RenameLayer renames a layer, returns error
+func (doc *DOCUMENT) RenameLayer(name string, newName string) error {
+	l := doc.GetLayerByName(name)
+	if l == nil {
+		return fmt.Errorf("layer %v not found", name)
+	}
+	if name != newName && doc.GetLayerByName(newName) != nil {
+		return fmt.Errorf("layer %v already exists", newName)
+	}
+	l.NAME = newName
+	return nil
+}
+
+// DeleteLayer deletes a layer. If moveTo is the name of another layer, the items on the
+// deleted layer are moved there; if it is empty, they are deleted as well. Returns error
+func (doc *DOCUMENT) DeleteLayer(name string, moveTo string) error {
+	l := doc.GetLayerByName(name)
+	if l == nil {
+		return fmt.Errorf("layer %v not found", name)
+	}
+	if len(doc.LAYERS) < 2 {
+		return fmt.Errorf("cannot delete the only layer %v", name)
+	}
+	number := l.Number()
+
+	if moveTo != "" {
+		if moveTo == name {
+			return fmt.Errorf("cannot move the items of layer %v onto itself", name)
+		}
+		target := doc.GetLayerByName(moveTo)
+		if target == nil {
+			return fmt.Errorf("layer %v not found", moveTo)
+		}
+		for _, po := range doc.PageObjectsOnLayer(name) {
+			setPageObjectLayer(po, target.NUMMER)
+		}
+	} else {
+		for _, objs := range []*[]PAGEOBJECT{&doc.MASTEROBJECT, &doc.PAGEOBJECT} {
+			kept := (*objs)[:0]
+			for _, po := range *objs {
+				if po.LAYER != l.NUMMER {
+					kept = append(kept, po)
+				}
+			}
+			*objs = kept
+		}
+	}
+
+	layers := doc.LAYERS[:0]
+	for _, layer := range doc.LAYERS {
+		if layer.Number() != number {
+			layers = append(layers, layer)
+		}
+	}
+	doc.LAYERS = layers
+	doc.normalizeLayerLevels()
+
+	if doc.ALAYER == strconv.Itoa(number) {
+		doc.ALAYER = doc.LayersByLevel()[0].NUMMER
+	}
+	return nil
+}
+
+// SetLayerLevel moves a layer to the given level (0 is the bottom) and renumbers
+// the levels of the other layers accordingly, returns error
+func (doc *DOCUMENT) SetLayerLevel(name string, level int) error {
+	l := doc.GetLayerByName(name)
+	if l == nil {
+		return fmt.Errorf("layer %v not found", name)
+	}
+	if level < 0 || level >= len(doc.LAYERS) {
+		return fmt.Errorf("level %v out of range", level)
+	}
+	var ordered []*Layer
+	for _, layer := range doc.LayersByLevel() {
+		if layer != l {
+			ordered = append(ordered, layer)
+		}
+	}
+	ordered = append(ordered[:level], append([]*Layer{l}, ordered[level:]...)...)
+	for i, layer := range ordered {
+		layer.LEVEL = strconv.Itoa(i)
+	}
+	return nil
+}
+
+// RaiseLayer moves a layer one level up, returns error
+func (doc *DOCUMENT) RaiseLayer(name string) error {
+	l := doc.GetLayerByName(name)
+	if l == nil {
+		return fmt.Errorf("layer %v not found", name)
+	}
+	if l.Level() >= len(doc.LAYERS)-1 {
+		return nil
+	}
+	return doc.SetLayerLevel(name, l.Level()+1)
+}
+
+// LowerLayer moves a layer one level down, returns error
+func (doc *DOCUMENT) LowerLayer(name string) error {
+	l := doc.GetLayerByName(name)
+	if l == nil {
+		return fmt.Errorf("layer %v not found", name)
+	}
+	if l.Level() <= 0 {
+		return nil
+	}
+	return doc.SetLayerLevel(name, l.Level()-1)
+}
+
+// normalizeLayerLevels makes the layer levels contiguous again, e.g., after deleting a layer
+func (doc *DOCUMENT) normalizeLayerLevels() {
+	for i, l := range doc.LayersByLevel() {
+		l.LEVEL = strconv.Itoa(i)
+	}
+}
+
+// PageObjectsOnLayer returns pointers to the MASTEROBJECTs and PAGEOBJECTs on the layer with the given name
+func (doc DOCUMENT) PageObjectsOnLayer(name string) []*PAGEOBJECT {
+	var pos []*PAGEOBJECT
+	l := doc.GetLayerByName(name)
+	if l == nil {
+		return pos
+	}
+	for _, objs := range [][]PAGEOBJECT{doc.MASTEROBJECT, doc.PAGEOBJECT} {
+		for i := range objs {
+			if objs[i].LAYER == l.NUMMER {
+				pos = append(pos, &objs[i])
+			}
+		}
+	}
+	return pos
+}
+
+// MovePageObjectToLayer moves a PAGEOBJECT (and the items inside it, if it is a group)
+// to the layer with the given name, returns error
+func (doc DOCUMENT) MovePageObjectToLayer(po *PAGEOBJECT, name string) error {
+	l := doc.GetLayerByName(name)
+	if l == nil {
+		return fmt.Errorf("layer %v not found", name)
+	}
+	setPageObjectLayer(po, l.NUMMER)
+	return nil
+}
+
+// MoveLayerItems moves all items from one layer to another, returns error
+func (doc DOCUMENT) MoveLayerItems(from string, to string) error {
+	target := doc.GetLayerByName(to)
+	if target == nil {
+		return fmt.Errorf("layer %v not found", to)
+	}
+	if doc.GetLayerByName(from) == nil {
+		return fmt.Errorf("layer %v not found", from)
+	}
+	for _, po := range doc.PageObjectsOnLayer(from) {
+		setPageObjectLayer(po, target.NUMMER)
+	}
+	return nil
+}
+
+func setPageObjectLayer(po *PAGEOBJECT, number string) {
+	po.LAYER = number
+	walkPageObjects(po.PAGEOBJECT, func(child *PAGEOBJECT) {
+		child.LAYER = number
+	})
+}
@@ -0,0 +1,48 @@
+package scribus
+
+import (
+	"testing"
+)
+
+func TestLayers(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := &document.DOCUMENT
+
+	de, err := doc.AddLayer("DE")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if de.Number() != 1 || de.Level() != 1 || !de.Visible() || !de.Printable() {
+		t.Errorf("new layer was incorrect, got: %+v", *de)
+	}
+	if err := doc.MovePageObjectToLayer(&doc.PAGEOBJECT[0], "DE"); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc.MASTEROBJECT = append(doc.MASTEROBJECT, PAGEOBJECT{ItemID: "1", LAYER: de.NUMMER})
+	if len(doc.PageObjectsOnLayer("DE")) != 2 || len(doc.PageObjectsOnLayer("Background")) != 3 {
+		t.Errorf("items were not moved to layer DE")
+	}
+
+	if err := doc.SetLayerLevel("DE", 0); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if doc.GetLayerByName("Background").Level() != 1 {
+		t.Errorf("Background level was incorrect, got: %v, want: %v.", doc.GetLayerByName("Background").Level(), 1)
+	}
+
+	doc.GetLayerByName("DE").SetVisible(false)
+	doc.GetLayerByName("DE").SetBlendMode(BlendMultiply)
+	if doc.GetLayerByName("DE").SICHTBAR != "0" || doc.GetLayerByName("DE").BLEND != "3" {
+		t.Errorf("layer attributes were incorrect, got: %+v", *doc.GetLayerByName("DE"))
+	}
+
+	if err := doc.DeleteLayer("DE", ""); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(doc.LAYERS) != 1 || len(doc.PAGEOBJECT) != 3 || len(doc.MASTEROBJECT) != 0 || doc.LAYERS[0].LEVEL != "0" {
+		t.Errorf("layer DE was not deleted with its items")
+	}
+}
@@ -0,0 +1,144 @@
+package scribus
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// defaultBullets are the bullets of the list levels, repeated for deeper levels
+var defaultBullets = []string{"•", "◦", "▪"}
+
+// ListItem is an item of a bullet or numbered list, a paragraph with its list properties
+type ListItem struct {
+	Paragraph        // Text of the item; the list attributes of its Style are set from the other fields
+	Level     int    // 0 for the top level
+	Numbered  bool   // Numbered item, otherwise bullet item
+	BulletStr string // Bullet of bullet items, the bullet of the level (see List.Bullets) if empty
+	Format    string // NumerationFormat of numbered items, e.g., NumberLowerLetter; NumberDecimal if empty
+	Start     int    // Number of a numbered item that starts counting again, 0 to go on counting
+	Prefix    string // Text before the number
+	Suffix    string // Text after the number, e.g., "." or ")"
+	Label     string // Bullet or number as shown, e.g., "•" or "b)"; set by Lists
+}
+
+// List is a bullet or numbered list, which may mix both kinds of items and be nested
+type List struct {
+	Items     []ListItem
+	Indent    float64  // Indent per level in points, 18 if 0
+	Bullets   []string // Bullets of the levels, "•", "◦" and "▪" if empty
+	Paragraph int      // Index of the first paragraph of the list in the story; set by Lists
+}
+
+// style returns the paragraph attributes of the item
+func (item ListItem) style(indent float64, bullets []string) Para {
+	style := item.Style
+	style.INDENT = formatFloat(indent * float64(item.Level+1))
+	style.FIRST = formatFloat(-indent)
+	style.Bullet, style.BulletStr = "0", ""
+	style.Numeration, style.NumerationName, style.NumerationFormat, style.NumerationLevel = "0", "", "", ""
+	style.NumerationStart, style.NumerationRestart, style.NumerationPrefix, style.NumerationSuffix = "", "", "", ""
+	if !item.Numbered {
+		style.Bullet = "1"
+		style.BulletStr = item.BulletStr
+		if style.BulletStr == "" {
+			style.BulletStr = bulletFor(bullets, item.Level)
+		}
+		return style
+	}
+	style.Numeration = "1"
+	style.NumerationName = "<local block>"
+	style.NumerationFormat = item.Format
+	if style.NumerationFormat == "" {
+		style.NumerationFormat = NumberDecimal
+	}
+	style.NumerationLevel = strconv.Itoa(item.Level)
+	style.NumerationPrefix = item.Prefix
+	style.NumerationSuffix = item.Suffix
+	if item.Start > 0 {
+		style.NumerationStart = strconv.Itoa(item.Start)
+		style.NumerationRestart = "1"
+	}
+	return style
+}
+
+// Paragraphs returns the paragraphs of the list
+func (l List) Paragraphs() []Paragraph {
+	indent := l.Indent
+	if indent == 0 {
+		indent = 18
+	}
+	bullets := l.Bullets
+	if len(bullets) == 0 {
+		bullets = defaultBullets
+	}
+	paragraphs := make([]Paragraph, len(l.Items))
+	for i, item := range l.Items {
+		paragraphs[i] = Paragraph{Runs: item.Runs, Style: item.style(indent, bullets)}
+	}
+	return paragraphs
+}
+
+// Lists returns the lists of the StoryText, which are runs of paragraphs with bullets
+// or numbers, with their items
+func (st StoryText) Lists() []List {
+	var lists []List
+	var current *List
+	for i, ep := range listParagraphs(st.Paragraphs()) {
+		if !ep.list {
+			current = nil
+			continue
+		}
+		item := ListItem{Paragraph: ep.Paragraph, Level: ep.level, Numbered: ep.ordered, Label: ep.label}
+		style := ep.Style
+		if ep.ordered {
+			item.Format, item.Prefix, item.Suffix = style.NumerationFormat, style.NumerationPrefix, style.NumerationSuffix
+			if style.NumerationRestart == "1" {
+				item.Start, _ = strconv.Atoi(style.NumerationStart)
+			}
+		} else {
+			item.BulletStr = style.BulletStr
+		}
+		if current == nil {
+			lists = append(lists, List{Paragraph: i, Indent: parseFloat(style.INDENT) / float64(ep.level+1)})
+			current = &lists[len(lists)-1]
+		}
+		current.Items = append(current.Items, item)
+	}
+	return lists
+}
+
+// SetList replaces the text of the StoryText with a list
+func (st *StoryText) SetList(list List) {
+	st.SetParagraphs(list.Paragraphs())
+}
+
+// ReplaceList replaces the i'th list of the StoryText (see Lists) with list, returns error
+// if there is no such list
+func (st *StoryText) ReplaceList(i int, list List) error {
+	lists := st.Lists()
+	if i < 0 || i >= len(lists) {
+		return fmt.Errorf("list %v not found, the text has %v lists", i, len(lists))
+	}
+	old := lists[i]
+	paragraphs := st.Paragraphs()
+	result := append([]Paragraph{}, paragraphs[:old.Paragraph]...)
+	result = append(result, list.Paragraphs()...)
+	result = append(result, paragraphs[old.Paragraph+len(old.Items):]...)
+	st.SetParagraphs(result)
+	return nil
+}
+
+// InsertList inserts a list before the paragraph with the index paragraph, or after
+// the last paragraph if paragraph is the number of paragraphs; returns error if there
+// is no such paragraph
+func (st *StoryText) InsertList(paragraph int, list List) error {
+	paragraphs := st.Paragraphs()
+	if paragraph < 0 || paragraph > len(paragraphs) {
+		return fmt.Errorf("paragraph %v not found, the text has %v paragraphs", paragraph, len(paragraphs))
+	}
+	result := append([]Paragraph{}, paragraphs[:paragraph]...)
+	result = append(result, list.Paragraphs()...)
+	result = append(result, paragraphs[paragraph:]...)
+	st.SetParagraphs(result)
+	return nil
+}
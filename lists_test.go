@@ -0,0 +1,57 @@
+package scribus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLists(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	st := &document.DOCUMENT.PAGEOBJECT[3].StoryText
+	lists := st.Lists()
+	if len(lists) != 1 || len(lists[0].Items) != 3 || lists[0].Items[2].Text() != "Five" || lists[0].Items[2].Label != "•" || lists[0].Items[0].Numbered {
+		t.Fatalf("Lists() was incorrect, got: %+v", lists)
+	}
+
+	list := List{Indent: 20, Items: []ListItem{
+		{Paragraph: Paragraph{Runs: []ITEXT{NewRun("Fruit")}}},
+		{Paragraph: Paragraph{Runs: []ITEXT{NewRun("Apples")}}, Level: 1, Numbered: true, Format: NumberLowerLetter, Suffix: ")"},
+		{Paragraph: Paragraph{Runs: []ITEXT{NewRun("Pears")}}, Level: 1, Numbered: true, Format: NumberLowerLetter, Suffix: ")"},
+		{Paragraph: Paragraph{Runs: []ITEXT{NewRun("Vegetables")}}, BulletStr: "-"},
+		{Paragraph: Paragraph{Runs: []ITEXT{NewRun("Leeks")}}, Level: 1, Numbered: true, Format: NumberUpperRoman, Start: 4},
+		{Paragraph: Paragraph{Runs: []ITEXT{NewRun("Onions")}}, Level: 1, Numbered: true, Format: NumberUpperRoman},
+	}}
+	if err := st.ReplaceList(0, list); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if err := st.InsertList(0, List{Items: []ListItem{{Paragraph: Paragraph{Runs: []ITEXT{NewRun("Intro")}}, Numbered: true, Prefix: "(", Suffix: ")"}}}); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	paragraphs := st.Paragraphs()
+	st.SetParagraphs(append([]Paragraph{paragraphs[0], {Runs: []ITEXT{NewRun("Text")}}}, paragraphs[1:]...))
+	if err := st.ReplaceList(2, list); err == nil {
+		t.Errorf("ReplaceList() did not return an error for a list that does not exist")
+	}
+
+	clone, err := document.Clone()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	lists = clone.DOCUMENT.PAGEOBJECT[3].StoryText.Lists()
+	if len(lists) != 2 || lists[0].Items[0].Label != "(1)" || lists[1].Paragraph != 2 || lists[1].Indent != 20 {
+		t.Fatalf("Lists() was incorrect, got: %+v", lists)
+	}
+	var labels []string
+	for _, item := range lists[1].Items {
+		labels = append(labels, item.Label)
+	}
+	if got := strings.Join(labels, " "); got != "• a) b) - IV V" {
+		t.Errorf("labels were incorrect, got: %v", got)
+	}
+	if item := lists[1].Items[4]; item.Level != 1 || item.Start != 4 || item.Format != NumberUpperRoman || item.Style.INDENT != "40" || item.Style.FIRST != "-20" {
+		t.Errorf("item was incorrect, got: %+v", item)
+	}
+}
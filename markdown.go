@@ -0,0 +1,730 @@
+package scribus
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MarkdownStyles configures how Markdown is turned into StoryText. Paragraph styles are
+// the NAMEs of STYLEs, empty means no style of its own. The character formats are
+// applied to the runs, e.g., ITEXT{CPARENT: "Emphasis"} for a character style or
+// ITEXT{FONT: "FreeSans Oblique"} for a font variant
+type MarkdownStyles struct {
+	Paragraph string    // Paragraph style of body text
+	Headings  [6]string // Paragraph styles of the headings of level 1 to 6
+	Quote     string    // Paragraph style of block quotes
+	CodeBlock string    // Paragraph style of fenced and indented code blocks
+	ListItem  string    // Paragraph style of list items
+
+	Emphasis       ITEXT // Format of *emphasis*
+	Strong         ITEXT // Format of **strong** text
+	StrongEmphasis ITEXT // Format of ***strong emphasis***; if empty, Emphasis and Strong are combined
+	Code           ITEXT // Format of `code` and code blocks
+	Link           ITEXT // Format of the text of links
+
+	Bullets    []string // BulletStr of bullet list items by list level, repeated for deeper levels
+	ListIndent float64  // Indent of list items per list level in points
+	LinkURLs   bool     // Whether the URL is appended in parentheses after the text of a link
+}
+
+// DefaultMarkdownStyles returns MarkdownStyles that use paragraph styles named "Heading 1"
+// to "Heading 6", "Quote" and "Code Block" and character styles named "Emphasis",
+// "Strong", "Strong Emphasis", "Code" and "Link". Use DOCUMENT.EnsureMarkdownStyles to
+// create the styles that the document does not have yet; it makes "Emphasis" italic and
+// "Strong" bold
+func DefaultMarkdownStyles() MarkdownStyles {
+	return MarkdownStyles{
+		Headings:       [6]string{"Heading 1", "Heading 2", "Heading 3", "Heading 4", "Heading 5", "Heading 6"},
+		Quote:          "Quote",
+		CodeBlock:      "Code Block",
+		Emphasis:       ITEXT{CPARENT: "Emphasis"},
+		Strong:         ITEXT{CPARENT: "Strong"},
+		StrongEmphasis: ITEXT{CPARENT: "Strong Emphasis"},
+		Code:           ITEXT{CPARENT: "Code"},
+		Link:           ITEXT{CPARENT: "Link"},
+		Bullets:        append([]string{}, defaultBullets...),
+		ListIndent:     18,
+	}
+}
+
+// EnsureMarkdownStyles creates the paragraph and character styles that styles refers to
+// and that do not exist in the document yet (see EnsureParagraphStyle and EnsureCharStyle).
+// The character styles of Emphasis, Strong and StrongEmphasis get the italic, bold and
+// bold italic variant of the default font
+func (doc *DOCUMENT) EnsureMarkdownStyles(styles MarkdownStyles) {
+	paragraphStyles := append([]string{styles.Paragraph, styles.Quote, styles.CodeBlock, styles.ListItem}, styles.Headings[:]...)
+	for _, name := range paragraphStyles {
+		if name != "" {
+			doc.EnsureParagraphStyle(name)
+		}
+	}
+	doc.ensureFontStyle(styles.Emphasis.CPARENT, false, true)
+	doc.ensureFontStyle(styles.Strong.CPARENT, true, false)
+	doc.ensureFontStyle(styles.StrongEmphasis.CPARENT, true, true)
+	doc.ensureFontStyle(styles.Code.CPARENT, false, false)
+	doc.ensureFontStyle(styles.Link.CPARENT, false, false)
+}
+
+// ensureFontStyle creates the character style name if it is not empty and does not exist
+// yet (see EnsureCharStyle). A new style gets the bold and/or italic variant of its font
+func (doc *DOCUMENT) ensureFontStyle(name string, bold bool, italic bool) {
+	if name == "" || doc.GetCharStyle(name) != nil {
+		return
+	}
+	style := doc.EnsureCharStyle(name)
+	if bold || italic {
+		style.FONT = fontVariant(style.FONT, bold, italic)
+	}
+}
+
+// fontVariant returns the name of the variant of font (family and style, e.g., "FreeSans
+// Bold") that is bold and/or italic in addition to the style of font. Whether the variant
+// exists is not checked; Scribus replaces fonts that are missing
+func fontVariant(font string, bold bool, italic bool) string {
+	words := strings.Fields(font)
+	if len(words) == 0 {
+		return font
+	}
+	slant := "Italic"
+styles:
+	for len(words) > 1 {
+		switch last := words[len(words)-1]; last {
+		case "Bold":
+			bold = true
+		case "Italic", "Oblique":
+			italic, slant = true, last
+		case "Regular", "Roman", "Book", "Normal":
+		default:
+			break styles
+		}
+		words = words[:len(words)-1]
+	}
+	return strings.Join(append(words, fontStyle(bold, italic, slant)), " ")
+}
+
+// fontStyle returns the style part of a font name
+func fontStyle(bold bool, italic bool, slant string) string {
+	switch {
+	case bold && italic:
+		return "Bold " + slant
+	case bold:
+		return "Bold"
+	case italic:
+		return slant
+	}
+	return "Regular"
+}
+
+// TextLink is a link found in imported text. Scribus has no hyperlinks inside a story; a
+// link annotation (see PAGEOBJECT.SetLink) makes a whole item clickable. The text of a
+// link therefore gets the Link format, and the links are returned for the caller to place,
+// e.g., as link annotation frames over the link text, whose position is only known once
+// Scribus has laid out the text. The text frame itself is not made a link
+type TextLink struct {
+	Text      string
+	URL       string
+	Paragraph int // Index of the paragraph that contains the link
+}
+
+// Annotation type (ANTYPE) of links and action type (ANACTYP) of links to URLs
+const (
+	annotationLink = "11"
+	actionURI      = "8"
+)
+
+// SetLink makes the item a link annotation that opens url when it is clicked in PDFs.
+// An empty url removes the annotation
+func (po *PAGEOBJECT) SetLink(url string) {
+	if url == "" {
+		po.ANNOTATION, po.ANTYPE, po.ANACTYP, po.ANEXTERN = "", "", "", ""
+		return
+	}
+	po.ANNOTATION, po.ANTYPE, po.ANACTYP, po.ANEXTERN = "1", annotationLink, actionURI, url
+}
+
+// Link returns the URL that the item links to, "" if it is not a link annotation
+func (po PAGEOBJECT) Link() string {
+	if po.ANNOTATION != "1" || po.ANTYPE != annotationLink || po.ANACTYP != actionURI {
+		return ""
+	}
+	return po.ANEXTERN
+}
+
+// MarkdownToParagraphs converts Markdown into paragraphs (see StoryText.SetParagraphs).
+// Supported are ATX and setext headings, paragraphs, hard line breaks (two trailing
+// spaces or a backslash), which become 'breakline's, block quotes, fenced and indented
+// code blocks, nested bullet and numbered lists, emphasis, strong emphasis, code spans,
+// links, autolinks, images (as their alternative text), backslash escapes and HTML entities
+func MarkdownToParagraphs(markdown string, styles MarkdownStyles) ([]Paragraph, []TextLink) {
+	c := markdownConverter{styles: styles}
+	for _, b := range parseMarkdownBlocks(markdown) {
+		c.block(b)
+	}
+	return c.paragraphs, c.links
+}
+
+// SetMarkdown replaces the content of the StoryText with the converted Markdown and
+// returns the links in it (see MarkdownToParagraphs)
+func (st *StoryText) SetMarkdown(markdown string, styles MarkdownStyles) []TextLink {
+	paragraphs, links := MarkdownToParagraphs(markdown, styles)
+	st.SetParagraphs(paragraphs)
+	return links
+}
+
+// SetMarkdown replaces the text of the Story with the converted Markdown and returns the
+// links in it (see TextLink). The text is put into the first frame, like in SetText
+func (s Story) SetMarkdown(markdown string, styles MarkdownStyles) []TextLink {
+	if len(s.Frames) == 0 {
+		return nil
+	}
+	s.SetText("")
+	return s.Frames[0].StoryText.SetMarkdown(markdown, styles)
+}
+
+// Kinds of Markdown blocks
+const (
+	mdParagraph = iota
+	mdHeading
+	mdQuote
+	mdCode
+	mdItem
+)
+
+type markdownBlock struct {
+	kind    int
+	level   int // Heading level, or list level of items and of paragraphs inside lists
+	ordered bool
+	number  int    // Number of the first item of a numbered list, 0 for the other items
+	suffix  string // "." or ")" after the number
+	bullet  bool   // Whether the block is a list item with a bullet or number
+	lines   []string
+}
+
+var (
+	mdATXHeading     = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	mdSetextHeading  = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	mdThematicBreak  = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	mdListItem       = regexp.MustCompile(`^([ \t]*)([-*+]|(\d{1,9})([.)]))(?:[ \t]+(.*))?$`)
+	mdQuoteLine      = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	mdFence          = regexp.MustCompile("^ {0,3}(```+|~~~+)")
+	mdIndentedCode   = regexp.MustCompile(`^(?: {4}|\t)(.*)$`)
+	mdAutolink       = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^<>\s]*|[^<>\s@]+@[^<>\s@]+)>`)
+	mdInlineLinkDest = regexp.MustCompile(`^\(\s*<?([^\s()<>]*)>?(?:\s+(?:"[^"]*"|'[^']*'))?\s*\)`)
+)
+
+// indentWidth returns the width of the leading whitespace, counting tabs as 4 spaces
+func indentWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// parseMarkdownBlocks splits Markdown into blocks
+func parseMarkdownBlocks(markdown string) []*markdownBlock {
+	lines := strings.Split(strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(markdown), "\n")
+	var blocks []*markdownBlock
+	var current *markdownBlock // Block that further lines are added to, nil after a blank line
+	var listIndents []int      // Indents of the open list levels
+	listKinds := map[int]string{}
+
+	closeLists := func() {
+		listIndents = nil
+		listKinds = map[int]string{}
+	}
+	add := func(b *markdownBlock) {
+		blocks = append(blocks, b)
+		current = b
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			current = nil
+			continue
+		}
+
+		if m := mdFence.FindStringSubmatch(line); m != nil {
+			fence := m[1]
+			indent := indentWidth(line)
+			code := &markdownBlock{kind: mdCode}
+			for i++; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) && strings.Trim(strings.TrimSpace(lines[i]), fence[:1]) == "" {
+					break
+				}
+				l := lines[i]
+				for n := 0; n < indent && strings.HasPrefix(l, " "); n++ {
+					l = l[1:]
+				}
+				code.lines = append(code.lines, l)
+			}
+			closeLists()
+			blocks = append(blocks, code)
+			current = nil
+			continue
+		}
+
+		if m := mdSetextHeading.FindStringSubmatch(line); m != nil && current != nil && current.kind == mdParagraph && current.level == 0 {
+			current.kind = mdHeading
+			current.level = 1
+			if m[1][0] == '-' {
+				current.level = 2
+			}
+			current = nil
+			continue
+		}
+
+		if mdThematicBreak.MatchString(line) {
+			closeLists()
+			current = nil
+			continue
+		}
+
+		if m := mdATXHeading.FindStringSubmatch(line); m != nil {
+			closeLists()
+			add(&markdownBlock{kind: mdHeading, level: len(m[1]), lines: []string{m[2]}})
+			current = nil
+			continue
+		}
+
+		if m := mdQuoteLine.FindStringSubmatch(line); m != nil {
+			if current == nil || current.kind != mdQuote {
+				closeLists()
+				add(&markdownBlock{kind: mdQuote})
+			}
+			if strings.TrimSpace(m[1]) == "" {
+				// An empty quote line separates paragraphs within the quote
+				current.lines = append(current.lines, "")
+				continue
+			}
+			current.lines = append(current.lines, m[1])
+			continue
+		}
+
+		if m := mdListItem.FindStringSubmatch(line); m != nil && (current == nil || current.kind != mdParagraph || m[5] != "") {
+			indent := indentWidth(m[1])
+			for len(listIndents) > 0 && listIndents[len(listIndents)-1] > indent {
+				delete(listKinds, len(listIndents)-1)
+				listIndents = listIndents[:len(listIndents)-1]
+			}
+			if len(listIndents) == 0 || indent > listIndents[len(listIndents)-1] {
+				listIndents = append(listIndents, indent)
+			}
+			level := len(listIndents) - 1
+			item := &markdownBlock{kind: mdItem, level: level, bullet: true, ordered: m[3] != "", suffix: m[4], lines: []string{m[5]}}
+			kind := "bullet" + m[2]
+			if item.ordered {
+				kind = "ordered" + m[4]
+			}
+			if listKinds[level] != kind {
+				listKinds[level] = kind
+				if item.ordered {
+					item.number, _ = strconv.Atoi(m[3])
+				}
+			}
+			add(item)
+			continue
+		}
+
+		if current != nil && current.kind != mdCode {
+			// Lazy continuation line
+			current.lines = append(current.lines, strings.TrimLeft(line, " \t"))
+			continue
+		}
+
+		if len(listIndents) > 0 && indentWidth(line) > listIndents[0] {
+			// Another paragraph of the last list item
+			add(&markdownBlock{kind: mdItem, level: len(listIndents) - 1, lines: []string{strings.TrimLeft(line, " \t")}})
+			continue
+		}
+
+		if m := mdIndentedCode.FindStringSubmatch(line); m != nil {
+			closeLists()
+			code := &markdownBlock{kind: mdCode, lines: []string{m[1]}}
+			for i+1 < len(lines) {
+				next := lines[i+1]
+				if m := mdIndentedCode.FindStringSubmatch(next); m != nil {
+					code.lines = append(code.lines, m[1])
+				} else if strings.TrimSpace(next) == "" {
+					code.lines = append(code.lines, "")
+				} else {
+					break
+				}
+				i++
+			}
+			for len(code.lines) > 0 && strings.TrimSpace(code.lines[len(code.lines)-1]) == "" {
+				code.lines = code.lines[:len(code.lines)-1]
+			}
+			blocks = append(blocks, code)
+			current = nil
+			continue
+		}
+
+		closeLists()
+		add(&markdownBlock{kind: mdParagraph, lines: []string{strings.TrimLeft(line, " \t")}})
+	}
+	return blocks
+}
+
+type markdownConverter struct {
+	styles     MarkdownStyles
+	paragraphs []Paragraph
+	links      []TextLink
+}
+
+// block converts a Markdown block into one or more paragraphs
+func (c *markdownConverter) block(b *markdownBlock) {
+	style := Para{Bullet: "0", Numeration: "0"}
+	switch b.kind {
+	case mdParagraph:
+		style.PARENT = c.styles.Paragraph
+	case mdHeading:
+		style.PARENT = c.styles.Headings[b.level-1]
+	case mdQuote:
+		style.PARENT = c.styles.Quote
+	case mdCode:
+		style.PARENT = c.styles.CodeBlock
+		p := Paragraph{Style: style}
+		p.AddRun(strings.Join(b.lines, "\u2028"), c.styles.Code)
+		c.paragraphs = append(c.paragraphs, p)
+		return
+	case mdItem:
+		switch {
+		case !b.bullet:
+			style = listParagraphStyle(b.level, c.styles.ListIndent)
+		case b.ordered:
+			style = listItemStyle(b.level, true, "", b.number, b.suffix, c.styles.ListIndent)
+		default:
+			style = listItemStyle(b.level, false, bulletFor(c.styles.Bullets, b.level), 0, "", c.styles.ListIndent)
+		}
+		style.PARENT = c.styles.ListItem
+	}
+
+	// Quotes may contain several paragraphs separated by empty lines
+	var texts [][]string
+	var lines []string
+	for _, line := range b.lines {
+		if line == "" && b.kind == mdQuote {
+			if len(lines) > 0 {
+				texts = append(texts, lines)
+			}
+			lines = nil
+			continue
+		}
+		lines = append(lines, line)
+	}
+	texts = append(texts, lines)
+
+	for _, lines := range texts {
+		c.paragraphs = append(c.paragraphs, Paragraph{Style: style})
+		p := &c.paragraphs[len(c.paragraphs)-1]
+		c.inline(joinMarkdownLines(lines), ITEXT{}, p)
+	}
+}
+
+// joinMarkdownLines joins the lines of a block. Lines that end in two spaces or a
+// backslash end with a line break, the other lines with a space
+func joinMarkdownLines(lines []string) string {
+	var sb strings.Builder
+	for i, line := range lines {
+		trimmed := strings.TrimRight(line, " \t")
+		if i == len(lines)-1 {
+			sb.WriteString(trimmed)
+			break
+		}
+		switch {
+		case strings.HasSuffix(trimmed, "\\") && !strings.HasSuffix(trimmed, "\\\\"):
+			sb.WriteString(strings.TrimSuffix(trimmed, "\\") + "\u2028")
+		case strings.HasSuffix(line, "  "):
+			sb.WriteString(trimmed + "\u2028")
+		default:
+			sb.WriteString(trimmed + " ")
+		}
+	}
+	return sb.String()
+}
+
+// inline converts the inline Markdown of text into runs with format that are appended to p
+func (c *markdownConverter) inline(text string, format ITEXT, p *Paragraph) {
+	var sb strings.Builder
+	flush := func() {
+		if sb.Len() > 0 {
+			addMergedRun(p, html.UnescapeString(sb.String()), format)
+			sb.Reset()
+		}
+	}
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", rune(rest[1])):
+			sb.WriteByte(rest[1])
+			i += 2
+			continue
+
+		case rest[0] == '`':
+			n := len(rest) - len(strings.TrimLeft(rest, "`"))
+			if end := strings.Index(rest[n:], rest[:n]); end >= 0 {
+				flush()
+				code := rest[n : n+end]
+				if len(code) > 1 && code[0] == ' ' && code[len(code)-1] == ' ' {
+					code = code[1 : len(code)-1]
+				}
+				addMergedRun(p, code, mergeFormat(format, c.styles.Code))
+				i += 2*n + end
+				continue
+			}
+			sb.WriteString(rest[:n])
+			i += n
+			continue
+
+		case rest[0] == '*' || rest[0] == '_':
+			if n, inner, ok := emphasis(text, i); ok {
+				flush()
+				var f ITEXT
+				switch {
+				case n == 3 && c.styles.StrongEmphasis.SameFormat(ITEXT{}):
+					f = mergeFormat(mergeFormat(format, c.styles.Emphasis), c.styles.Strong)
+				case n == 3:
+					f = mergeFormat(format, c.styles.StrongEmphasis)
+				case n == 2:
+					f = mergeFormat(format, c.styles.Strong)
+				default:
+					f = mergeFormat(format, c.styles.Emphasis)
+				}
+				c.inline(inner, f, p)
+				i += 2*n + len(inner)
+				continue
+			}
+			n := len(rest) - len(strings.TrimLeft(rest, rest[:1]))
+			sb.WriteString(rest[:n])
+			i += n
+			continue
+
+		case rest[0] == '!' && strings.HasPrefix(rest, "!["):
+			if label, _, length, ok := markdownLink(rest[1:]); ok {
+				sb.WriteString(label) // Images cannot be placed inside a story, keep the alternative text
+				i += 1 + length
+				continue
+			}
+
+		case rest[0] == '[':
+			if label, dest, length, ok := markdownLink(rest); ok {
+				flush()
+				linkFormat := mergeFormat(format, c.styles.Link)
+				before := len(p.Runs)
+				c.inline(label, linkFormat, p)
+				var linkText strings.Builder
+				for _, run := range p.Runs[before:] {
+					linkText.WriteString(run.CH)
+				}
+				c.addLink(linkText.String(), dest, p, linkFormat)
+				i += length
+				continue
+			}
+
+		case rest[0] == '<':
+			if m := mdAutolink.FindStringSubmatch(rest); m != nil {
+				flush()
+				linkFormat := mergeFormat(format, c.styles.Link)
+				dest := m[1]
+				if !strings.Contains(dest, ":") {
+					dest = "mailto:" + dest
+				}
+				addMergedRun(p, m[1], linkFormat)
+				c.links = append(c.links, TextLink{Text: m[1], URL: dest, Paragraph: len(c.paragraphs) - 1})
+				i += len(m[0])
+				continue
+			}
+		}
+		sb.WriteByte(rest[0])
+		i++
+	}
+	flush()
+}
+
+// addLink records a link and appends its URL if the styles ask for it
+func (c *markdownConverter) addLink(text string, dest string, p *Paragraph, format ITEXT) {
+	c.links = append(c.links, TextLink{Text: text, URL: dest, Paragraph: len(c.paragraphs) - 1})
+	if c.styles.LinkURLs && dest != "" && dest != text {
+		addMergedRun(p, " ("+dest+")", format)
+	}
+}
+
+// markdownLink parses a link "[label](destination)" at the start of s and returns the
+// label, the destination and the length of the link
+func markdownLink(s string) (string, string, int, bool) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				m := mdInlineLinkDest.FindStringSubmatch(s[i+1:])
+				if m == nil {
+					return "", "", 0, false
+				}
+				return s[1:i], html.UnescapeString(m[1]), i + 1 + len(m[0]), true
+			}
+		}
+	}
+	return "", "", 0, false
+}
+
+// emphasis checks whether emphasis with '*' or '_' starts at text[i]. It returns the
+// number of delimiter characters (1 for emphasis, 2 for strong, 3 for both) and the
+// emphasized text
+func emphasis(text string, i int) (int, string, bool) {
+	delim := text[i]
+	count := delimiterRun(text, i)
+	// Opening delimiters must be followed by text, '_' must not be inside a word
+	if i+count >= len(text) || isSpace(text[i+count]) {
+		return 0, "", false
+	}
+	if delim == '_' && i > 0 && isWordChar(text[i-1]) {
+		return 0, "", false
+	}
+	for n := min(count, 3); n >= 1; n-- {
+		if end, ok := closeEmphasis(text, i+n, n); ok {
+			return n, text[i+n : end], true
+		}
+	}
+	return 0, "", false
+}
+
+// closeEmphasis returns the position of the n delimiters that close emphasis whose text
+// starts at start. Nested emphasis and code spans are skipped
+func closeEmphasis(text string, start int, n int) (int, bool) {
+	delim := text[start-1]
+	for j := start; j < len(text); {
+		switch text[j] {
+		case '\\':
+			j += 2
+			continue
+		case '`':
+			ticks := delimiterRun(text, j)
+			if end := strings.Index(text[j+ticks:], text[j:j+ticks]); end >= 0 {
+				j += 2*ticks + end
+			} else {
+				j += ticks
+			}
+			continue
+		case delim:
+			r := delimiterRun(text, j)
+			leftFlanking := j+r < len(text) && !isSpace(text[j+r])
+			rightFlanking := !isSpace(text[j-1])
+			canOpen := leftFlanking && (!rightFlanking || !isWordChar(text[j-1]))
+			canClose := rightFlanking && (!leftFlanking || isWordChar(text[j-1]))
+			if delim == '_' && j+r < len(text) && isWordChar(text[j+r]) {
+				canClose = false
+			}
+			if canClose && r >= n && j > start {
+				return j, true
+			}
+			if canOpen {
+				if m, inner, ok := emphasis(text, j); ok {
+					j += 2*m + len(inner)
+					continue
+				}
+			}
+			j += r
+			continue
+		}
+		j++
+	}
+	return 0, false
+}
+
+// delimiterRun returns the number of equal characters starting at text[i]
+func delimiterRun(text string, i int) int {
+	n := 0
+	for i+n < len(text) && text[i+n] == text[i] {
+		n++
+	}
+	return n
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n'
+}
+
+func isWordChar(b byte) bool {
+	return b >= 0x80 || unicode.IsLetter(rune(b)) || unicode.IsDigit(rune(b))
+}
+
+// bulletFor returns the bullet string of list level, repeating bullets for deeper levels
+func bulletFor(bullets []string, level int) string {
+	if len(bullets) == 0 {
+		return ""
+	}
+	return bullets[level%len(bullets)]
+}
+
+// listItemStyle returns the paragraph attributes of a list item at level (0 for the top
+// level) that is indented by indent per level. Bullet items get bulletStr, ordered items
+// are numbered with suffix after the number; start is the number of the first item of
+// a list and 0 for the other items
+func listItemStyle(level int, ordered bool, bulletStr string, start int, suffix string, indent float64) Para {
+	item := ListItem{Level: level, Numbered: ordered, BulletStr: bulletStr, Start: start, Suffix: suffix}
+	return item.style(indent, nil)
+}
+
+// listParagraphStyle returns the paragraph attributes of a further paragraph of a list
+// item at level, which is indented like the item but has no bullet or number
+func listParagraphStyle(level int, indent float64) Para {
+	return Para{Bullet: "0", Numeration: "0", INDENT: formatFloat(indent * float64(level+1)), FIRST: "0"}
+}
+
+// addMergedRun appends text with format to p, extending the last run if it has the same format
+func addMergedRun(p *Paragraph, text string, format ITEXT) {
+	if text == "" {
+		return
+	}
+	if n := len(p.Runs); n > 0 && p.Runs[n-1].SameFormat(format) {
+		p.Runs[n-1].CH += text
+		return
+	}
+	p.AddRun(text, format)
+}
+
+// mergeFormat returns the character attributes of base overridden by those of overlay.
+// FEATURES are combined
+func mergeFormat(base ITEXT, overlay ITEXT) ITEXT {
+	attrs := itextAttrs(base)
+	for _, a := range itextAttrs(overlay) {
+		if a.Name.Local == "FEATURES" {
+			continue
+		}
+		replaced := false
+		for i := range attrs {
+			if attrs[i].Name.Local == a.Name.Local {
+				attrs[i].Value = a.Value
+				replaced = true
+			}
+		}
+		if !replaced {
+			attrs = append(attrs, a)
+		}
+	}
+	merged := itextFromAttrs(attrs)
+	for _, feature := range strings.Fields(overlay.FEATURES) {
+		if feature != "inherit" {
+			merged.SetFeature(feature, true)
+		}
+	}
+	return merged
+}
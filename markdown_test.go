@@ -0,0 +1,128 @@
+package scribus
+
+import (
+	"testing"
+)
+
+func TestMarkdownToParagraphs(t *testing.T) {
+	markdown := "# Title\n\n" +
+		"Some *emphasis*, **strong** and ***both***, `code` and a [link](https://example.com).  \n" +
+		"Next line with snake_case_name\n\n" +
+		"- One\n" +
+		"- Two\n" +
+		"  - Nested\n\n" +
+		"3. Three\n" +
+		"4. Four\n"
+	paragraphs, links := MarkdownToParagraphs(markdown, DefaultMarkdownStyles())
+
+	texts := []string{"Title", "Some emphasis, strong and both, code and a link.\u2028Next line with snake_case_name", "One", "Two", "Nested", "Three", "Four"}
+	if len(paragraphs) != len(texts) {
+		t.Fatalf("len(paragraphs) was incorrect, got: %v, want: %v.", len(paragraphs), len(texts))
+	}
+	for i, text := range texts {
+		if paragraphs[i].Text() != text {
+			t.Errorf("paragraphs[%v].Text() was incorrect, got: %q, want: %q.", i, paragraphs[i].Text(), text)
+		}
+	}
+	if paragraphs[0].Style.PARENT != "Heading 1" {
+		t.Errorf("heading style was incorrect, got: %v", paragraphs[0].Style.PARENT)
+	}
+
+	formats := map[string]string{}
+	for _, run := range paragraphs[1].Runs {
+		formats[run.CH] = run.CPARENT
+	}
+	for text, style := range map[string]string{"emphasis": "Emphasis", "strong": "Strong", "both": "Strong Emphasis", "code": "Code", "link": "Link"} {
+		if formats[text] != style {
+			t.Errorf("character style of %q was incorrect, got: %q, want: %q.", text, formats[text], style)
+		}
+	}
+	if len(links) != 1 || links[0].URL != "https://example.com" || links[0].Text != "link" || links[0].Paragraph != 1 {
+		t.Errorf("links were incorrect, got: %+v", links)
+	}
+
+	if s := paragraphs[2].Style; s.Bullet != "1" || s.BulletStr != "•" || s.INDENT != "18" || s.FIRST != "-18" {
+		t.Errorf("bullet item style was incorrect, got: %+v", s)
+	}
+	if s := paragraphs[4].Style; s.BulletStr != "◦" || s.INDENT != "36" {
+		t.Errorf("nested item style was incorrect, got: %+v", s)
+	}
+	if s := paragraphs[5].Style; s.Numeration != "1" || s.NumerationStart != "3" || s.NumerationSuffix != "." {
+		t.Errorf("numbered item style was incorrect, got: %+v", s)
+	}
+	if s := paragraphs[6].Style; s.Numeration != "1" || s.NumerationStart != "" {
+		t.Errorf("second numbered item style was incorrect, got: %+v", s)
+	}
+}
+
+func TestSetMarkdown(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	styles := DefaultMarkdownStyles()
+	document.DOCUMENT.EnsureMarkdownStyles(styles)
+	if document.DOCUMENT.GetParagraphStyle("Heading 6") == nil || document.DOCUMENT.GetCharStyle("Link") == nil {
+		t.Errorf("styles were not created")
+	}
+	// The default character style uses "FreeSans Bold"
+	if s := document.DOCUMENT.GetCharStyle("Emphasis"); s == nil || s.FONT != "FreeSans Bold Italic" {
+		t.Errorf("Emphasis was not italic, got: %+v", s)
+	}
+	if s := document.DOCUMENT.GetCharStyle("Link"); s == nil || s.FONT != "FreeSans Bold" {
+		t.Errorf("Link was not a copy of the default character style, got: %+v", s)
+	}
+	variants := []struct {
+		font         string
+		bold, italic bool
+		want         string
+	}{
+		{"FreeSans Bold", false, true, "FreeSans Bold Italic"},
+		{"FreeSans Oblique", true, false, "FreeSans Bold Oblique"},
+		{"DejaVu Sans Book", false, true, "DejaVu Sans Italic"},
+		{"Liberation Serif Regular", true, false, "Liberation Serif Bold"},
+		{"Arial", false, false, "Arial Regular"},
+	}
+	for _, v := range variants {
+		if got := fontVariant(v.font, v.bold, v.italic); got != v.want {
+			t.Errorf("fontVariant(%q) was incorrect, got: %v, want: %v.", v.font, got, v.want)
+		}
+	}
+
+	document.DOCUMENT.PAGEOBJECT[3].StoryText.SetMarkdown("## Heading\n\nLine one\\\nLine two\n\n- Last", styles)
+	clone, err := document.Clone()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	st := clone.DOCUMENT.PAGEOBJECT[3].StoryText
+	if st.PlainText() != "Heading\nLine one\u2028Line two\nLast" {
+		t.Errorf("PlainText() was incorrect, got: %q", st.PlainText())
+	}
+	if len(st.Elements) != 1 || st.Elements[0].XMLName.Local != "breakline" {
+		t.Errorf("st.Elements was incorrect, got: %+v", st.Elements)
+	}
+	if st.Para[0].PARENT != "Heading 2" || st.Trail.Bullet != "1" {
+		t.Errorf("paragraph styles were incorrect, got: %+v, %+v", st.Para, st.Trail)
+	}
+
+	// The links are returned, the frame does not become a link
+	doc := &document.DOCUMENT
+	links := doc.GetStory(&doc.PAGEOBJECT[3]).SetMarkdown("See [the site](https://example.com) or <https://example.org>", styles)
+	if len(links) != 2 || links[1].URL != "https://example.org" || doc.PAGEOBJECT[3].ANNOTATION != "" {
+		t.Errorf("SetMarkdown() links were incorrect, got: %+v", links)
+	}
+
+	// Link annotations can be placed over the links
+	doc.PAGEOBJECT[2].SetLink(links[0].URL)
+	clone, err = document.Clone()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if frame := clone.DOCUMENT.PAGEOBJECT[2]; frame.Link() != "https://example.com" || frame.ANTYPE != "11" {
+		t.Errorf("link annotation was not written, got: %+v", frame)
+	}
+	doc.PAGEOBJECT[2].SetLink("")
+	if doc.PAGEOBJECT[2].Link() != "" || doc.PAGEOBJECT[2].ANNOTATION != "" {
+		t.Errorf("SetLink() did not remove the link annotation")
+	}
+}
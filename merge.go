@@ -0,0 +1,440 @@
+package scribus
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MergeRecord is a record of a data source, e.g., a row of a CSV file, with the values by
+// column name. The values of JSON records may be nested objects and arrays
+type MergeRecord map[string]interface{}
+
+// ReadCSVRecords reads the records of CSV data whose first row holds the column names
+func ReadCSVRecords(r io.Reader) ([]MergeRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	header := rows[0]
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+	var records []MergeRecord
+	for _, row := range rows[1:] {
+		record := MergeRecord{}
+		for i, name := range header {
+			if name == "" {
+				continue
+			}
+			if i < len(row) {
+				record[name] = row[i]
+			} else {
+				record[name] = ""
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// ReadJSONRecords reads the records of JSON data, which is an array of objects
+func ReadJSONRecords(r io.Reader) ([]MergeRecord, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var records []MergeRecord
+	if err := decoder.Decode(&records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// ColorBinding sets a colour of the items with a name (ANNAME) to the value of a column,
+// which is the name of a COLOR of the document or an RGB value such as "#ff8000"
+type ColorBinding struct {
+	Item      string // ANNAME of the items
+	Attribute string // "PCOLOR" (fill), "PCOLOR2" (line) or "FCOLOR" (text)
+	Column    string
+}
+
+// MergeOptions configures the data merge
+type MergeOptions struct {
+	Template   TemplateOptions // Filling of the placeholders (see FillTemplate)
+	Colors     []ColorBinding
+	NameColumn string // Column with the names of the merged documents, otherwise they are numbered from 1
+
+	// N-up for MergePages: the cells of a grid of Columns x Rows on each sheet get
+	// consecutive records. The sheets are SheetWidth x SheetHeight points, or the size
+	// of the grid if they are 0, and the grid is centred on them
+	Columns     int
+	Rows        int
+	SheetWidth  float64
+	SheetHeight float64
+	Gap         float64 // Space between the cells
+}
+
+// MergedDocument is the Document of one record
+type MergedDocument struct {
+	Name     string // Value of the NameColumn, or the number of the record
+	Record   MergeRecord
+	Report   TemplateReport
+	Document Document
+}
+
+// MergeDocuments derives one Document per record from the template Document: the placeholders
+// such as {{name}} are filled with the values of the record (see FillTemplate) and the colour
+// bindings are applied. The reports include the bound columns. Returns the documents in the
+// order of records, error
+func (scribusDocument Document) MergeDocuments(records []MergeRecord, options MergeOptions) ([]MergedDocument, error) {
+	var docs []MergedDocument
+	for i, record := range records {
+		doc, report, err := scribusDocument.mergeRecord(record, options)
+		if err != nil {
+			return nil, fmt.Errorf("record %v: %v", i+1, err)
+		}
+		name := strconv.Itoa(i + 1)
+		if options.NameColumn != "" {
+			if value := templateString(record[options.NameColumn], options.Template); value != "" {
+				name = value
+			}
+		}
+		docs = append(docs, MergedDocument{Name: name, Record: record, Report: report, Document: doc})
+	}
+	return docs, nil
+}
+
+// WriteMergedDocuments writes one Scribus file per record (see MergeDocuments) and returns the
+// paths of the written files, error. The path of each file is pattern with "{record}" replaced
+// by the name of the merged document; if pattern does not contain "{record}", the name is
+// appended to the file name, e.g., "badge.sla" becomes "badge-1.sla"
+func (scribusDocument Document) WriteMergedDocuments(pattern string, records []MergeRecord, options MergeOptions) ([]string, error) {
+	docs, err := scribusDocument.MergeDocuments(records, options)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, d := range docs {
+		path := namedPath(pattern, "{record}", fileNameSafe(d.Name))
+		if err := d.Document.WriteScribusFile(path); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// MergePages returns one Document in which the pages of the template Document are repeated
+// per record, filled with the values of the record (see MergeDocuments). With options.Columns
+// and options.Rows, the template, which then must have one page, is placed N-up on sheets
+// instead. The items of the master page are copied onto the pages (or cells) with the items
+// of the template pages, so that they are filled per record too; the master page of the merged
+// document is empty. Items on the pasteboard are kept once, as they are. Returns the document
+// and the reports by record, error
+func (scribusDocument Document) MergePages(records []MergeRecord, options MergeOptions) (Document, []TemplateReport, error) {
+	template := scribusDocument.DOCUMENT
+	if len(template.PAGE) == 0 {
+		return Document{}, nil, fmt.Errorf("the template has no pages")
+	}
+	nUp := options.Columns > 1 || options.Rows > 1
+	if nUp && len(template.PAGE) > 1 {
+		return Document{}, nil, fmt.Errorf("N-up needs a template with one page, it has %v", len(template.PAGE))
+	}
+	columns, rows := max(options.Columns, 1), max(options.Rows, 1)
+
+	merged, err := scribusDocument.Clone()
+	if err != nil {
+		return Document{}, nil, err
+	}
+	doc := &merged.DOCUMENT
+	doc.PAGE = nil
+	doc.ANZPAGES = "0"
+	doc.PAGEOBJECT = nil
+	doc.MASTEROBJECT = nil
+	for _, po := range template.PAGEOBJECT {
+		if template.pageIndex(po.OwnPage) < 0 {
+			doc.PAGEOBJECT = append(doc.PAGEOBJECT, po)
+		}
+	}
+	nextID := doc.maxItemID() + 1
+
+	first := template.PAGE[0]
+	width, height := parseFloat(first.PAGEWIDTH), parseFloat(first.PAGEHEIGHT)
+	sheetWidth := float64(columns)*width + float64(columns-1)*options.Gap
+	sheetHeight := float64(rows)*height + float64(rows-1)*options.Gap
+	marginX, marginY := 0.0, 0.0
+	if nUp {
+		if options.SheetWidth > 0 {
+			marginX = (options.SheetWidth - sheetWidth) / 2
+			sheetWidth = options.SheetWidth
+		}
+		if options.SheetHeight > 0 {
+			marginY = (options.SheetHeight - sheetHeight) / 2
+			sheetHeight = options.SheetHeight
+		}
+		doc.PAGEWIDTH, doc.PAGEHEIGHT = formatFloat(sheetWidth), formatFloat(sheetHeight)
+		doc.MASTERPAGE.PAGEWIDTH, doc.MASTERPAGE.PAGEHEIGHT = doc.PAGEWIDTH, doc.PAGEHEIGHT
+	}
+	var reports []TemplateReport
+	perSheet := columns * rows
+	var sheet PAGE
+	for i, record := range records {
+		filled, report, err := scribusDocument.mergeRecord(record, options)
+		if err != nil {
+			return Document{}, nil, fmt.Errorf("record %v: %v", i+1, err)
+		}
+		reports = append(reports, report)
+
+		// The items of all pages of the record are renumbered together, so that the links
+		// between frames on different pages are kept
+		var onPages []PAGEOBJECT
+		for _, po := range filled.DOCUMENT.PAGEOBJECT {
+			if filled.DOCUMENT.pageIndex(po.OwnPage) >= 0 {
+				onPages = append(onPages, po)
+			}
+		}
+		renumberItems(onPages, &nextID)
+
+		for _, page := range filled.DOCUMENT.PAGE {
+			var originX, originY float64
+			if nUp {
+				if i%perSheet == 0 {
+					sheet = page
+					sheet.PAGEWIDTH, sheet.PAGEHEIGHT = formatFloat(sheetWidth), formatFloat(sheetHeight)
+					sheet.Size = "Custom"
+					sheet.Orientation = boolAttr(sheetWidth > sheetHeight)
+					sheet = doc.appendPage(sheet)
+				}
+				cell := i % perSheet
+				originX = parseFloat(sheet.PAGEXPOS) + marginX + float64(cell%columns)*(width+options.Gap)
+				originY = parseFloat(sheet.PAGEYPOS) + marginY + float64(cell/columns)*(height+options.Gap)
+			} else {
+				sheet = doc.appendPage(page)
+				originX, originY = parseFloat(sheet.PAGEXPOS), parseFloat(sheet.PAGEYPOS)
+			}
+
+			var items []PAGEOBJECT
+			master := filled.DOCUMENT.MASTERPAGE
+			if page.MNAM != "" && page.MNAM == master.NAM {
+				for _, po := range filled.DOCUMENT.MASTEROBJECT {
+					shiftPageObject(&po, originX-parseFloat(master.PAGEXPOS), originY-parseFloat(master.PAGEYPOS))
+					items = append(items, po)
+				}
+				renumberItems(items, &nextID)
+			}
+			for _, po := range onPages {
+				if po.OwnPage == page.NUM {
+					shiftPageObject(&po, originX-parseFloat(page.PAGEXPOS), originY-parseFloat(page.PAGEYPOS))
+					items = append(items, po)
+				}
+			}
+			for i := range items {
+				items[i].OwnPage = sheet.NUM
+			}
+			doc.PAGEOBJECT = append(doc.PAGEOBJECT, items...)
+		}
+	}
+	return merged, reports, nil
+}
+
+// mergeRecord returns a copy of the template Document filled with the values of record
+func (scribusDocument Document) mergeRecord(record MergeRecord, options MergeOptions) (Document, TemplateReport, error) {
+	filled, err := scribusDocument.Clone()
+	if err != nil {
+		return Document{}, TemplateReport{}, err
+	}
+	doc := &filled.DOCUMENT
+	var unresolved []string
+	bound := map[string]bool{}
+	for _, binding := range options.Colors {
+		bound[binding.Column] = true
+		value, ok := record[binding.Column]
+		if !ok {
+			unresolved = append(unresolved, binding.Column)
+			continue
+		}
+		if err := doc.bindColor(binding, templateString(value, options.Template)); err != nil {
+			return Document{}, TemplateReport{}, err
+		}
+	}
+
+	report, err := doc.FillTemplate(record, options.Template)
+	if err != nil {
+		return Document{}, TemplateReport{}, err
+	}
+	var unused []string
+	for _, column := range report.Unused {
+		if !bound[column] {
+			unused = append(unused, column)
+		}
+	}
+	report.Unused = unused
+	if len(unresolved) > 0 {
+		report.Unresolved = append(report.Unresolved, unresolved...)
+		sort.Strings(report.Unresolved)
+	}
+	return filled, report, nil
+}
+
+// bindColor sets the colour of the items of binding to value
+func (doc *DOCUMENT) bindColor(binding ColorBinding, value string) error {
+	color, err := doc.colorFor(value)
+	if err != nil {
+		return fmt.Errorf("column %v: %v", binding.Column, err)
+	}
+	found := false
+	var attrErr error
+	doc.walkAllPageObjects(func(po *PAGEOBJECT) {
+		if po.ANNAME != binding.Item {
+			return
+		}
+		found = true
+		switch binding.Attribute {
+		case "PCOLOR":
+			po.PCOLOR = color
+		case "PCOLOR2":
+			po.PCOLOR2 = color
+		case "FCOLOR":
+			for i := range po.StoryText.ITEXT {
+				po.StoryText.ITEXT[i].FCOLOR = color
+			}
+			for i := range po.StoryText.StoryTextSpan {
+				po.StoryText.StoryTextSpan[i].ITEXT.FCOLOR = color
+			}
+		default:
+			attrErr = fmt.Errorf("cannot bind the colour attribute %v", binding.Attribute)
+		}
+	})
+	if attrErr != nil {
+		return attrErr
+	}
+	if !found {
+		return fmt.Errorf("item %v not found", binding.Item)
+	}
+	return nil
+}
+
+// colorFor returns the name of the COLOR for value, which is the name of a COLOR or an RGB
+// value such as "#ff8000", for which a COLOR is added if needed
+func (doc *DOCUMENT) colorFor(value string) (string, error) {
+	for _, c := range doc.COLOR {
+		if c.NAME == value {
+			return value, nil
+		}
+	}
+	if !strings.HasPrefix(value, "#") {
+		return "", fmt.Errorf("colour %v not found", value)
+	}
+	rgb, err := parseHexColor(value, 3)
+	if err != nil {
+		return "", err
+	}
+	name := formatHexColor(rgb)
+	for _, c := range doc.COLOR {
+		if c.NAME == name {
+			return name, nil
+		}
+	}
+	color := COLOR{NAME: name}
+	color.SetRGB([3]float64{rgb[0], rgb[1], rgb[2]})
+	doc.COLOR = append(doc.COLOR, color)
+	return name, nil
+}
+
+// appendPage adds a copy of page after the last page, below it, and returns the copy
+func (doc *DOCUMENT) appendPage(page PAGE) PAGE {
+	page.NUM = strconv.Itoa(len(doc.PAGE))
+	if len(doc.PAGE) > 0 {
+		last := doc.PAGE[len(doc.PAGE)-1]
+		page.PAGEXPOS = last.PAGEXPOS
+		page.PAGEYPOS = formatFloat(parseFloat(last.PAGEYPOS) + parseFloat(last.PAGEHEIGHT) + parseFloat(doc.GapVertical))
+	}
+	doc.PAGE = append(doc.PAGE, page)
+	doc.ANZPAGES = strconv.Itoa(len(doc.PAGE))
+	return page
+}
+
+// pageIndex returns the index of the PAGE with the number num, -1 if there is none
+func (doc DOCUMENT) pageIndex(num string) int {
+	for i, page := range doc.PAGE {
+		if page.NUM == num {
+			return i
+		}
+	}
+	return -1
+}
+
+// maxItemID returns the highest numeric ItemID of the items of the document
+func (doc DOCUMENT) maxItemID() int {
+	highest := 0
+	doc.walkAllPageObjects(func(po *PAGEOBJECT) {
+		if id, err := strconv.Atoi(po.ItemID); err == nil && id > highest {
+			highest = id
+		}
+	})
+	return highest
+}
+
+// renumberItems gives the items (including the items inside groups) new ItemIDs from
+// *next on, keeping the links between the frames among them
+func renumberItems(items []PAGEOBJECT, next *int) {
+	ids := map[string]string{}
+	walkPageObjects(items, func(po *PAGEOBJECT) {
+		if po.ItemID != "" {
+			ids[po.ItemID] = strconv.Itoa(*next)
+			po.ItemID = ids[po.ItemID]
+			*next++
+		}
+	})
+	walkPageObjects(items, func(po *PAGEOBJECT) {
+		// Links to frames that are not among the items are dropped
+		if linked(po.NEXTITEM) {
+			if po.NEXTITEM = ids[po.NEXTITEM]; po.NEXTITEM == "" {
+				po.NEXTITEM = "-1"
+			}
+		}
+		if linked(po.BACKITEM) {
+			if po.BACKITEM = ids[po.BACKITEM]; po.BACKITEM == "" {
+				po.BACKITEM = "-1"
+			}
+		}
+	})
+}
+
+// shiftPageObject moves an item (including the items inside it) by dx and dy. The items
+// inside groups keep their positions within the group (gXpos, gYpos)
+func shiftPageObject(po *PAGEOBJECT, dx float64, dy float64) {
+	if dx == 0 && dy == 0 {
+		return
+	}
+	if po.GXpos != "" {
+		po.GXpos = formatFloat(parseFloat(po.GXpos) + dx)
+	}
+	if po.GYpos != "" {
+		po.GYpos = formatFloat(parseFloat(po.GYpos) + dy)
+	}
+	po.XPOS = formatFloat(parseFloat(po.XPOS) + dx)
+	po.YPOS = formatFloat(parseFloat(po.YPOS) + dy)
+	walkPageObjects(po.PAGEOBJECT, func(child *PAGEOBJECT) {
+		child.XPOS = formatFloat(parseFloat(child.XPOS) + dx)
+		child.YPOS = formatFloat(parseFloat(child.YPOS) + dy)
+	})
+}
+
+// fileNameSafe replaces the characters of name that are not safe in file names
+func fileNameSafe(name string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+}
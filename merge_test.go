@@ -0,0 +1,137 @@
+package scribus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	template, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	badge := &template.DOCUMENT.PAGEOBJECT[0]
+	badge.ANNAME = "badge"
+	badge.StoryText.SetPlainText("Hello {{name | upper}}")
+	template.DOCUMENT.PAGEOBJECT[2].ANNAME = "{{photo}}"
+
+	records, err := ReadCSVRecords(strings.NewReader("name,color,photo,extra\nAda,#FF0000,ada.png,x\nBob,Black,bob.png,y\nCy,White,cy.png,z\n"))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(records) != 3 || records[1]["name"] != "Bob" {
+		t.Fatalf("ReadCSVRecords() was incorrect, got: %v", records)
+	}
+	jsonRecords, err := ReadJSONRecords(strings.NewReader(`[{"name": "Ada", "address": {"city": "London"}, "age": 36}]`))
+	if err != nil || len(jsonRecords) != 1 || templateString(jsonRecords[0]["age"], TemplateOptions{}) != "36" {
+		t.Fatalf("ReadJSONRecords() was incorrect, got: %v, %v", jsonRecords, err)
+	}
+
+	options := MergeOptions{
+		Colors:     []ColorBinding{{Item: "badge", Attribute: "PCOLOR", Column: "color"}},
+		NameColumn: "name",
+	}
+	docs, err := template.MergeDocuments(records, options)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	first := docs[0].Document.DOCUMENT
+	if len(docs) != 3 || docs[0].Name != "Ada" || first.PAGEOBJECT[0].StoryText.PlainText() != "Hello ADA" || first.PAGEOBJECT[2].PFILE != "ada.png" {
+		t.Errorf("MergeDocuments() was incorrect, got: %v, %q", docs[0].Name, first.PAGEOBJECT[0].StoryText.PlainText())
+	}
+	if first.PAGEOBJECT[0].PCOLOR != "#ff0000" || first.COLOR[len(first.COLOR)-1].NAME != "#ff0000" || docs[1].Document.DOCUMENT.PAGEOBJECT[0].PCOLOR != "Black" {
+		t.Errorf("colour binding was incorrect, got: %v", first.PAGEOBJECT[0].PCOLOR)
+	}
+	if strings.Join(docs[0].Report.Unused, ",") != "extra" {
+		t.Errorf("Report.Unused was incorrect, got: %v", docs[0].Report.Unused)
+	}
+
+	merged, reports, err := template.MergePages(records, options)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := merged.DOCUMENT
+	if len(reports) != 3 || len(doc.PAGE) != 3 || doc.ANZPAGES != "3" || len(doc.PAGEOBJECT) != 12 {
+		t.Fatalf("MergePages() was incorrect, got: %v pages, %v items", len(doc.PAGE), len(doc.PAGEOBJECT))
+	}
+	// Pages are 792 high with a gap of 40
+	if doc.PAGE[2].PAGEYPOS != "1684" || doc.PAGEOBJECT[8].YPOS != "1724" || doc.PAGEOBJECT[8].OwnPage != "2" || doc.PAGEOBJECT[8].StoryText.PlainText() != "Hello CY" {
+		t.Errorf("MergePages() was incorrect, got: %v, %+v", doc.PAGE[2].PAGEYPOS, doc.PAGEOBJECT[8])
+	}
+	ids := map[string]bool{}
+	for _, po := range doc.PAGEOBJECT {
+		if ids[po.ItemID] {
+			t.Errorf("ItemID %v is not unique", po.ItemID)
+		}
+		ids[po.ItemID] = true
+	}
+
+	options.Columns, options.Rows, options.SheetWidth, options.SheetHeight = 2, 1, 1300, 900
+	merged, _, err = template.MergePages(records, options)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc = merged.DOCUMENT
+	if len(doc.PAGE) != 2 || doc.PAGE[0].PAGEWIDTH != "1300" || doc.PAGE[1].PAGEYPOS != "960" {
+		t.Fatalf("N-up MergePages() was incorrect, got: %+v", doc.PAGE)
+	}
+	// The grid of 2 x 612 is centred: (1300 - 1224) / 2 = 38 and (900 - 792) / 2 = 54, the item is at 40, 40 on the page
+	if doc.PAGEOBJECT[4].XPOS != "790" || doc.PAGEOBJECT[4].YPOS != "114" || doc.PAGEOBJECT[8].OwnPage != "1" || doc.PAGEOBJECT[8].XPOS != "178" {
+		t.Errorf("N-up MergePages() was incorrect, got: %+v, %+v", doc.PAGEOBJECT[4], doc.PAGEOBJECT[8])
+	}
+}
+
+func TestMergeChainedPages(t *testing.T) {
+	template, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := &template.DOCUMENT
+	// The story flows from the first frame on page 0 into the last frame, moved onto page 1
+	second := doc.appendPage(doc.PAGE[0])
+	first, last := &doc.PAGEOBJECT[0], &doc.PAGEOBJECT[3]
+	last.OwnPage = second.NUM
+	shiftPageObject(last, 0, parseFloat(second.PAGEYPOS)-parseFloat(doc.PAGE[0].PAGEYPOS))
+	first.NEXTITEM, last.BACKITEM = last.ItemID, first.ItemID
+
+	records := []MergeRecord{{"name": "Ada"}, {"name": "Bob"}}
+	merged, _, err := template.MergePages(records, MergeOptions{})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	result := merged.DOCUMENT
+	items := result.pageObjectsByID()
+	links := 0
+	for _, po := range result.PAGEOBJECT {
+		if !linked(po.NEXTITEM) {
+			continue
+		}
+		links++
+		next := items[po.NEXTITEM]
+		if next == nil || next.BACKITEM != po.ItemID || next.OwnPage == po.OwnPage {
+			t.Errorf("link of frame %v on page %v was incorrect, got: %+v", po.ItemID, po.OwnPage, next)
+		}
+	}
+	if len(result.PAGE) != 4 || links != 2 {
+		t.Errorf("MergePages() kept %v links on %v pages, want 2 on 4", links, len(result.PAGE))
+	}
+}
+
+func TestShiftPageObject(t *testing.T) {
+	// A group at 10, 20 holds a group 5 to the right of it, which holds an item at its origin
+	item := PAGEOBJECT{XPOS: "15", YPOS: "20", GXpos: "0", GYpos: "0"}
+	inner := PAGEOBJECT{PTYPE: "12", XPOS: "15", YPOS: "20", GXpos: "5", GYpos: "0", PAGEOBJECT: []PAGEOBJECT{item}}
+	outer := PAGEOBJECT{PTYPE: "12", XPOS: "10", YPOS: "20", GXpos: "10", GYpos: "20", PAGEOBJECT: []PAGEOBJECT{inner}}
+	shiftPageObject(&outer, 1, 2)
+	if outer.XPOS != "11" || outer.YPOS != "22" || outer.GXpos != "11" || outer.GYpos != "22" {
+		t.Errorf("shiftPageObject() was incorrect, got: %v, %v", outer.XPOS, outer.YPOS)
+	}
+	// The items inside move on the page, but keep their positions within their groups
+	inner, item = outer.PAGEOBJECT[0], outer.PAGEOBJECT[0].PAGEOBJECT[0]
+	if inner.XPOS != "16" || inner.YPOS != "22" || inner.GXpos != "5" || inner.GYpos != "0" {
+		t.Errorf("shiftPageObject() of the group inside was incorrect, got: %v, %v within the group", inner.GXpos, inner.GYpos)
+	}
+	if item.XPOS != "16" || item.YPOS != "22" || item.GXpos != "0" || item.GYpos != "0" {
+		t.Errorf("shiftPageObject() of the item inside was incorrect, got: %v, %v within the group", item.GXpos, item.GYpos)
+	}
+}
@@ -0,0 +1,425 @@
+package scribus
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Point is a point in points
+type Point struct {
+	X float64
+	Y float64
+}
+
+// PathSegment is a command of a Path with its points: 'M' (move to) and 'L' (line to)
+// have one point, 'C' (cubic Bezier curve to) has two control points and the end point,
+// 'Z' (close) has none
+type PathSegment struct {
+	Command byte
+	Points  []Point
+}
+
+// Path is the outline of an item as stored in its path and copath attributes, relative to
+// the top left corner of the item
+type Path []PathSegment
+
+// MoveTo starts a new subpath at x, y
+func (p *Path) MoveTo(x float64, y float64) {
+	*p = append(*p, PathSegment{Command: 'M', Points: []Point{{x, y}}})
+}
+
+// LineTo adds a straight line to x, y
+func (p *Path) LineTo(x float64, y float64) {
+	*p = append(*p, PathSegment{Command: 'L', Points: []Point{{x, y}}})
+}
+
+// CurveTo adds a cubic Bezier curve with the control points x1, y1 and x2, y2 to x, y
+func (p *Path) CurveTo(x1 float64, y1 float64, x2 float64, y2 float64, x float64, y float64) {
+	*p = append(*p, PathSegment{Command: 'C', Points: []Point{{x1, y1}, {x2, y2}, {x, y}}})
+}
+
+// Close closes the current subpath
+func (p *Path) Close() {
+	*p = append(*p, PathSegment{Command: 'Z'})
+}
+
+// arcTo adds an elliptical arc around the centre cx, cy with the radii rx, ry from the angle
+// start through sweep (in radians, clockwise on the page), approximated by one cubic
+// Bezier curve per quarter circle at most. The current point must be the start of the arc
+func (p *Path) arcTo(cx float64, cy float64, rx float64, ry float64, start float64, sweep float64) {
+	n := int(math.Ceil(math.Abs(sweep)/(math.Pi/2) - 1e-9))
+	if n == 0 {
+		return
+	}
+	step := sweep / float64(n)
+	k := 4.0 / 3 * math.Tan(step/4)
+	for i := 0; i < n; i++ {
+		a, b := start+float64(i)*step, start+float64(i+1)*step
+		p.CurveTo(
+			cx+rx*(math.Cos(a)-k*math.Sin(a)), cy+ry*(math.Sin(a)+k*math.Cos(a)),
+			cx+rx*(math.Cos(b)+k*math.Sin(b)), cy+ry*(math.Sin(b)-k*math.Cos(b)),
+			cx+rx*math.Cos(b), cy+ry*math.Sin(b))
+	}
+}
+
+// Closed tells whether the last subpath of the path is closed
+func (p Path) Closed() bool {
+	return len(p) > 0 && p[len(p)-1].Command == 'Z'
+}
+
+// ParsePath parses a path such as "M0 0 L10 0 C15 0 20 5 20 10 Z". Besides the commands
+// Scribus writes, it understands the relative commands and H, V, S, Q and T of SVG, which are
+// converted into lines and cubic curves. Elliptical arcs (A) are not supported
+func ParsePath(s string) (Path, error) {
+	tokens := pathTokens(s)
+	var path Path
+	var current, start, lastControl Point
+	var command, last byte
+	for i := 0; i < len(tokens); {
+		if c := tokens[i]; len(c) == 1 && strings.Contains("MmLlHhVvCcSsQqTtZzAa", c) {
+			command = c[0]
+			i++
+		} else if command == 0 {
+			return nil, fmt.Errorf("path %q does not start with a command", s)
+		}
+		upper := command &^ 0x20
+		relative := command != upper
+		counts := map[byte]int{'M': 2, 'L': 2, 'H': 1, 'V': 1, 'C': 6, 'S': 4, 'Q': 4, 'T': 2, 'Z': 0}
+		n, ok := counts[upper]
+		if !ok {
+			return nil, fmt.Errorf("path command %c is not supported", command)
+		}
+		if i+n > len(tokens) {
+			return nil, fmt.Errorf("path %q ends within %c", s, command)
+		}
+		v := make([]float64, n)
+		for j := range v {
+			f, err := strconv.ParseFloat(tokens[i+j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("path %q: %v", s, err)
+			}
+			v[j] = f
+		}
+		i += n
+		// abs returns the k'th pair of values as a point
+		abs := func(k int) Point {
+			if relative {
+				return Point{current.X + v[2*k], current.Y + v[2*k+1]}
+			}
+			return Point{v[2*k], v[2*k+1]}
+		}
+		// reflect returns the control point mirrored at the current point if the last
+		// command was of the kind
+		reflect := func(kinds string) Point {
+			if strings.IndexByte(kinds, last) >= 0 {
+				return Point{2*current.X - lastControl.X, 2*current.Y - lastControl.Y}
+			}
+			return current
+		}
+		next := current
+		switch upper {
+		case 'M':
+			next = abs(0)
+			start = next
+			path.MoveTo(next.X, next.Y)
+			// Further pairs are lines
+			if relative {
+				command = 'l'
+			} else {
+				command = 'L'
+			}
+		case 'L', 'T':
+			next = abs(0)
+			if upper == 'T' {
+				q := reflect("QT")
+				path.quadTo(current, q, next)
+				lastControl = q
+			} else {
+				path.LineTo(next.X, next.Y)
+			}
+		case 'H':
+			next.X = v[0]
+			if relative {
+				next.X += current.X
+			}
+			path.LineTo(next.X, next.Y)
+		case 'V':
+			next.Y = v[0]
+			if relative {
+				next.Y += current.Y
+			}
+			path.LineTo(next.X, next.Y)
+		case 'C':
+			c1, c2 := abs(0), abs(1)
+			next = abs(2)
+			path.CurveTo(c1.X, c1.Y, c2.X, c2.Y, next.X, next.Y)
+			lastControl = c2
+		case 'S':
+			c1, c2 := reflect("CS"), abs(0)
+			next = abs(1)
+			path.CurveTo(c1.X, c1.Y, c2.X, c2.Y, next.X, next.Y)
+			lastControl = c2
+		case 'Q':
+			q := abs(0)
+			next = abs(1)
+			path.quadTo(current, q, next)
+			lastControl = q
+		case 'Z':
+			path.Close()
+			next = start
+		}
+		current, last = next, upper
+		if n == 0 && i < len(tokens) && !strings.Contains("MmLlHhVvCcSsQqTtZzAa", tokens[i]) {
+			return nil, fmt.Errorf("path %q has values after Z", s)
+		}
+	}
+	return path, nil
+}
+
+// pathTokens splits a path into commands and numbers
+func pathTokens(s string) []string {
+	var tokens []string
+	var number strings.Builder
+	flush := func() {
+		if number.Len() > 0 {
+			tokens = append(tokens, number.String())
+			number.Reset()
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '-' || c == '+':
+			// A sign starts a new number unless it follows an exponent
+			if prev := number.String(); prev != "" && !strings.HasSuffix(prev, "e") && !strings.HasSuffix(prev, "E") {
+				flush()
+			}
+			number.WriteByte(c)
+		case c == '.':
+			if strings.Contains(number.String(), ".") && !strings.ContainsAny(number.String(), "eE") {
+				flush()
+			}
+			number.WriteByte(c)
+		case c >= '0' && c <= '9', (c == 'e' || c == 'E') && number.Len() > 0:
+			number.WriteByte(c)
+		case c == ' ' || c == ',' || c == '\t' || c == '\n' || c == '\r':
+			flush()
+		default:
+			flush()
+			tokens = append(tokens, string(c))
+		}
+	}
+	flush()
+	return tokens
+}
+
+// quadTo adds the quadratic Bezier curve from p0 with the control point q to p as a cubic curve
+func (p *Path) quadTo(p0 Point, q Point, p1 Point) {
+	p.CurveTo(p0.X+2.0/3*(q.X-p0.X), p0.Y+2.0/3*(q.Y-p0.Y), p1.X+2.0/3*(q.X-p1.X), p1.Y+2.0/3*(q.Y-p1.Y), p1.X, p1.Y)
+}
+
+// Bounds returns the smallest rectangle that contains the path, which may be smaller than
+// the rectangle of its points because curves need not reach their control points
+func (p Path) Bounds() (min Point, max Point) {
+	first := true
+	add := func(pt Point) {
+		if first {
+			min, max, first = pt, pt, false
+			return
+		}
+		min = Point{math.Min(min.X, pt.X), math.Min(min.Y, pt.Y)}
+		max = Point{math.Max(max.X, pt.X), math.Max(max.Y, pt.Y)}
+	}
+	var current Point
+	for _, s := range p {
+		switch s.Command {
+		case 'M', 'L':
+			current = s.Points[0]
+			add(current)
+		case 'C':
+			p0, p1, p2, p3 := current, s.Points[0], s.Points[1], s.Points[2]
+			add(p3)
+			for _, t := range append(cubicExtrema(p0.X, p1.X, p2.X, p3.X), cubicExtrema(p0.Y, p1.Y, p2.Y, p3.Y)...) {
+				add(cubicPoint(p0, p1, p2, p3, t))
+			}
+			current = p3
+		}
+	}
+	return min, max
+}
+
+// cubicPoint returns the point of a cubic Bezier curve at t
+func cubicPoint(p0, p1, p2, p3 Point, t float64) Point {
+	u := 1 - t
+	a, b, c, d := u*u*u, 3*u*u*t, 3*u*t*t, t*t*t
+	return Point{a*p0.X + b*p1.X + c*p2.X + d*p3.X, a*p0.Y + b*p1.Y + c*p2.Y + d*p3.Y}
+}
+
+// cubicExtrema returns the parameters in (0, 1) at which one coordinate of a cubic Bezier
+// curve has a minimum or maximum, i.e., the roots of its derivative
+func cubicExtrema(p0, p1, p2, p3 float64) []float64 {
+	a := -p0 + 3*p1 - 3*p2 + p3
+	b := 2 * (p0 - 2*p1 + p2)
+	c := p1 - p0
+	var roots []float64
+	if math.Abs(a) < 1e-12 {
+		if math.Abs(b) > 1e-12 {
+			roots = append(roots, -c/b)
+		}
+	} else if d := b*b - 4*a*c; d >= 0 {
+		sq := math.Sqrt(d)
+		roots = append(roots, (-b+sq)/(2*a), (-b-sq)/(2*a))
+	}
+	var inside []float64
+	for _, t := range roots {
+		if t > 0 && t < 1 {
+			inside = append(inside, t)
+		}
+	}
+	return inside
+}
+
+// Matrix is an affine transformation that maps x, y to A*x + C*y + E, B*x + D*y + F, as
+// in SVG and Qt
+type Matrix struct {
+	A, B, C, D, E, F float64
+}
+
+// Identity returns the transformation that does not change anything
+func Identity() Matrix { return Matrix{A: 1, D: 1} }
+
+// Translation returns the transformation that moves by dx, dy
+func Translation(dx float64, dy float64) Matrix { return Matrix{A: 1, D: 1, E: dx, F: dy} }
+
+// Scaling returns the transformation that scales by sx, sy around 0, 0. Negative factors flip
+func Scaling(sx float64, sy float64) Matrix { return Matrix{A: sx, D: sy} }
+
+// Rotation returns the transformation that rotates clockwise on the page by degrees around 0, 0
+func Rotation(degrees float64) Matrix {
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+	return Matrix{A: cos, B: sin, C: -sin, D: cos}
+}
+
+// Skewing returns the transformation that skews by the angles x and y in degrees
+func Skewing(x float64, y float64) Matrix {
+	return Matrix{A: 1, B: math.Tan(y * math.Pi / 180), C: math.Tan(x * math.Pi / 180), D: 1}
+}
+
+// Then returns the transformation that applies m and then n
+func (m Matrix) Then(n Matrix) Matrix {
+	return Matrix{
+		A: n.A*m.A + n.C*m.B, B: n.B*m.A + n.D*m.B,
+		C: n.A*m.C + n.C*m.D, D: n.B*m.C + n.D*m.D,
+		E: n.A*m.E + n.C*m.F + n.E, F: n.B*m.E + n.D*m.F + n.F,
+	}
+}
+
+// Apply returns the transformed point
+func (m Matrix) Apply(pt Point) Point {
+	return Point{m.A*pt.X + m.C*pt.Y + m.E, m.B*pt.X + m.D*pt.Y + m.F}
+}
+
+// Transform returns the path transformed by m. Affine transformations keep Bezier curves
+// exact, so only the points are transformed
+func (p Path) Transform(m Matrix) Path {
+	transformed := make(Path, len(p))
+	for i, s := range p {
+		transformed[i] = PathSegment{Command: s.Command, Points: make([]Point, len(s.Points))}
+		for j, pt := range s.Points {
+			transformed[i].Points[j] = m.Apply(pt)
+		}
+	}
+	return transformed
+}
+
+// String returns the path the way Scribus writes it, e.g., "M0 0 L10 0 L10 10 Z"
+func (p Path) String() string {
+	var parts []string
+	for _, s := range p {
+		coords := make([]string, 0, 2*len(s.Points))
+		for _, pt := range s.Points {
+			coords = append(coords, formatFloat(pt.X), formatFloat(pt.Y))
+		}
+		parts = append(parts, string(s.Command)+strings.Join(coords, " "))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Outline returns the outline of the item (its path attribute)
+func (po PAGEOBJECT) Outline() (Path, error) { return ParsePath(po.Path) }
+
+// SetOutline replaces the outline of the item and its contour line (copath)
+func (po *PAGEOBJECT) SetOutline(path Path) {
+	po.Path = path.String()
+	po.Copath = po.Path
+}
+
+// TransformOutline transforms the outline and the contour line of the item by m, returns
+// error if they cannot be parsed. The position and size of the item do not change
+func (po *PAGEOBJECT) TransformOutline(m Matrix) error {
+	path, err := ParsePath(po.Path)
+	if err != nil {
+		return err
+	}
+	copath, err := ParsePath(po.Copath)
+	if err != nil {
+		return err
+	}
+	po.Path, po.Copath = path.Transform(m).String(), copath.Transform(m).String()
+	return nil
+}
+
+// SetSize changes the size of the item and scales its outline and contour line with it, so
+// that non-rectangular items keep their shape. The top left corner stays in place. Lines
+// (PTYPE 5) only change their length. Returns error
+func (po *PAGEOBJECT) SetSize(width float64, height float64) error {
+	if po.PTYPE == "5" {
+		height = parseFloat(po.HEIGHT)
+	}
+	sx, sy := 1.0, 1.0
+	if w := parseFloat(po.WIDTH); w != 0 {
+		sx = width / w
+	}
+	if h := parseFloat(po.HEIGHT); h != 0 {
+		sy = height / h
+	}
+	if po.PTYPE == "5" {
+		sy = 1
+	}
+	if err := po.TransformOutline(Scaling(sx, sy)); err != nil {
+		return err
+	}
+	po.WIDTH, po.HEIGHT = formatFloat(width), formatFloat(height)
+	return nil
+}
+
+// Flipped returns whether the item is flipped horizontally and vertically
+func (po PAGEOBJECT) Flipped() (horizontal bool, vertical bool) {
+	return po.FLIPPEDH == "1", po.FLIPPEDV == "1"
+}
+
+// FlipOutline mirrors the outline and the contour line of the item within its box and
+// toggles FLIPPEDH and FLIPPEDV, returns error. FLOP is not a flip: it is the first line
+// offset of text frames
+func (po *PAGEOBJECT) FlipOutline(horizontal bool, vertical bool) error {
+	m := Identity()
+	if horizontal {
+		m = m.Then(Scaling(-1, 1)).Then(Translation(parseFloat(po.WIDTH), 0))
+	}
+	if vertical {
+		m = m.Then(Scaling(1, -1)).Then(Translation(0, parseFloat(po.HEIGHT)))
+	}
+	if err := po.TransformOutline(m); err != nil {
+		return err
+	}
+	h, v := po.Flipped()
+	if horizontal {
+		po.FLIPPEDH = boolAttr(!h)
+	}
+	if vertical {
+		po.FLIPPEDV = boolAttr(!v)
+	}
+	return nil
+}
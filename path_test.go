@@ -0,0 +1,99 @@
+package scribus
+
+import (
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"M0 0 L118.5 0 L118.5 52.5 L0 52.5 L0 0 Z", "M0 0 L118.5 0 L118.5 52.5 L0 52.5 L0 0 Z"},
+		{"m10,10 h20 v-5 H0 V0 z", "M10 10 L30 10 L30 5 L0 5 L0 0 Z"},
+		{"M0 0C0 5 5 10 10 10S20 5 20 0", "M0 0 C0 5 5 10 10 10 C15 10 20 5 20 0"},
+		{"M0 0 Q10 10 20 0 T40 0", "M0 0 C6.666667 6.666667 13.333333 6.666667 20 0 C26.666667 -6.666667 33.333333 -6.666667 40 0"},
+		{"M1e1-5 10 0", "M10 -5 L10 0"},
+		{"M.5.5 1 1", "M0.5 0.5 L1 1"},
+	}
+	for _, test := range tests {
+		path, err := ParsePath(test.path)
+		if err != nil {
+			t.Errorf("ParsePath(%q) error: %v", test.path, err)
+			continue
+		}
+		if got := path.String(); got != test.want {
+			t.Errorf("ParsePath(%q) was incorrect, got: %q, want: %q.", test.path, got, test.want)
+		}
+	}
+	for _, bad := range []string{"0 0 L1 1", "M0 0 L1", "M0 0 A5 5 0 0 1 10 10", "M0 0 Lx 1"} {
+		if _, err := ParsePath(bad); err == nil {
+			t.Errorf("ParsePath(%q) should be an error", bad)
+		}
+	}
+}
+
+func TestPathGeometry(t *testing.T) {
+	path, err := ParsePath("M0 10 C0 -3.333333 20 -3.333333 20 10 Z")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	// The curve peaks at t = 0.5, at 10 - 3 / 4 * 13.333333 = 0
+	min, max := path.Bounds()
+	if formatFloat(min.X) != "0" || formatFloat(min.Y) != "0" || max.X != 20 || max.Y != 10 {
+		t.Errorf("Bounds() was incorrect, got: %v, %v", min, max)
+	}
+
+	m := Rotation(90).Then(Translation(10, 0))
+	if got := m.Apply(Point{10, 0}); formatFloat(got.X) != "10" || formatFloat(got.Y) != "10" {
+		t.Errorf("Apply() was incorrect, got: %v", got)
+	}
+	if got := Skewing(45, 0).Apply(Point{0, 10}); formatFloat(got.X) != "10" || got.Y != 10 {
+		t.Errorf("Skewing() was incorrect, got: %v", got)
+	}
+	if got := path.Transform(Scaling(2, -1)).String(); got != "M0 -10 C0 3.333333 40 3.333333 40 -10 Z" {
+		t.Errorf("Transform() was incorrect, got: %q", got)
+	}
+}
+
+func TestPageObjectOutline(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := &document.DOCUMENT
+	var path Path
+	path.MoveTo(0, 0)
+	path.LineTo(40, 0)
+	path.LineTo(0, 20)
+	path.Close()
+	triangle, err := doc.AddPath(0, 0, 0, path, ShapeStyle{Fill: "Black"})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if err := triangle.SetSize(80, 10); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if triangle.WIDTH != "80" || triangle.HEIGHT != "10" || triangle.Path != "M0 0 L80 0 L0 10 Z" || triangle.Copath != triangle.Path {
+		t.Errorf("SetSize() was incorrect, got: %v x %v, %q", triangle.WIDTH, triangle.HEIGHT, triangle.Path)
+	}
+	flop := triangle.FLOP
+	if err := triangle.FlipOutline(true, false); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if h, v := triangle.Flipped(); !h || v || triangle.Path != "M80 0 L0 0 L80 10 Z" || triangle.FLOP != flop {
+		t.Errorf("FlipOutline() was incorrect, got: %v, %v, %q", h, v, triangle.Path)
+	}
+	if err := triangle.FlipOutline(true, true); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if h, v := triangle.Flipped(); h || !v || triangle.Path != "M0 10 L80 10 L0 0 Z" {
+		t.Errorf("FlipOutline() was incorrect, got: %v, %v, %q", h, v, triangle.Path)
+	}
+
+	triangle.Path = "M0 0 X"
+	if err := triangle.SetSize(10, 10); err == nil || triangle.WIDTH != "80" {
+		t.Errorf("SetSize() of an invalid path should be an error")
+	}
+}
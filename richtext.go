@@ -0,0 +1,269 @@
+package scribus
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// Paragraph is a paragraph of a StoryText: a sequence of runs (ITEXTs) with their own
+// character attributes, and the para that ends the paragraph and holds its attributes.
+// Special characters such as tabs are part of the run text (see StoryText.PlainText)
+type Paragraph struct {
+	Runs  []ITEXT
+	Style Para
+}
+
+// NewRun returns a run with the given text and no character attributes of its own
+func NewRun(text string) ITEXT {
+	return ITEXT{CH: text}
+}
+
+// AddRun appends a run with text and the character attributes of format to the
+// Paragraph and returns a pointer to it
+func (p *Paragraph) AddRun(text string, format ITEXT) *ITEXT {
+	format.CH = text
+	p.Runs = append(p.Runs, format)
+	return &p.Runs[len(p.Runs)-1]
+}
+
+// Text returns the text of the Paragraph
+func (p Paragraph) Text() string {
+	var sb strings.Builder
+	for _, run := range p.Runs {
+		sb.WriteString(run.CH)
+	}
+	return sb.String()
+}
+
+// Paragraphs returns the content of the StoryText as paragraphs of runs. The last
+// paragraph gets the attributes of the trail
+func (st StoryText) Paragraphs() []Paragraph {
+	var paragraphs []Paragraph
+	var current Paragraph
+	for _, ref := range st.sequence() {
+		switch ref.kind {
+		case storyITEXT:
+			current.Runs = append(current.Runs, st.ITEXT[ref.index])
+		case storyElement:
+			element := st.Elements[ref.index]
+			if char, ok := specialChars[element.XMLName.Local]; ok {
+				run := itextFromAttrs(element.Attr)
+				run.CH = char
+				current.Runs = append(current.Runs, run)
+			}
+		case storyPara:
+			current.Style = st.Para[ref.index]
+			paragraphs = append(paragraphs, current)
+			current = Paragraph{}
+		}
+	}
+	if len(current.Runs) > 0 || len(paragraphs) > 0 {
+		copyAttrs(st.Trail, &current.Style, "para")
+		paragraphs = append(paragraphs, current)
+	}
+	for _, span := range st.StoryTextSpan {
+		paragraphs = append(paragraphs, Paragraph{Runs: []ITEXT{span.ITEXT}, Style: span.Para})
+	}
+	return paragraphs
+}
+
+// SetParagraphs replaces the content of the StoryText with paragraphs. Special
+// characters in the runs become the corresponding elements with the attributes of
+// their run; the attributes of the last paragraph are written to the trail
+func (st *StoryText) SetParagraphs(paragraphs []Paragraph) {
+	st.resetContent()
+	st.StoryTextSpan = nil
+	for i, p := range paragraphs {
+		for _, run := range p.Runs {
+			st.appendRun(run)
+		}
+		if i < len(paragraphs)-1 {
+			st.appendPara(p.Style)
+		} else {
+			st.Trail = Trail{}
+			copyAttrs(p.Style, &st.Trail, "trail")
+		}
+	}
+}
+
+// copyAttrs copies the XML attributes of src to dst, which is decoded as an element called name
+func copyAttrs(src interface{}, dst interface{}, name string) error {
+	b, err := xml.Marshal(src)
+	if err != nil {
+		return err
+	}
+	d := xml.NewDecoder(bytes.NewReader(b))
+	token, err := d.Token()
+	if err != nil {
+		return err
+	}
+	start := token.(xml.StartElement)
+	start.Name = xml.Name{Local: name}
+	return d.DecodeElement(dst, &start)
+}
+
+// itextAttrs returns the non-empty character attributes of an ITEXT, without CH
+func itextAttrs(itext ITEXT) []xml.Attr {
+	b, err := xml.Marshal(itext)
+	if err != nil {
+		return nil
+	}
+	token, err := xml.NewDecoder(bytes.NewReader(b)).Token()
+	if err != nil {
+		return nil
+	}
+	var attrs []xml.Attr
+	for _, a := range token.(xml.StartElement).Attr {
+		if a.Value != "" && a.Name.Local != "CH" {
+			attrs = append(attrs, a)
+		}
+	}
+	return attrs
+}
+
+// itextFromAttrs returns an ITEXT with the given XML attributes
+func itextFromAttrs(attrs []xml.Attr) ITEXT {
+	var itext ITEXT
+	var buf bytes.Buffer
+	e := xml.NewEncoder(&buf)
+	start := xml.StartElement{Name: xml.Name{Local: "ITEXT"}, Attr: attrs}
+	if e.EncodeToken(start) == nil && e.EncodeToken(start.End()) == nil && e.Flush() == nil {
+		xml.Unmarshal(buf.Bytes(), &itext)
+	}
+	return itext
+}
+
+// Character features as stored in the FEATURES attribute of ITEXT and CHARSTYLE
+const (
+	FeatureUnderline      = "underline"
+	FeatureUnderlineWords = "underlinewords"
+	FeatureStrike         = "strike"
+	FeatureSuperscript    = "superscript"
+	FeatureSubscript      = "subscript"
+	FeatureOutline        = "outline"
+	FeatureShadowed       = "shadowed"
+	FeatureAllCaps        = "allcaps"
+	FeatureSmallCaps      = "smallcaps"
+)
+
+// HasFeature tells whether feature (e.g., FeatureUnderline) is set in FEATURES
+func (itext ITEXT) HasFeature(feature string) bool {
+	for _, f := range strings.Fields(itext.FEATURES) {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFeature sets or clears feature (e.g., FeatureUnderline) in FEATURES. Features of the
+// character style are still inherited ("inherit"), so a feature that is set in the style
+// cannot be cleared here; use a different CPARENT for that
+func (itext *ITEXT) SetFeature(feature string, on bool) {
+	features := []string{"inherit"}
+	for _, f := range strings.Fields(itext.FEATURES) {
+		if f != feature && f != "inherit" {
+			features = append(features, f)
+		}
+	}
+	if on {
+		features = append(features, feature)
+	}
+	if len(features) == 1 {
+		itext.FEATURES = ""
+		return
+	}
+	itext.FEATURES = strings.Join(features, " ")
+}
+
+// Underline tells whether the run is underlined
+func (itext ITEXT) Underline() bool { return itext.HasFeature(FeatureUnderline) }
+
+// SetUnderline underlines the run or not
+func (itext *ITEXT) SetUnderline(on bool) { itext.SetFeature(FeatureUnderline, on) }
+
+// Strike tells whether the run is struck through
+func (itext ITEXT) Strike() bool { return itext.HasFeature(FeatureStrike) }
+
+// SetStrike strikes the run through or not
+func (itext *ITEXT) SetStrike(on bool) { itext.SetFeature(FeatureStrike, on) }
+
+// Superscript tells whether the run is superscript
+func (itext ITEXT) Superscript() bool { return itext.HasFeature(FeatureSuperscript) }
+
+// SetSuperscript makes the run superscript or not
+func (itext *ITEXT) SetSuperscript(on bool) {
+	if on {
+		itext.SetFeature(FeatureSubscript, false)
+	}
+	itext.SetFeature(FeatureSuperscript, on)
+}
+
+// Subscript tells whether the run is subscript
+func (itext ITEXT) Subscript() bool { return itext.HasFeature(FeatureSubscript) }
+
+// SetSubscript makes the run subscript or not
+func (itext *ITEXT) SetSubscript(on bool) {
+	if on {
+		itext.SetFeature(FeatureSuperscript, false)
+	}
+	itext.SetFeature(FeatureSubscript, on)
+}
+
+// SmallCaps tells whether the run is set in small capitals
+func (itext ITEXT) SmallCaps() bool { return itext.HasFeature(FeatureSmallCaps) }
+
+// SetSmallCaps sets the run in small capitals or not
+func (itext *ITEXT) SetSmallCaps(on bool) { itext.SetFeature(FeatureSmallCaps, on) }
+
+// FontSize returns the font size in points, or 0 if it is inherited
+func (itext ITEXT) FontSize() float64 { return parseFloat(itext.FONTSIZE) }
+
+// SetFontSize sets the font size in points
+func (itext *ITEXT) SetFontSize(size float64) { itext.FONTSIZE = formatFloat(size) }
+
+// SetColor sets the fill colour (the NAME of a COLOR) and its shade in percent
+func (itext *ITEXT) SetColor(color string, shade float64) {
+	itext.FCOLOR = color
+	itext.FSHADE = formatFloat(shade)
+}
+
+// Tracking returns the tracking in percent of the font size
+func (itext ITEXT) Tracking() float64 { return parseFloat(itext.KERN) }
+
+// SetTracking sets the tracking in percent of the font size
+func (itext *ITEXT) SetTracking(tracking float64) { itext.KERN = formatFloat(tracking) }
+
+// SetScaling sets the horizontal and vertical scaling of the glyphs in percent
+func (itext *ITEXT) SetScaling(horizontal float64, vertical float64) {
+	itext.SCALEH = formatFloat(horizontal)
+	itext.SCALEV = formatFloat(vertical)
+}
+
+// BaselineOffset returns the baseline offset in percent of the font size
+func (itext ITEXT) BaselineOffset() float64 { return parseFloat(itext.BASEO) }
+
+// SetBaselineOffset sets the baseline offset in percent of the font size
+func (itext *ITEXT) SetBaselineOffset(offset float64) { itext.BASEO = formatFloat(offset) }
+
+// SetLanguage sets the language of the run, e.g., "en_GB"
+func (itext *ITEXT) SetLanguage(language string) { itext.LANGUAGE = language }
+
+// SetCharStyle sets the character style (the CNAME of a CHARSTYLE) of the run
+func (itext *ITEXT) SetCharStyle(style string) { itext.CPARENT = style }
+
+// SameFormat tells whether two runs have the same character attributes
+func (itext ITEXT) SameFormat(other ITEXT) bool {
+	itext.CH, other.CH = "", ""
+	return attrsKey(itextAttrs(itext)) == attrsKey(itextAttrs(other))
+}
+
+func attrsKey(attrs []xml.Attr) string {
+	var sb strings.Builder
+	for _, a := range attrs {
+		sb.WriteString(a.Name.Local + "=" + strconv.Quote(a.Value) + " ")
+	}
+	return sb.String()
+}
@@ -0,0 +1,71 @@
+package scribus
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestParagraphs(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	st := &document.DOCUMENT.PAGEOBJECT[3].StoryText
+	paragraphs := st.Paragraphs()
+	if len(paragraphs) != 3 || paragraphs[2].Text() != "Five" || paragraphs[2].Style.BulletStr != "•" {
+		t.Fatalf("paragraphs were incorrect, got: %+v", paragraphs)
+	}
+
+	bold := ITEXT{FONT: "FreeSans Bold"}
+	bold.SetColor("Black", 80)
+	bold.SetUnderline(true)
+	bold.SetTracking(2.5)
+	p := &paragraphs[0]
+	p.Runs = nil
+	p.AddRun("Plain ", NewRun(""))
+	p.AddRun("bold\tstrong", bold)
+	st.SetParagraphs(paragraphs)
+
+	clone, err := document.Clone()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	st = &clone.DOCUMENT.PAGEOBJECT[3].StoryText
+	if st.PlainText() != "Plain bold\tstrong\nFour\nFive" {
+		t.Errorf("PlainText() was incorrect, got: %q", st.PlainText())
+	}
+	runs := st.Paragraphs()[0].Runs
+	if len(runs) != 4 {
+		t.Fatalf("len(runs) was incorrect, got: %v, want: %v.", len(runs), 4)
+	}
+	if !runs[1].Underline() || runs[1].FSHADE != "80" || runs[1].Tracking() != 2.5 || !runs[2].SameFormat(runs[1]) || runs[0].Underline() {
+		t.Errorf("run attributes were incorrect, got: %+v", runs)
+	}
+	if st.Trail.BulletStr != "•" {
+		t.Errorf("st.Trail.BulletStr was incorrect, got: %v, want: %v.", st.Trail.BulletStr, "•")
+	}
+}
+
+func TestNewTextAttributes(t *testing.T) {
+	// Scribus reads empty attributes as values, e.g., FONTSIZE="" as 0 pt, so attributes
+	// that are not set must be left out
+	var st StoryText
+	st.SetHTML("<p>Hello <b>world</b></p><ul><li>One</li></ul>", DefaultHTMLStyles())
+	b, err := xml.Marshal(st)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if out := string(b); strings.Contains(out, `=""`) {
+		t.Errorf("new text was written with empty attributes, got: %v", out)
+	}
+	p := Paragraph{Style: Para{ALIGN: "1"}}
+	p.AddRun("Run", NewRun(""))
+	st.SetParagraphs([]Paragraph{p, p})
+	if b, err = xml.Marshal(st); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if out := string(b); strings.Contains(out, `=""`) || !strings.Contains(out, `<para ALIGN="1">`) {
+		t.Errorf("new paragraphs were written with empty attributes, got: %v", out)
+	}
+}
@@ -0,0 +1,328 @@
+package scribus
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Rules of templates (see FillTemplate). A rule is given to an item as an item attribute
+// whose Name is the rule and whose Value is its argument, or in the name of the item,
+// e.g., "Price [if price] [fill {{price_color}}]". Conditions are a field, which is true if
+// it has a value other than "", "0" and "false" (or has elements), a negated field such as
+// "!price", or a comparison such as "status == sold" or "status != sold"
+const (
+	RuleIf        = "if"         // The item is deleted unless the condition is true
+	RuleUnless    = "unless"     // The item is deleted if the condition is true
+	RuleShowIf    = "show-if"    // The item is not printed unless the condition is true
+	RuleHideIf    = "hide-if"    // The item is not printed if the condition is true
+	RuleLayer     = "layer"      // The item is moved to the layer with the name, e.g., "{{language}}"
+	RuleFill      = "fill"       // Fill colour: the name of a COLOR or an RGB value such as "#ff8000"
+	RuleLine      = "line"       // Line colour
+	RuleTextColor = "text-color" // Colour of the text
+	RuleRepeat    = "repeat"     // The item is repeated per element of a list, e.g., "products as p" (items on pages, not inside groups)
+	RuleRepeatGap = "repeat-gap" // Space between the repeated items
+)
+
+// templateRuleNames are the rules that are recognised
+var templateRuleNames = map[string]bool{
+	RuleIf: true, RuleUnless: true, RuleShowIf: true, RuleHideIf: true, RuleLayer: true,
+	RuleFill: true, RuleLine: true, RuleTextColor: true, RuleRepeat: true, RuleRepeatGap: true,
+}
+
+// ruleInNamePattern matches a rule in the name of an item
+var ruleInNamePattern = regexp.MustCompile(`\s*\[([a-z-]+)(?:\s+([^\]]*))?\]`)
+
+// templateRule is a rule of an item
+type templateRule struct {
+	name string
+	arg  string
+}
+
+// itemRules returns the rules of an item
+func itemRules(po PAGEOBJECT) []templateRule {
+	var rules []templateRule
+	if po.PageItemAttributes != nil {
+		for _, attr := range po.PageItemAttributes.ItemAttribute {
+			if templateRuleNames[attr.Name] {
+				rules = append(rules, templateRule{name: attr.Name, arg: strings.TrimSpace(attr.Value)})
+			}
+		}
+	}
+	for _, m := range ruleInNamePattern.FindAllStringSubmatch(po.ANNAME, -1) {
+		if templateRuleNames[m[1]] {
+			rules = append(rules, templateRule{name: m[1], arg: strings.TrimSpace(m[2])})
+		}
+	}
+	return rules
+}
+
+// ruleArg returns the argument of the rule with name, and whether the item has the rule
+func ruleArg(po PAGEOBJECT, name string) (string, bool) {
+	for _, rule := range itemRules(po) {
+		if rule.name == name {
+			return rule.arg, true
+		}
+	}
+	return "", false
+}
+
+// removeRules removes the rules from an item
+func removeRules(po *PAGEOBJECT) {
+	po.ANNAME = strings.TrimSpace(ruleInNamePattern.ReplaceAllStringFunc(po.ANNAME, func(match string) string {
+		if templateRuleNames[ruleInNamePattern.FindStringSubmatch(match)[1]] {
+			return ""
+		}
+		return match
+	}))
+	if po.PageItemAttributes == nil {
+		return
+	}
+	var attrs []ItemAttribute
+	for _, attr := range po.PageItemAttributes.ItemAttribute {
+		if !templateRuleNames[attr.Name] {
+			attrs = append(attrs, attr)
+		}
+	}
+	po.PageItemAttributes.ItemAttribute = attrs
+	if len(attrs) == 0 {
+		po.PageItemAttributes = nil
+	}
+}
+
+// fillItems applies the rules of items (including the items inside groups), fills their
+// placeholders and returns the items that are kept
+func (tf *templateFiller) fillItems(doc *DOCUMENT, objs []PAGEOBJECT) []PAGEOBJECT {
+	var kept []PAGEOBJECT
+	for _, po := range objs {
+		if !tf.applyRules(doc, &po) {
+			tf.deleted = true
+			continue
+		}
+		tf.fillPageObject(&po)
+		po.PAGEOBJECT = tf.fillItems(doc, po.PAGEOBJECT)
+		kept = append(kept, po)
+	}
+	return kept
+}
+
+// applyRules applies the rules of an item except repeat and removes them, returns whether
+// the item is kept
+func (tf *templateFiller) applyRules(doc *DOCUMENT, po *PAGEOBJECT) bool {
+	rules := itemRules(*po)
+	removeRules(po)
+	for _, rule := range rules {
+		var err error
+		switch rule.name {
+		case RuleIf:
+			if !tf.condition(rule.arg) {
+				return false
+			}
+		case RuleUnless:
+			if tf.condition(rule.arg) {
+				return false
+			}
+		case RuleShowIf:
+			po.PRINTABLE = boolAttr(tf.condition(rule.arg))
+		case RuleHideIf:
+			po.PRINTABLE = boolAttr(!tf.condition(rule.arg))
+		case RuleLayer:
+			name := tf.fillString(rule.arg)
+			if l := doc.GetLayerByName(name); l != nil {
+				setLayer(po, l.NUMMER)
+			} else {
+				err = fmt.Errorf("layer %v not found", name)
+			}
+		case RuleFill, RuleLine, RuleTextColor:
+			var color string
+			if color, err = doc.colorFor(tf.fillString(rule.arg)); err == nil {
+				setRuleColor(po, rule.name, color)
+			}
+		}
+		if err != nil {
+			tf.errs = append(tf.errs, fmt.Errorf("[%v %v]: %v", rule.name, rule.arg, err))
+		}
+	}
+	return true
+}
+
+// setLayer moves an item (including the items inside it) to the layer with the number
+func setLayer(po *PAGEOBJECT, number string) {
+	po.LAYER = number
+	for i := range po.PAGEOBJECT {
+		setLayer(&po.PAGEOBJECT[i], number)
+	}
+}
+
+// setRuleColor sets the colour of an item for a colour rule
+func setRuleColor(po *PAGEOBJECT, rule string, color string) {
+	switch rule {
+	case RuleFill:
+		po.PCOLOR = color
+	case RuleLine:
+		po.PCOLOR2 = color
+	case RuleTextColor:
+		for i := range po.StoryText.ITEXT {
+			po.StoryText.ITEXT[i].FCOLOR = color
+		}
+		for i := range po.StoryText.StoryTextSpan {
+			po.StoryText.StoryTextSpan[i].ITEXT.FCOLOR = color
+		}
+	}
+}
+
+// condition evaluates the condition of a rule
+func (tf *templateFiller) condition(cond string) bool {
+	cond = strings.TrimSpace(cond)
+	if m := placeholderPattern.FindStringSubmatch(cond); m != nil && m[0] == cond {
+		cond = strings.TrimSpace(m[1])
+	}
+	for _, op := range []string{"==", "!="} {
+		if field, operand, ok := strings.Cut(cond, op); ok {
+			value, _ := tf.value(strings.TrimSpace(field))
+			operand = strings.TrimSpace(operand)
+			if unquoted, err := strconv.Unquote(operand); err == nil {
+				operand = unquoted
+			}
+			return (templateString(value, tf.options) == operand) == (op == "==")
+		}
+	}
+	if field, ok := strings.CutPrefix(cond, "!"); ok {
+		return !tf.condition(field)
+	}
+	value, found := tf.value(cond)
+	return found && truthy(value)
+}
+
+// value returns the value of a field and whether it was found
+func (tf *templateFiller) value(field string) (interface{}, bool) {
+	value, path, found := tf.lookup(field)
+	if found {
+		tf.used[path] = true
+	}
+	return value, found
+}
+
+// truthy tells whether a value counts as true in conditions
+func truthy(value interface{}) bool {
+	v := indirect(reflect.ValueOf(value))
+	switch v.Kind() {
+	case reflect.Invalid:
+		return false
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() > 0
+	}
+	s := strings.TrimSpace(templateString(v.Interface(), TemplateOptions{}))
+	return s != "" && s != "0" && s != "false"
+}
+
+// repeatItem repeats the item with the rule repeat (see RuleRepeat) per element of a list,
+// one below the other. The repeated items flow onto copies of the page of the item (see
+// overflowPage) when the bottom margin is reached. Fields named like the
+// element, e.g., {{p.name}} for "products as p" ({{item.name}} by default), refer to the
+// element. Returns the filled items
+func (tf *templateFiller) repeatItem(doc *DOCUMENT, po PAGEOBJECT, arg string) []PAGEOBJECT {
+	field, name, ok := strings.Cut(arg, " as ")
+	field, name = strings.TrimSpace(field), strings.TrimSpace(name)
+	if !ok || name == "" {
+		name = "item"
+	}
+	value, path, found := tf.lookup(field)
+	if !found {
+		tf.unresolved[field] = true
+		tf.deleted = true
+		return nil
+	}
+	tf.used[path] = true
+	n := 0
+	if v := indirect(reflect.ValueOf(value)); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		n = v.Len()
+	}
+	gap := 0.0
+	if s, ok := ruleArg(po, RuleRepeatGap); ok {
+		gap = parseFloat(s)
+	}
+	if n == 0 {
+		tf.deleted = true
+	}
+
+	var items []PAGEOBJECT
+	pageIndex := doc.pageIndex(po.OwnPage)
+	page := PAGE{}
+	if pageIndex >= 0 {
+		page = doc.PAGE[pageIndex]
+	}
+	height := parseFloat(po.HEIGHT)
+	top := parseFloat(po.YPOS)
+	if tf.nextID == 0 {
+		tf.nextID = doc.maxItemID() + 1
+	}
+	dx, dy := 0.0, 0.0
+	current := page
+	overflowed := 0
+	for i := 0; i < n; i++ {
+		clone, err := po.Clone()
+		if err != nil {
+			tf.errs = append(tf.errs, err)
+			break
+		}
+		if i > 0 {
+			dy += height + gap
+			bottom := parseFloat(current.PAGEYPOS) + parseFloat(current.PAGEHEIGHT) - parseFloat(current.BORDERBOTTOM)
+			if pageIndex >= 0 && top+dy+height > bottom {
+				var static []PAGEOBJECT
+				current, static = tf.overflowPage(doc, page, overflowed)
+				overflowed++
+				items = append(items, static...)
+				dx = parseFloat(current.PAGEXPOS) - parseFloat(page.PAGEXPOS)
+				dy = parseFloat(current.PAGEYPOS) - parseFloat(page.PAGEYPOS)
+			}
+			shiftPageObject(&clone, dx, dy)
+			renumbered := []PAGEOBJECT{clone}
+			renumberItems(renumbered, &tf.nextID)
+			clone = renumbered[0]
+			if pageIndex >= 0 {
+				clone.OwnPage = current.NUM
+			}
+		}
+		tf.aliases[name] = path + "." + strconv.Itoa(i)
+		items = append(items, tf.fillItems(doc, []PAGEOBJECT{clone})...)
+	}
+	delete(tf.aliases, name)
+	return items
+}
+
+// overflowPage returns the k-th page (from 0) that repeated items of page flow onto. All
+// repeated items of a page share these pages, which are added after the last page when they
+// are first needed. A new page gets filled copies of the items of page that are not repeated,
+// which are returned too
+func (tf *templateFiller) overflowPage(doc *DOCUMENT, page PAGE, k int) (PAGE, []PAGEOBJECT) {
+	if pages := tf.overflow[page.NUM]; k < len(pages) {
+		return pages[k], nil
+	}
+	added := doc.appendPage(page)
+	tf.overflow[page.NUM] = append(tf.overflow[page.NUM], added)
+	dx := parseFloat(added.PAGEXPOS) - parseFloat(page.PAGEXPOS)
+	dy := parseFloat(added.PAGEYPOS) - parseFloat(page.PAGEYPOS)
+	// While FillTemplate runs, doc.PAGEOBJECT still holds the unfilled items
+	var static []PAGEOBJECT
+	for _, po := range doc.PAGEOBJECT {
+		if _, repeated := ruleArg(po, RuleRepeat); repeated || po.OwnPage != page.NUM {
+			continue
+		}
+		clone, err := po.Clone()
+		if err != nil {
+			tf.errs = append(tf.errs, err)
+			continue
+		}
+		shiftPageObject(&clone, dx, dy)
+		clone.OwnPage = added.NUM
+		static = append(static, clone)
+	}
+	renumberItems(static, &tf.nextID)
+	return added, tf.fillItems(doc, static)
+}
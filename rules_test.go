@@ -0,0 +1,106 @@
+package scribus
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTemplateRules(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := &document.DOCUMENT
+	if _, err := doc.AddLayer("EN"); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	row := &doc.PAGEOBJECT[0]
+	row.ANNAME = "Row [repeat products as p] [repeat-gap 10]"
+	row.StoryText.SetPlainText("{{p.name}}: {{p.price | number:2}}")
+	doc.PAGEOBJECT[1].PageItemAttributes = &PageItemAttributes{ItemAttribute: []ItemAttribute{
+		{Name: RuleIf, Value: "discount"},
+		{Name: "note", Value: "kept"},
+	}}
+	doc.PAGEOBJECT[2].ANNAME = "photo [hide-if !photo]"
+	notes := &doc.PAGEOBJECT[3]
+	notes.ANNAME = "Notes [fill {{color}}] [layer {{lang}}] [unless status != sold]"
+
+	var products []map[string]interface{}
+	for i := 1; i <= 12; i++ {
+		products = append(products, map[string]interface{}{"name": fmt.Sprintf("Product %v", i), "price": i * 10})
+	}
+	data := map[string]interface{}{"products": products, "photo": "", "color": "#00ff00", "lang": "EN", "status": "sold"}
+	report, err := doc.FillTemplate(data, TemplateOptions{})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(report.Unresolved) != 0 || len(report.Unused) != 0 {
+		t.Errorf("FillTemplate() was incorrect, got: %+v", report)
+	}
+
+	// 12 rows, the image and the notes, which are copied onto the new page before the last row;
+	// the second item was deleted
+	if len(doc.PAGEOBJECT) != 16 {
+		t.Fatalf("len(doc.PAGEOBJECT) was incorrect, got: %v, want: %v.", len(doc.PAGEOBJECT), 16)
+	}
+	// Rows are 52.5 high with a gap of 10, the page ends at 20 + 792 - 40 = 772: 11 rows fit
+	first, last := doc.PAGEOBJECT[0], doc.PAGEOBJECT[13]
+	if first.ANNAME != "Row" || first.StoryText.PlainText() != "Product 1: 10.00" || doc.PAGEOBJECT[10].YPOS != "685" {
+		t.Errorf("first rows were incorrect, got: %v, %q, %v", first.ANNAME, first.StoryText.PlainText(), doc.PAGEOBJECT[10].YPOS)
+	}
+	if len(doc.PAGE) != 2 || doc.ANZPAGES != "2" || last.OwnPage != "1" || last.YPOS != "892" || last.StoryText.PlainText() != "Product 12: 120.00" || last.ItemID == first.ItemID {
+		t.Errorf("last row was incorrect, got: %v pages, %+v", len(doc.PAGE), last)
+	}
+
+	if copied := doc.PAGEOBJECT[11]; copied.ANNAME != "photo" || copied.OwnPage != "1" || copied.YPOS != "1013" || copied.PRINTABLE != "0" {
+		t.Errorf("copy of the image on the new page was incorrect, got: %+v", copied)
+	}
+	image, kept := doc.PAGEOBJECT[14], doc.PAGEOBJECT[15]
+	if image.ANNAME != "photo" || image.PRINTABLE != "0" {
+		t.Errorf("image was incorrect, got: %v, %v", image.ANNAME, image.PRINTABLE)
+	}
+	if kept.ANNAME != "Notes" || kept.PCOLOR != "#00ff00" || kept.LAYER != doc.GetLayerByName("EN").NUMMER {
+		t.Errorf("notes were incorrect, got: %v, %v, %v", kept.ANNAME, kept.PCOLOR, kept.LAYER)
+	}
+
+	// Items repeated for an empty list are deleted, layers that do not exist are errors
+	var empty DOCUMENT
+	empty.PAGEOBJECT = []PAGEOBJECT{{ANNAME: "[repeat items]"}, {ANNAME: "[layer DE]"}}
+	report, err = empty.FillTemplate(map[string]interface{}{"items": []string{}}, TemplateOptions{})
+	if err == nil || len(empty.PAGEOBJECT) != 1 || len(report.Unused) != 0 {
+		t.Errorf("FillTemplate() was incorrect, got: %+v, %v, %v", empty.PAGEOBJECT, report, err)
+	}
+}
+
+func TestTemplateRepeatsSharePages(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := &document.DOCUMENT
+	doc.PAGEOBJECT[0].ANNAME = "[repeat products]"
+	doc.PAGEOBJECT[3].ANNAME = "[repeat names]"
+	data := map[string]interface{}{"products": make([]int, 15), "names": make([]string, 8)}
+	if _, err := doc.FillTemplate(data, TemplateOptions{}); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	// 13 of the products and 7 of the names fit on the first page, the rest share one new
+	// page with one copy of the two other items
+	if len(doc.PAGE) != 2 || len(doc.PAGEOBJECT) != 27 {
+		t.Fatalf("FillTemplate() was incorrect, got: %v pages, %v items", len(doc.PAGE), len(doc.PAGEOBJECT))
+	}
+	ids := map[string]bool{}
+	onNewPage := 0
+	for _, po := range doc.PAGEOBJECT {
+		if ids[po.ItemID] {
+			t.Errorf("ItemID %v is not unique", po.ItemID)
+		}
+		ids[po.ItemID] = true
+		if po.OwnPage == "1" {
+			onNewPage++
+		}
+	}
+	if onNewPage != 5 {
+		t.Errorf("FillTemplate() put %v items on the new page, want 5", onNewPage)
+	}
+}
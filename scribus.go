@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"os"
 	"strconv"
 	"strings"
@@ -13,7 +14,7 @@ import (
 // Struct generated using an example Scribus document following https://github.com/miku/zek/issues/14
 // Then manually decided which elements can occur multiple times, e.g., changed PAGEOBJECT to []PAGEOBJECT
 // in the DOCUMENT struct because one DOCUMENT can have multiple PAGEOBJECTs
-// Same for COLOR
+// Same for COLOR, STYLE and CHARSTYLE
 // FIXME: Probably many others as well
 // FIXME: There must be a better, complete way to generate those structs from e.g., DTDs?
 
@@ -206,11 +207,13 @@ type DOCUMENT struct {
 	CalligraphicPenStyle          string         `xml:"calligraphicPenStyle,attr"`
 	CheckProfile                  []CheckProfile `xml:"CheckProfile"`
 	COLOR                         []COLOR        `xml:"COLOR"`
+	Gradient                      []Gradient     `xml:"Gradient"`
 	HYPHEN                        string         `xml:"HYPHEN"`
-	STYLE                         STYLE          `xml:"STYLE"`
-	CHARSTYLE                     CHARSTYLE      `xml:"CHARSTYLE"`
-	TableStyle                    TableStyle     `xml:"TableStyle"`
-	CellStyle                     CellStyle      `xml:"CellStyle"`
+	STYLE                         []STYLE        `xml:"STYLE"`
+	CHARSTYLE                     []CHARSTYLE    `xml:"CHARSTYLE"`
+	TableStyle                    []TableStyle   `xml:"TableStyle"`
+	CellStyle                     []CellStyle    `xml:"CellStyle"`
+	MultiLine                     []MultiLine    `xml:"MultiLine"`
 	LAYERS                        LAYERS         `xml:"LAYERS"`
 	Printer                       Printer        `xml:"Printer"`
 	PDF                           PDF            `xml:"PDF"`
@@ -220,8 +223,11 @@ type DOCUMENT struct {
 	NotesFrames                   string         `xml:"NotesFrames"`
 	PageSets                      PageSets       `xml:"PageSets"`
 	Sections                      Sections       `xml:"Sections"`
+	Pattern                       []Pattern      `xml:"Pattern"`
 	MASTERPAGE                    MASTERPAGE     `xml:"MASTERPAGE"`
 	PAGE                          []PAGE         `xml:"PAGE"`
+	FRAMEOBJECT                   []PAGEOBJECT   `xml:"FRAMEOBJECT"`
+	MASTEROBJECT                  []PAGEOBJECT   `xml:"MASTEROBJECT"`
 	PAGEOBJECT                    []PAGEOBJECT   `xml:"PAGEOBJECT"`
 }
 
@@ -314,10 +320,32 @@ type COLOR struct {
 	R        string   `xml:"R,attr"`
 	G        string   `xml:"G,attr"`
 	B        string   `xml:"B,attr"`
+	L        string   `xml:"L,attr,omitempty"`
+	A        string   `xml:"A,attr,omitempty"`
+	RGB      string   `xml:"RGB,attr,omitempty"`
+	Spot     string   `xml:"Spot,attr,omitempty"`
 	Register string   `xml:"Register,attr"`
 }
 
-// Default paragraph styles
+// Named colour gradients
+type Gradient struct {
+	XMLName xml.Name `xml:"Gradient"`
+	Text    string   `xml:",chardata"`
+	Name    string   `xml:"Name,attr"`
+	CSTOP   []CSTOP  `xml:"CSTOP"`
+}
+
+// Colour stop of a gradient, used in Gradient and in the fill (CSTOP) and
+// stroke (S_CSTOP) gradients of a PAGEOBJECT
+type CSTOP struct {
+	Text  string `xml:",chardata"`
+	RAMP  string `xml:"RAMP,attr"`
+	NAME  string `xml:"NAME,attr"`
+	SHADE string `xml:"SHADE,attr"`
+	TRANS string `xml:"TRANS,attr"`
+}
+
+// Paragraph styles
 // FIXME: Probably not complete
 type STYLE struct {
 	XMLName                xml.Name `xml:"STYLE"`
@@ -344,6 +372,14 @@ type STYLE struct {
 	PARENT                 string   `xml:"PARENT,attr"`
 	FONT                   string   `xml:"FONT,attr"`
 	FCOLOR                 string   `xml:"FCOLOR,attr"`
+	Tabs                   []Tabs   `xml:"Tabs"`
+}
+
+// A tab stop of a paragraph style or paragraph (see tabs.go)
+type Tabs struct {
+	Type string `xml:"Type,attr"`
+	Pos  string `xml:"Pos,attr"`
+	Fill string `xml:"Fill,attr"`
 }
 
 type CHARSTYLE struct {
@@ -376,23 +412,27 @@ type CHARSTYLE struct {
 	LANGUAGE      string   `xml:"LANGUAGE,attr"`
 }
 
+// TableStyle is a named table style (see tablestyles.go). A style inherits the attributes
+// and borders it does not set from its PARENT
 type TableStyle struct {
-	XMLName           xml.Name          `xml:"TableStyle"`
-	Text              string            `xml:",chardata"`
-	NAME              string            `xml:"NAME,attr"`
-	DefaultStyle      string            `xml:"DefaultStyle,attr"`
-	FillColor         string            `xml:"FillColor,attr"`
-	FillShade         string            `xml:"FillShade,attr"`
-	TableBorderLeft   TableBorderLeft   `xml:"TableBorderLeft"`
-	TableBorderRight  TableBorderRight  `xml:"TableBorderRight"`
-	TableBorderTop    TableBorderTop    `xml:"TableBorderTop"`
-	TableBorderBottom TableBorderBottom `xml:"TableBorderBottom"`
+	XMLName           xml.Name     `xml:"TableStyle"`
+	Text              string       `xml:",chardata"`
+	NAME              string       `xml:"NAME,attr"`
+	PARENT            string       `xml:"PARENT,attr,omitempty"`
+	DefaultStyle      string       `xml:"DefaultStyle,attr"`
+	FillColor         string       `xml:"FillColor,attr,omitempty"`
+	FillShade         string       `xml:"FillShade,attr,omitempty"`
+	TableBorderLeft   *TableBorder `xml:"TableBorderLeft"`
+	TableBorderRight  *TableBorder `xml:"TableBorderRight"`
+	TableBorderTop    *TableBorder `xml:"TableBorderTop"`
+	TableBorderBottom *TableBorder `xml:"TableBorderBottom"`
 }
 
-type TableBorderLeft struct {
-	XMLName         xml.Name        `xml:"TableBorderLeft"`
-	Text            string          `xml:",chardata"`
-	TableBorderLine TableBorderLine `xml:"TableBorderLine"`
+// TableBorder is a border of a table or cell, which is drawn with one or more lines. The name
+// of the element is that of the side, e.g., TableBorderLeft
+type TableBorder struct {
+	Text            string            `xml:",chardata"`
+	TableBorderLine []TableBorderLine `xml:"TableBorderLine"`
 }
 
 type TableBorderLine struct {
@@ -404,42 +444,66 @@ type TableBorderLine struct {
 	Shade    string   `xml:"Shade,attr"`
 }
 
-type TableBorderRight struct {
-	XMLName         xml.Name        `xml:"TableBorderRight"`
-	Text            string          `xml:",chardata"`
-	TableBorderLine TableBorderLine `xml:"TableBorderLine"`
+// CellStyle is a named cell style (see TableStyle)
+type CellStyle struct {
+	XMLName           xml.Name     `xml:"CellStyle"`
+	Text              string       `xml:",chardata"`
+	NAME              string       `xml:"NAME,attr"`
+	PARENT            string       `xml:"PARENT,attr,omitempty"`
+	DefaultStyle      string       `xml:"DefaultStyle,attr"`
+	FillColor         string       `xml:"FillColor,attr,omitempty"`
+	FillShade         string       `xml:"FillShade,attr,omitempty"`
+	LeftPadding       string       `xml:"LeftPadding,attr,omitempty"`
+	RightPadding      string       `xml:"RightPadding,attr,omitempty"`
+	TopPadding        string       `xml:"TopPadding,attr,omitempty"`
+	BottomPadding     string       `xml:"BottomPadding,attr,omitempty"`
+	TableBorderLeft   *TableBorder `xml:"TableBorderLeft"`
+	TableBorderRight  *TableBorder `xml:"TableBorderRight"`
+	TableBorderTop    *TableBorder `xml:"TableBorderTop"`
+	TableBorderBottom *TableBorder `xml:"TableBorderBottom"`
 }
 
-type TableBorderTop struct {
-	XMLName         xml.Name        `xml:"TableBorderTop"`
-	Text            string          `xml:",chardata"`
-	TableBorderLine TableBorderLine `xml:"TableBorderLine"`
+// MultiLine is a line style: lines of different widths, dashes and colours drawn on top of
+// each other. Items use it by its Name in NAMEDLST
+type MultiLine struct {
+	XMLName xml.Name  `xml:"MultiLine"`
+	Text    string    `xml:",chardata"`
+	Name    string    `xml:"Name,attr"`
+	SubLine []SubLine `xml:"SubLine"`
 }
 
-type TableBorderBottom struct {
-	XMLName         xml.Name        `xml:"TableBorderBottom"`
-	Text            string          `xml:",chardata"`
-	TableBorderLine TableBorderLine `xml:"TableBorderLine"`
+// SubLine is one of the lines of a line style
+type SubLine struct {
+	Dash     string `xml:"Dash,attr"`
+	LineEnd  string `xml:"LineEnd,attr"`
+	LineJoin string `xml:"LineJoin,attr"`
+	Shade    string `xml:"Shade,attr"`
+	Width    string `xml:"Width,attr"`
+	Color    string `xml:"Color,attr"`
 }
 
-type CellStyle struct {
-	XMLName           xml.Name          `xml:"CellStyle"`
-	Text              string            `xml:",chardata"`
-	NAME              string            `xml:"NAME,attr"`
-	DefaultStyle      string            `xml:"DefaultStyle,attr"`
-	FillColor         string            `xml:"FillColor,attr"`
-	FillShade         string            `xml:"FillShade,attr"`
-	LeftPadding       string            `xml:"LeftPadding,attr"`
-	RightPadding      string            `xml:"RightPadding,attr"`
-	TopPadding        string            `xml:"TopPadding,attr"`
-	BottomPadding     string            `xml:"BottomPadding,attr"`
-	TableBorderLeft   TableBorderLeft   `xml:"TableBorderLeft"`
-	TableBorderRight  TableBorderRight  `xml:"TableBorderRight"`
-	TableBorderTop    TableBorderTop    `xml:"TableBorderTop"`
-	TableBorderBottom TableBorderBottom `xml:"TableBorderBottom"`
+// Pattern is a fill made of items (PatternItem) that is repeated. Items use it by its Name in
+// pattern (fill) and patternS (line)
+type Pattern struct {
+	XMLName     xml.Name     `xml:"Pattern"`
+	Text        string       `xml:",chardata"`
+	Name        string       `xml:"Name,attr"`
+	ScaleX      string       `xml:"scaleX,attr"`
+	ScaleY      string       `xml:"scaleY,attr"`
+	Width       string       `xml:"width,attr"`
+	Height      string       `xml:"height,attr"`
+	XOffset     string       `xml:"xoffset,attr"`
+	YOffset     string       `xml:"yoffset,attr"`
+	PatternItem []PAGEOBJECT `xml:"PatternItem"`
 }
 
-type LAYERS []struct {
+// LAYERS are the layers of the document. The attribute names are German:
+// NUMMER is the number PAGEOBJECT.LAYER refers to, SICHTBAR means visible
+// and DRUCKEN means printable
+type LAYERS []Layer
+
+// Layer is one layer of the document, each is written as a LAYERS element
+type Layer struct {
 	XMLName  xml.Name `xml:"LAYERS"`
 	Text     string   `xml:",chardata"`
 	NUMMER   string   `xml:"NUMMER,attr"`
@@ -721,100 +785,189 @@ type PAGE struct {
 	Di                    string   `xml:"Di,attr"`
 }
 
+// PAGEOBJECT is an item on a page, an item on a master page (MASTEROBJECT) or an inline
+// frame (FRAMEOBJECT, with InID). It is written with the element name of the slice it is
+// in, so items can be moved between pages, master pages, groups and patterns; XMLName is
+// not read or written
 type PAGEOBJECT struct {
-	XMLName           xml.Name     `xml:"PAGEOBJECT"`
-	Text              string       `xml:",chardata"`
-	XPOS              string       `xml:"XPOS,attr"`
-	YPOS              string       `xml:"YPOS,attr"`
-	OwnPage           string       `xml:"OwnPage,attr"`
-	ItemID            string       `xml:"ItemID,attr"`
-	PTYPE             string       `xml:"PTYPE,attr"`
-	WIDTH             string       `xml:"WIDTH,attr"`
-	HEIGHT            string       `xml:"HEIGHT,attr"`
-	FRTYPE            string       `xml:"FRTYPE,attr"`
-	CLIPEDIT          string       `xml:"CLIPEDIT,attr"`
-	ROT               string       `xml:"ROT,attr"`
-	PWIDTH            string       `xml:"PWIDTH,attr"`
-	PCOLOR            string       `xml:"PCOLOR,attr"`
-	PLINEART          string       `xml:"PLINEART,attr"`
-	LOCALSCX          string       `xml:"LOCALSCX,attr"`
-	LOCALSCY          string       `xml:"LOCALSCY,attr"`
-	LOCALX            string       `xml:"LOCALX,attr"`
-	LOCALY            string       `xml:"LOCALY,attr"`
-	LOCALROT          string       `xml:"LOCALROT,attr"`
-	PICART            string       `xml:"PICART,attr"`
-	SCALETYPE         string       `xml:"SCALETYPE,attr"`
-	RATIO             string       `xml:"RATIO,attr"`
-	TransValue        string       `xml:"TransValue,attr"`
-	Path              string       `xml:"path,attr"`
-	Copath            string       `xml:"copath,attr"`
-	GXpos             string       `xml:"gXpos,attr"`
-	GYpos             string       `xml:"gYpos,attr"`
-	GWidth            string       `xml:"gWidth,attr"`
-	GHeight           string       `xml:"gHeight,attr"`
-	LAYER             string       `xml:"LAYER,attr"`
-	NEXTITEM          string       `xml:"NEXTITEM,attr"`
-	BACKITEM          string       `xml:"BACKITEM,attr"`
-	Pagenumber        string       `xml:"Pagenumber,attr"`
-	PFILE             string       `xml:"PFILE,attr"`
-	IRENDER           string       `xml:"IRENDER,attr"`
-	EMBEDDED          string       `xml:"EMBEDDED,attr"`
-	COMPRESSIONMETHOD string       `xml:"COMPRESSIONMETHOD,attr"`
-	GRExtM            string       `xml:"GRExtM,attr"`
-	GRTYPM            string       `xml:"GRTYPM,attr"`
-	GRSTARTXM         string       `xml:"GRSTARTXM,attr"`
-	GRSTARTYM         string       `xml:"GRSTARTYM,attr"`
-	GRENDXM           string       `xml:"GRENDXM,attr"`
-	GRENDYM           string       `xml:"GRENDYM,attr"`
-	GRFOCALXM         string       `xml:"GRFOCALXM,attr"`
-	GRFOCALYM         string       `xml:"GRFOCALYM,attr"`
-	GRSCALEM          string       `xml:"GRSCALEM,attr"`
-	GRSKEWM           string       `xml:"GRSKEWM,attr"`
-	ImageRes          string       `xml:"ImageRes,attr"`
-	FillRule          string       `xml:"fillRule,attr"`
-	ANNAME            string       `xml:"ANNAME,attr"`
-	GroupWidth        string       `xml:"groupWidth,attr"`
-	GroupHeight       string       `xml:"groupHeight,attr"`
-	GroupClips        string       `xml:"groupClips,attr"`
-	PLINEEND          string       `xml:"PLINEEND,attr"`
-	PLINEJOIN         string       `xml:"PLINEJOIN,attr"`
-	RADRECT           string       `xml:"RADRECT,attr"`
-	PCOLOR2           string       `xml:"PCOLOR2,attr"`
-	PRFILE            string       `xml:"PRFILE,attr"`
-	COLUMNS           string       `xml:"COLUMNS,attr"`
-	COLGAP            string       `xml:"COLGAP,attr"`
-	AUTOTEXT          string       `xml:"AUTOTEXT,attr"`
-	EXTRA             string       `xml:"EXTRA,attr"`
-	TEXTRA            string       `xml:"TEXTRA,attr"`
-	BEXTRA            string       `xml:"BEXTRA,attr"`
-	REXTRA            string       `xml:"REXTRA,attr"`
-	VAlign            string       `xml:"VAlign,attr"`
-	FLOP              string       `xml:"FLOP,attr"`
-	PLTSHOW           string       `xml:"PLTSHOW,attr"`
-	BASEOF            string       `xml:"BASEOF,attr"`
-	TextPathType      string       `xml:"textPathType,attr"`
-	TextPathFlipped   string       `xml:"textPathFlipped,attr"`
-	PSTYLE            string       `xml:"PSTYLE,attr"`
+	XMLName            xml.Name            `xml:"-"`
+	Text               string              `xml:",chardata"`
+	XPOS               string              `xml:"XPOS,attr"`
+	YPOS               string              `xml:"YPOS,attr"`
+	OwnPage            string              `xml:"OwnPage,attr"`
+	ItemID             string              `xml:"ItemID,attr"`
+	PTYPE              string              `xml:"PTYPE,attr"`
+	WIDTH              string              `xml:"WIDTH,attr"`
+	HEIGHT             string              `xml:"HEIGHT,attr"`
+	FRTYPE             string              `xml:"FRTYPE,attr"`
+	CLIPEDIT           string              `xml:"CLIPEDIT,attr"`
+	ROT                string              `xml:"ROT,attr"`
+	PWIDTH             string              `xml:"PWIDTH,attr"`
+	PCOLOR             string              `xml:"PCOLOR,attr"`
+	PLINEART           string              `xml:"PLINEART,attr"`
+	LOCALSCX           string              `xml:"LOCALSCX,attr"`
+	LOCALSCY           string              `xml:"LOCALSCY,attr"`
+	LOCALX             string              `xml:"LOCALX,attr"`
+	LOCALY             string              `xml:"LOCALY,attr"`
+	LOCALROT           string              `xml:"LOCALROT,attr"`
+	PICART             string              `xml:"PICART,attr"`
+	SCALETYPE          string              `xml:"SCALETYPE,attr"`
+	RATIO              string              `xml:"RATIO,attr"`
+	TransValue         string              `xml:"TransValue,attr"`
+	Path               string              `xml:"path,attr"`
+	Copath             string              `xml:"copath,attr"`
+	GXpos              string              `xml:"gXpos,attr"`
+	GYpos              string              `xml:"gYpos,attr"`
+	GWidth             string              `xml:"gWidth,attr"`
+	GHeight            string              `xml:"gHeight,attr"`
+	LAYER              string              `xml:"LAYER,attr"`
+	NEXTITEM           string              `xml:"NEXTITEM,attr"`
+	BACKITEM           string              `xml:"BACKITEM,attr"`
+	Pagenumber         string              `xml:"Pagenumber,attr"`
+	PFILE              string              `xml:"PFILE,attr"`
+	IRENDER            string              `xml:"IRENDER,attr"`
+	EMBEDDED           string              `xml:"EMBEDDED,attr"`
+	COMPRESSIONMETHOD  string              `xml:"COMPRESSIONMETHOD,attr"`
+	GRExtM             string              `xml:"GRExtM,attr"`
+	GRTYPM             string              `xml:"GRTYPM,attr"`
+	GRSTARTXM          string              `xml:"GRSTARTXM,attr"`
+	GRSTARTYM          string              `xml:"GRSTARTYM,attr"`
+	GRENDXM            string              `xml:"GRENDXM,attr"`
+	GRENDYM            string              `xml:"GRENDYM,attr"`
+	GRFOCALXM          string              `xml:"GRFOCALXM,attr"`
+	GRFOCALYM          string              `xml:"GRFOCALYM,attr"`
+	GRSCALEM           string              `xml:"GRSCALEM,attr"`
+	GRSKEWM            string              `xml:"GRSKEWM,attr"`
+	ImageRes           string              `xml:"ImageRes,attr"`
+	FillRule           string              `xml:"fillRule,attr"`
+	ANNAME             string              `xml:"ANNAME,attr"`
+	GroupWidth         string              `xml:"groupWidth,attr"`
+	GroupHeight        string              `xml:"groupHeight,attr"`
+	GroupClips         string              `xml:"groupClips,attr"`
+	PLINEEND           string              `xml:"PLINEEND,attr"`
+	PLINEJOIN          string              `xml:"PLINEJOIN,attr"`
+	RADRECT            string              `xml:"RADRECT,attr"`
+	PCOLOR2            string              `xml:"PCOLOR2,attr"`
+	PRFILE             string              `xml:"PRFILE,attr"`
+	COLUMNS            string              `xml:"COLUMNS,attr"`
+	COLGAP             string              `xml:"COLGAP,attr"`
+	AUTOTEXT           string              `xml:"AUTOTEXT,attr"`
+	EXTRA              string              `xml:"EXTRA,attr"`
+	TEXTRA             string              `xml:"TEXTRA,attr"`
+	BEXTRA             string              `xml:"BEXTRA,attr"`
+	REXTRA             string              `xml:"REXTRA,attr"`
+	VAlign             string              `xml:"VAlign,attr"`
+	FLOP               string              `xml:"FLOP,attr"`
+	PLTSHOW            string              `xml:"PLTSHOW,attr"`
+	BASEOF             string              `xml:"BASEOF,attr"`
+	TextPathType       string              `xml:"textPathType,attr"`
+	TextPathFlipped    string              `xml:"textPathFlipped,attr"`
+	PSTYLE             string              `xml:"PSTYLE,attr"`
+	PRINTABLE          string              `xml:"PRINTABLE,attr,omitempty"`
+	FLIPPEDH           string              `xml:"FLIPPEDH,attr,omitempty"`
+	FLIPPEDV           string              `xml:"FLIPPEDV,attr,omitempty"`
+	GRNAME             string              `xml:"GRNAME,attr,omitempty"`
+	NAMEDLST           string              `xml:"NAMEDLST,attr,omitempty"`
+	Pattern            string              `xml:"pattern,attr,omitempty"`
+	PatternStroke      string              `xml:"patternS,attr,omitempty"`
+	InID               string              `xml:"InID,attr,omitempty"`
+	ANNOTATION         string              `xml:"ANNOTATION,attr,omitempty"`
+	ANTYPE             string              `xml:"ANTYPE,attr,omitempty"`
+	ANACTYP            string              `xml:"ANACTYP,attr,omitempty"`
+	ANEXTERN           string              `xml:"ANEXTERN,attr,omitempty"`
+	CSTOP              []CSTOP             `xml:"CSTOP"`
+	SCSTOP             []CSTOP             `xml:"S_CSTOP"`
+	PageItemAttributes *PageItemAttributes `xml:"PageItemAttributes"`
+	TableData          *TableData          `xml:"TableData"`
+	StoryText          StoryText           `xml:"StoryText"`
+	PAGEOBJECT         []PAGEOBJECT        `xml:"PAGEOBJECT"`
+}
+
+// TableData holds the rows, columns and cells of a table frame (PTYPE 16, see tables.go).
+// RowHeights and ColumnWidths are lists of numbers separated by spaces, CellAreas lists the
+// merged cells as groups of row, column, number of rows and number of columns
+type TableData struct {
+	Rows              string       `xml:"Rows,attr"`
+	Columns           string       `xml:"Columns,attr"`
+	RowHeights        string       `xml:"RowHeights,attr"`
+	ColumnWidths      string       `xml:"ColumnWidths,attr"`
+	CellAreas         string       `xml:"CellAreas,attr"`
+	Style             string       `xml:"Style,attr,omitempty"`
+	FillColor         string       `xml:"FillColor,attr,omitempty"`
+	FillShade         string       `xml:"FillShade,attr,omitempty"`
+	TableBorderLeft   *TableBorder `xml:"TableBorderLeft"`
+	TableBorderRight  *TableBorder `xml:"TableBorderRight"`
+	TableBorderTop    *TableBorder `xml:"TableBorderTop"`
+	TableBorderBottom *TableBorder `xml:"TableBorderBottom"`
+	Cell              []Cell       `xml:"Cell"`
+}
+
+// Cell is a cell of a table with its own story text
+type Cell struct {
+	Row               string       `xml:"Row,attr"`
+	Column            string       `xml:"Column,attr"`
+	Style             string       `xml:"Style,attr,omitempty"`
+	FillColor         string       `xml:"FillColor,attr,omitempty"`
+	FillShade         string       `xml:"FillShade,attr,omitempty"`
+	LeftPadding       string       `xml:"LeftPadding,attr,omitempty"`
+	RightPadding      string       `xml:"RightPadding,attr,omitempty"`
+	TopPadding        string       `xml:"TopPadding,attr,omitempty"`
+	BottomPadding     string       `xml:"BottomPadding,attr,omitempty"`
+	TableBorderLeft   *TableBorder `xml:"TableBorderLeft"`
+	TableBorderRight  *TableBorder `xml:"TableBorderRight"`
+	TableBorderTop    *TableBorder `xml:"TableBorderTop"`
+	TableBorderBottom *TableBorder `xml:"TableBorderBottom"`
 	StoryText         StoryText    `xml:"StoryText"`
-	PAGEOBJECT        []PAGEOBJECT `xml:"PAGEOBJECT"`
+}
+
+// PageItemAttributes holds the attributes that were given to an item (Item > Attributes in Scribus)
+type PageItemAttributes struct {
+	ItemAttribute []ItemAttribute `xml:"ItemAttribute"`
+}
+
+type ItemAttribute struct {
+	Name           string `xml:"Name,attr"`
+	Type           string `xml:"Type,attr"`
+	Value          string `xml:"Value,attr"`
+	Parameter      string `xml:"Parameter,attr"`
+	Relationship   string `xml:"Relationship,attr"`
+	RelationshipTo string `xml:"RelationshipTo,attr"`
+	AutoAddTo      string `xml:"AutoAddTo,attr"`
 }
 
 type Para struct {
 	XMLName                  xml.Name `xml:"para"`
 	Text                     string   `xml:",chardata"`
-	LINESP                   string   `xml:"LINESP,attr"`
-	ParagraphEffectCharStyle string   `xml:"ParagraphEffectCharStyle,attr"`
-	ParagraphEffectOffset    string   `xml:"ParagraphEffectOffset,attr"`
-	ParagraphEffectIndent    string   `xml:"ParagraphEffectIndent,attr"`
-	DROP                     string   `xml:"DROP,attr"`
-	Bullet                   string   `xml:"Bullet,attr"`
-	BulletStr                string   `xml:"BulletStr,attr"`
-	Numeration               string   `xml:"Numeration,attr"`
+	LINESP                   string   `xml:"LINESP,attr,omitempty"`
+	ParagraphEffectCharStyle string   `xml:"ParagraphEffectCharStyle,attr,omitempty"`
+	ParagraphEffectOffset    string   `xml:"ParagraphEffectOffset,attr,omitempty"`
+	ParagraphEffectIndent    string   `xml:"ParagraphEffectIndent,attr,omitempty"`
+	DROP                     string   `xml:"DROP,attr,omitempty"`
+	Bullet                   string   `xml:"Bullet,attr,omitempty"`
+	BulletStr                string   `xml:"BulletStr,attr,omitempty"`
+	Numeration               string   `xml:"Numeration,attr,omitempty"`
+	PARENT                   string   `xml:"PARENT,attr,omitempty"`
+	ALIGN                    string   `xml:"ALIGN,attr,omitempty"`
+	LINESPMode               string   `xml:"LINESPMode,attr,omitempty"`
+	INDENT                   string   `xml:"INDENT,attr,omitempty"`
+	RMARGIN                  string   `xml:"RMARGIN,attr,omitempty"`
+	FIRST                    string   `xml:"FIRST,attr,omitempty"`
+	VOR                      string   `xml:"VOR,attr,omitempty"`
+	NACH                     string   `xml:"NACH,attr,omitempty"`
+	OpticalMargins           string   `xml:"OpticalMargins,attr,omitempty"`
+	NumerationName           string   `xml:"NumerationName,attr,omitempty"`
+	NumerationFormat         string   `xml:"NumerationFormat,attr,omitempty"`
+	NumerationLevel          string   `xml:"NumerationLevel,attr,omitempty"`
+	NumerationStart          string   `xml:"NumerationStart,attr,omitempty"`
+	NumerationRestart        string   `xml:"NumerationRestart,attr,omitempty"`
+	NumerationPrefix         string   `xml:"NumerationPrefix,attr,omitempty"`
+	NumerationSuffix         string   `xml:"NumerationSuffix,attr,omitempty"`
+	Tabs                     []Tabs   `xml:"Tabs"`
 }
 
-// FIXME: The order of 'ITEXT' and 'Para' in the XML document must not be changed;
-// currently we are getting all 'ITEXT's first and then all 'Para's when we write out
-// the changed XML, probably due to them being declared as []? How to fix this?
+// The order of 'ITEXT', 'para' and the special characters such as 'tab' in the XML document
+// must not be changed. encoding/xml would write all 'ITEXT's first and then all 'para's,
+// so StoryText has its own UnmarshalXML and MarshalXML that remember the order (see storytext.go)
 type StoryText struct {
 	XMLName       xml.Name        `xml:"StoryText"`
 	Text          string          `xml:",chardata"`
@@ -823,9 +976,12 @@ type StoryText struct {
 	Para          []Para          `xml:"para"`
 	Trail         Trail           `xml:"trail"`
 	StoryTextSpan []StoryTextSpan `xml:"StoryTextSpan"`
+	Elements      []StoryElement  `xml:"-"` // Special characters such as 'tab' and 'breakline'
+	order         []storyRef
 }
 
-// FIXME: Working around the above by introducing a phantasy tag 'StoryTextSpan' to group the elements inside
+// FIXME: Before StoryText kept the order of its elements, this was worked around
+// by introducing a phantasy tag 'StoryTextSpan' to group the elements inside
 // Luckily Scribus can still open the file
 // https://gitlab.com/scribus/scribus/issues/8
 // TODO: If we want to parse files that contain this tag ourselves again, we also need to handle this case...
@@ -840,39 +996,74 @@ type StoryTextSpan struct {
 type DefaultStyle struct {
 	XMLName        xml.Name `xml:"DefaultStyle"`
 	Text           string   `xml:",chardata"`
-	LINESP         string   `xml:"LINESP,attr"`
-	LINESPMode     string   `xml:"LINESPMode,attr"`
-	FCOLOR         string   `xml:"FCOLOR,attr"`
-	FONT           string   `xml:"FONT,attr"`
-	FONTSIZE       string   `xml:"FONTSIZE,attr"`
-	PARENT         string   `xml:"PARENT,attr"`
-	CPARENT        string   `xml:"CPARENT,attr"`
-	ALIGN          string   `xml:"ALIGN,attr"`
-	OpticalMargins string   `xml:"OpticalMargins,attr"`
+	LINESP         string   `xml:"LINESP,attr,omitempty"`
+	LINESPMode     string   `xml:"LINESPMode,attr,omitempty"`
+	FCOLOR         string   `xml:"FCOLOR,attr,omitempty"`
+	FONT           string   `xml:"FONT,attr,omitempty"`
+	FONTSIZE       string   `xml:"FONTSIZE,attr,omitempty"`
+	PARENT         string   `xml:"PARENT,attr,omitempty"`
+	CPARENT        string   `xml:"CPARENT,attr,omitempty"`
+	ALIGN          string   `xml:"ALIGN,attr,omitempty"`
+	OpticalMargins string   `xml:"OpticalMargins,attr,omitempty"`
 }
 
+// A run of text with the same character attributes. Attributes that are empty
+// are inherited from the character style CPARENT and the paragraph style
 type ITEXT struct {
-	XMLName  xml.Name `xml:"ITEXT"`
-	Text     string   `xml:",chardata"`
-	FONT     string   `xml:"FONT,attr"`
-	FONTSIZE string   `xml:"FONTSIZE,attr"`
-	FCOLOR   string   `xml:"FCOLOR,attr"`
-	CPARENT  string   `xml:"CPARENT,attr"`
-	CH       string   `xml:"CH,attr"`
+	XMLName      xml.Name `xml:"ITEXT"`
+	Text         string   `xml:",chardata"`
+	FONT         string   `xml:"FONT,attr,omitempty"`
+	FONTSIZE     string   `xml:"FONTSIZE,attr,omitempty"`
+	FCOLOR       string   `xml:"FCOLOR,attr,omitempty"`
+	CPARENT      string   `xml:"CPARENT,attr,omitempty"`
+	FSHADE       string   `xml:"FSHADE,attr,omitempty"`
+	SCOLOR       string   `xml:"SCOLOR,attr,omitempty"`
+	SSHADE       string   `xml:"SSHADE,attr,omitempty"`
+	BGCOLOR      string   `xml:"BGCOLOR,attr,omitempty"`
+	BGSHADE      string   `xml:"BGSHADE,attr,omitempty"`
+	FEATURES     string   `xml:"FEATURES,attr,omitempty"`
+	FONTFEATURES string   `xml:"FONTFEATURES,attr,omitempty"`
+	KERN         string   `xml:"KERN,attr,omitempty"`
+	SCALEH       string   `xml:"SCALEH,attr,omitempty"`
+	SCALEV       string   `xml:"SCALEV,attr,omitempty"`
+	BASEO        string   `xml:"BASEO,attr,omitempty"`
+	LANGUAGE     string   `xml:"LANGUAGE,attr,omitempty"`
+	TXTSHX       string   `xml:"TXTSHX,attr,omitempty"`
+	TXTSHY       string   `xml:"TXTSHY,attr,omitempty"`
+	TXTOUT       string   `xml:"TXTOUT,attr,omitempty"`
+	TXTULP       string   `xml:"TXTULP,attr,omitempty"`
+	TXTULW       string   `xml:"TXTULW,attr,omitempty"`
+	TXTSTP       string   `xml:"TXTSTP,attr,omitempty"`
+	TXTSTW       string   `xml:"TXTSTW,attr,omitempty"`
+	CH           string   `xml:"CH,attr"`
 }
 
 type Trail struct {
 	Text                  string `xml:",chardata"`
-	LINESP                string `xml:"LINESP,attr"`
-	LINESPMode            string `xml:"LINESPMode,attr"`
-	ParagraphEffectOffset string `xml:"ParagraphEffectOffset,attr"`
-	ParagraphEffectIndent string `xml:"ParagraphEffectIndent,attr"`
-	DROP                  string `xml:"DROP,attr"`
-	Bullet                string `xml:"Bullet,attr"`
-	BulletStr             string `xml:"BulletStr,attr"`
-	Numeration            string `xml:"Numeration,attr"`
-	ALIGN                 string `xml:"ALIGN,attr"`
-	OpticalMargins        string `xml:"OpticalMargins,attr"`
+	LINESP                string `xml:"LINESP,attr,omitempty"`
+	LINESPMode            string `xml:"LINESPMode,attr,omitempty"`
+	ParagraphEffectOffset string `xml:"ParagraphEffectOffset,attr,omitempty"`
+	ParagraphEffectIndent string `xml:"ParagraphEffectIndent,attr,omitempty"`
+	DROP                  string `xml:"DROP,attr,omitempty"`
+	Bullet                string `xml:"Bullet,attr,omitempty"`
+	BulletStr             string `xml:"BulletStr,attr,omitempty"`
+	Numeration            string `xml:"Numeration,attr,omitempty"`
+	ALIGN                 string `xml:"ALIGN,attr,omitempty"`
+	OpticalMargins        string `xml:"OpticalMargins,attr,omitempty"`
+	PARENT                string `xml:"PARENT,attr,omitempty"`
+	INDENT                string `xml:"INDENT,attr,omitempty"`
+	RMARGIN               string `xml:"RMARGIN,attr,omitempty"`
+	FIRST                 string `xml:"FIRST,attr,omitempty"`
+	VOR                   string `xml:"VOR,attr,omitempty"`
+	NACH                  string `xml:"NACH,attr,omitempty"`
+	NumerationName        string `xml:"NumerationName,attr,omitempty"`
+	NumerationFormat      string `xml:"NumerationFormat,attr,omitempty"`
+	NumerationLevel       string `xml:"NumerationLevel,attr,omitempty"`
+	NumerationStart       string `xml:"NumerationStart,attr,omitempty"`
+	NumerationRestart     string `xml:"NumerationRestart,attr,omitempty"`
+	NumerationPrefix      string `xml:"NumerationPrefix,attr,omitempty"`
+	NumerationSuffix      string `xml:"NumerationSuffix,attr,omitempty"`
+	Tabs                  []Tabs `xml:"Tabs"`
 }
 
 // readScribusFile reads an existing Scribus file from path and
@@ -905,22 +1096,46 @@ func (scribusDocument Document) WriteScribusFile(path string) error {
 	}
 }
 
+// Clone returns a deep copy of the Document, error
+func (scribusDocument Document) Clone() (Document, error) {
+	var clone Document
+	xmlstring, err := xml.Marshal(scribusDocument)
+	if err != nil {
+		return clone, err
+	}
+	err = xml.Unmarshal(xmlstring, &clone)
+	return clone, err
+}
+
+// Clone returns a deep copy of the PAGEOBJECT, error
+func (po PAGEOBJECT) Clone() (PAGEOBJECT, error) {
+	var clone PAGEOBJECT
+	xmlstring, err := xml.Marshal(po)
+	if err != nil {
+		return clone, err
+	}
+	err = xml.Unmarshal(xmlstring, &clone)
+	return clone, err
+}
+
 // ChangeText changes the text of an ITEXT
 func (itext *ITEXT) ChangeText(text string) {
 	itext.CH = text
 }
 
-// GetPageObjectsWithText returns pointers to the PAGEOBJECTs with the text in question
+// GetPageObjectsWithText returns pointers to the PAGEOBJECTs (including the items inside
+// groups) with an ITEXT whose text is the text in question. To find text that spans
+// several ITEXTs or paragraphs, use Find
 func (doc DOCUMENT) GetPageObjectsWithText(text string) []*PAGEOBJECT {
 	var pos []*PAGEOBJECT
-	for i := range doc.PAGEOBJECT {
-		for j := range doc.PAGEOBJECT[i].StoryText.ITEXT {
-			if doc.PAGEOBJECT[i].StoryText.ITEXT[j].CH == text {
-				pos = append(pos, &doc.PAGEOBJECT[i])
-				return pos
+	walkPageObjects(doc.PAGEOBJECT, func(po *PAGEOBJECT) {
+		for j := range po.StoryText.ITEXT {
+			if po.StoryText.ITEXT[j].CH == text {
+				pos = append(pos, po)
+				return
 			}
 		}
-	}
+	})
 	return pos
 }
 
@@ -948,15 +1163,62 @@ func (doc DOCUMENT) GetPageObjectsThatAreType2() []*PAGEOBJECT {
 	return pos
 }
 
-// MovePageObject moves the i'th PAGEOBJECT to the supplied x and y position
-func (po *PAGEOBJECT) MovePageObject(i int, xpos int, ypos int) {
-	po.XPOS = strconv.Itoa(xpos)
-	po.YPOS = strconv.Itoa(ypos)
+// walkPageObjects calls fn for every PAGEOBJECT in objs, including the items inside groups
+func walkPageObjects(objs []PAGEOBJECT, fn func(po *PAGEOBJECT)) {
+	for i := range objs {
+		fn(&objs[i])
+		walkPageObjects(objs[i].PAGEOBJECT, fn)
+	}
+}
+
+// walkAllPageObjects calls fn for every MASTEROBJECT, PAGEOBJECT and FRAMEOBJECT, including
+// the items inside groups
+func (doc DOCUMENT) walkAllPageObjects(fn func(po *PAGEOBJECT)) {
+	walkPageObjects(doc.MASTEROBJECT, fn)
+	walkPageObjects(doc.PAGEOBJECT, fn)
+	walkPageObjects(doc.FRAMEOBJECT, fn)
+}
+
+// newPageObject returns an item of the type ptype (e.g., "4" for text frames) with the size
+// width x height and the attributes Scribus gives new items
+func newPageObject(ptype string, width float64, height float64) PAGEOBJECT {
+	w, h := formatFloat(width), formatFloat(height)
+	path := "M0 0 L" + w + " 0 L" + w + " " + h + " L0 " + h + " L0 0 Z"
+	return PAGEOBJECT{
+		PTYPE: ptype, WIDTH: w, HEIGHT: h, FRTYPE: "0", CLIPEDIT: "0", ROT: "0", PWIDTH: "1",
+		PCOLOR: "None", PCOLOR2: "None", PLINEART: "1", LOCALSCX: "1", LOCALSCY: "1", LOCALX: "0",
+		LOCALY: "0", LOCALROT: "0", PICART: "1", SCALETYPE: "1", RATIO: "1", COLUMNS: "1", COLGAP: "0",
+		AUTOTEXT: "0", EXTRA: "0", TEXTRA: "0", BEXTRA: "0", REXTRA: "0", VAlign: "0", FLOP: "0",
+		PLTSHOW: "0", BASEOF: "0", TextPathType: "0", TextPathFlipped: "0", Path: path, Copath: path,
+		GWidth: "0", GHeight: "0", LAYER: "0", NEXTITEM: "-1", BACKITEM: "-1",
+	}
+}
+
+// addPageObject puts po on the page with the index page at the position x, y relative to
+// the page, gives it a new ItemID and returns a pointer to it, error
+func (doc *DOCUMENT) addPageObject(page int, x float64, y float64, po PAGEOBJECT) (*PAGEOBJECT, error) {
+	if page < 0 || page >= len(doc.PAGE) {
+		return nil, fmt.Errorf("page %v not found, the document has %v pages", page, len(doc.PAGE))
+	}
+	p := doc.PAGE[page]
+	po.OwnPage = p.NUM
+	po.ItemID = strconv.Itoa(doc.maxItemID() + 1)
+	po.XPOS = formatFloat(parseFloat(p.PAGEXPOS) + x)
+	po.YPOS = formatFloat(parseFloat(p.PAGEYPOS) + y)
+	po.GXpos, po.GYpos = po.XPOS, po.YPOS
+	doc.PAGEOBJECT = append(doc.PAGEOBJECT, po)
+	return &doc.PAGEOBJECT[len(doc.PAGEOBJECT)-1], nil
+}
+
+// MovePageObject moves the PAGEOBJECT (and the items inside it) to the supplied x and y position
+func (po *PAGEOBJECT) MovePageObject(xpos float64, ypos float64) {
+	shiftPageObject(po, xpos-parseFloat(po.XPOS), ypos-parseFloat(po.YPOS))
 }
 
 // TODO: ChangeBulletPointsOfPageObject changes the bullet points of of the StoryText
 // to the contents of a []string
 // We should probably read the first para tag in a StoryText tag that has a BulletStr property, and copy that
+// To build or edit lists with levels, numbers and different bullets, use List (see lists.go)
 func (st *StoryText) ChangeBulletPoints(texts []string) {
 
 	// Get the first ITEXT and the first Para and use them as templates for the ones we are creating
@@ -973,8 +1235,7 @@ func (st *StoryText) ChangeBulletPoints(texts []string) {
 	templatePara := st.Para[0]
 
 	// Clear the pre-existing ITEXTs and PARAs
-	st.ITEXT = nil
-	st.Para = nil
+	st.resetContent()
 
 	var bulletGroups []StoryTextSpan
 
@@ -1011,8 +1272,7 @@ func (st *StoryText) ChangeTextParagraphs(texts []string) {
 
 	// Clear the pre-existing ITEXTs and PARAs
 	st.DefaultStyle = DefaultStyle{}
-	st.ITEXT = nil
-	st.Para = nil
+	st.resetContent()
 	st.Trail = Trail{}
 
 	var bulletGroups []StoryTextSpan
@@ -1036,3 +1296,21 @@ func (scribusDocument *Document) xxxChangePictureOfPageObject(i int, path string
 
 	return nil
 }
+
+// parseFloat returns the value of a numeric attribute, or 0 if it is empty or invalid
+func parseFloat(s string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+// formatFloat formats a numeric attribute the way Scribus writes them
+func formatFloat(f float64) string {
+	f = math.Round(f*1e6) / 1e6
+	if f == 0 {
+		f = 0 // Avoid "-0"
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
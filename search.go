@@ -0,0 +1,305 @@
+package scribus
+
+import (
+	"encoding/xml"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SearchOptions configures Find and Replace. Without options the search is for the plain,
+// case-sensitive text
+type SearchOptions struct {
+	IgnoreCase bool // Case-insensitive search
+	WholeWord  bool // Matches must not be preceded or followed by a letter, digit or '_'
+	Regexp     bool // The search text is a regular expression (see package regexp); replacements may refer to groups such as $1
+}
+
+// TextMatch is a match of Find or Replace
+type TextMatch struct {
+	PageObject  *PAGEOBJECT // Frame whose StoryText contains the match
+	Master      bool        // Whether the frame is a MASTEROBJECT
+	Start       int         // Byte offset of the match in the text of the frame (see StoryText.PlainText) before any replacement
+	End         int         // Byte offset after the match
+	Paragraph   int         // Index of the paragraph in which the match starts
+	Text        string      // Matched text
+	Replacement string      // Text the match was replaced with (Replace only)
+}
+
+// compileSearch returns the regular expression for a search text
+func compileSearch(search string, options SearchOptions) (*regexp.Regexp, error) {
+	if !options.Regexp {
+		search = regexp.QuoteMeta(search)
+	}
+	if options.IgnoreCase {
+		search = "(?i)" + search
+	}
+	return regexp.Compile(search)
+}
+
+// isWordRune tells whether r is part of a word for whole word searches
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// findMatches returns the start and end offsets and the submatches of the matches of re in text
+func findMatches(re *regexp.Regexp, text string, options SearchOptions) [][]int {
+	var matches [][]int
+	for _, m := range re.FindAllStringSubmatchIndex(text, -1) {
+		if m[0] == m[1] {
+			continue // Empty matches are not useful in text
+		}
+		if options.WholeWord {
+			before, _ := utf8.DecodeLastRuneInString(text[:m[0]])
+			after, _ := utf8.DecodeRuneInString(text[m[1]:])
+			if (m[0] > 0 && isWordRune(before)) || (m[1] < len(text) && isWordRune(after)) {
+				continue
+			}
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+// Find returns the matches of search in the text of all frames on pages and master pages,
+// including the frames inside groups. Text is found across ITEXTs and paragraphs, which are
+// separated by "\n" (see StoryText.PlainText). Returns error if search is not a valid
+// regular expression
+func (doc DOCUMENT) Find(search string, options SearchOptions) ([]TextMatch, error) {
+	return doc.searchAndReplace(search, nil, options)
+}
+
+// Replace replaces the matches of search (see Find) with replacement and returns them.
+// The replacement gets the character attributes of the ITEXT in which the match starts;
+// "\n" in the replacement starts a new paragraph and special characters such as "\t"
+// become the corresponding elements. Returns error if search is not a valid regular expression
+func (doc *DOCUMENT) Replace(search string, replacement string, options SearchOptions) ([]TextMatch, error) {
+	return doc.searchAndReplace(search, &replacement, options)
+}
+
+func (doc DOCUMENT) searchAndReplace(search string, replacement *string, options SearchOptions) ([]TextMatch, error) {
+	re, err := compileSearch(search, options)
+	if err != nil {
+		return nil, err
+	}
+	var matches []TextMatch
+	for _, set := range []struct {
+		objs   []PAGEOBJECT
+		master bool
+	}{{doc.MASTEROBJECT, true}, {doc.PAGEOBJECT, false}} {
+		walkPageObjects(set.objs, func(po *PAGEOBJECT) {
+			found := po.StoryText.searchAndReplace(re, replacement, options)
+			for i := range found {
+				found[i].PageObject = po
+				found[i].Master = set.master
+			}
+			matches = append(matches, found...)
+		})
+	}
+	return matches, nil
+}
+
+// searchAndReplace finds the matches of re in the StoryText and replaces them if
+// replacement is not nil
+func (st *StoryText) searchAndReplace(re *regexp.Regexp, replacement *string, options SearchOptions) []TextMatch {
+	text := st.PlainText()
+	found := findMatches(re, text, options)
+	if len(found) == 0 {
+		return nil
+	}
+	matches := make([]TextMatch, len(found))
+	for i, m := range found {
+		matches[i] = TextMatch{Start: m[0], End: m[1], Paragraph: strings.Count(text[:m[0]], "\n"), Text: text[m[0]:m[1]]}
+		if replacement != nil {
+			if options.Regexp {
+				matches[i].Replacement = string(re.ExpandString(nil, *replacement, text, m))
+			} else {
+				matches[i].Replacement = *replacement
+			}
+		}
+	}
+	if replacement != nil {
+		st.replaceMatches(matches)
+	}
+	return matches
+}
+
+// replaceMatches replaces the text of matches, which are ordered by Start and do not
+// overlap, with their Replacement
+func (st *StoryText) replaceMatches(matches []TextMatch) {
+	if len(matches) == 0 {
+		return
+	}
+	st.moveSpansIntoContent()
+	cells, formats := st.cells()
+	// Replace from the end, so that the offsets of the earlier matches stay valid
+	for i := len(matches) - 1; i >= 0; i-- {
+		cells, formats = st.replaceCells(cells, formats, matches[i].Start, matches[i].End, matches[i].Replacement)
+	}
+	st.setCells(cells, formats)
+}
+
+// storyCell is a character of a StoryText: a character of an ITEXT, a para (as "\n"), or
+// another element, which is a special character or has no text at all
+type storyCell struct {
+	text    string
+	run     int // Index of the character attributes of ITEXT characters, -1 for paras and elements
+	para    *Para
+	element *StoryElement
+}
+
+// cells returns the characters of the StoryText and the character attributes of its ITEXTs
+func (st StoryText) cells() ([]storyCell, []ITEXT) {
+	var cells []storyCell
+	var formats []ITEXT
+	for _, ref := range st.sequence() {
+		switch ref.kind {
+		case storyITEXT:
+			format := st.ITEXT[ref.index]
+			format.CH = ""
+			formats = append(formats, format)
+			for _, r := range st.ITEXT[ref.index].CH {
+				cells = append(cells, storyCell{text: string(r), run: len(formats) - 1})
+			}
+		case storyPara:
+			para := st.Para[ref.index]
+			cells = append(cells, storyCell{text: "\n", run: -1, para: &para})
+		case storyElement:
+			element := st.Elements[ref.index]
+			cells = append(cells, storyCell{text: specialChars[element.XMLName.Local], run: -1, element: &element})
+		}
+	}
+	return cells, formats
+}
+
+// setCells replaces the content of the StoryText with cells
+func (st *StoryText) setCells(cells []storyCell, formats []ITEXT) {
+	st.resetContent()
+	var sb strings.Builder
+	run := -1
+	flush := func() {
+		if sb.Len() > 0 {
+			itext := formats[run]
+			itext.CH = sb.String()
+			st.appendITEXT(itext)
+			sb.Reset()
+		}
+	}
+	for _, c := range cells {
+		if c.run >= 0 && c.run == run {
+			sb.WriteString(c.text)
+			continue
+		}
+		flush()
+		run = c.run
+		switch {
+		case c.run >= 0:
+			sb.WriteString(c.text)
+		case c.para != nil:
+			st.appendPara(*c.para)
+		case c.element != nil:
+			st.appendElement(*c.element)
+		}
+	}
+	flush()
+}
+
+// replaceCells replaces the characters between the byte offsets start and end of the
+// text of cells with replacement
+func (st StoryText) replaceCells(cells []storyCell, formats []ITEXT, start int, end int, replacement string) ([]storyCell, []ITEXT) {
+	first, last := len(cells), len(cells)
+	offset := 0
+	for i, c := range cells {
+		if offset >= start && first == len(cells) && c.text != "" {
+			first = i
+		}
+		if offset >= end {
+			last = i
+			break
+		}
+		offset += len(c.text)
+	}
+	if first > last {
+		first = last
+	}
+
+	// The replacement gets the attributes of the ITEXT in which the match starts, or
+	// of the one before or after it
+	run := -1
+	for i := first; i < len(cells) && run < 0 && i < last; i++ {
+		run = cells[i].run
+	}
+	for i := first - 1; i >= 0 && run < 0; i-- {
+		run = cells[i].run
+	}
+	for i := last; i < len(cells) && run < 0; i++ {
+		run = cells[i].run
+	}
+	if run < 0 {
+		formats = append(formats, ITEXT{})
+		run = len(formats) - 1
+	}
+
+	// New paragraphs get the attributes of the para that ends the paragraph of the match
+	var para Para
+	found := false
+	for i := last; i < len(cells); i++ {
+		if cells[i].para != nil {
+			para, found = *cells[i].para, true
+			break
+		}
+	}
+	if !found {
+		copyAttrs(st.Trail, &para, "para")
+	}
+
+	var inserted []storyCell
+	for _, r := range replacement {
+		switch name := specialCharElement(r); {
+		case r == '\n':
+			p := para
+			inserted = append(inserted, storyCell{text: "\n", run: -1, para: &p})
+		case name != "":
+			element := StoryElement{XMLName: xml.Name{Local: name}, Attr: itextAttrs(formats[run])}
+			inserted = append(inserted, storyCell{text: string(r), run: -1, element: &element})
+		default:
+			inserted = append(inserted, storyCell{text: string(r), run: run})
+		}
+	}
+
+	// Elements without text, e.g., page numbers, are kept
+	var kept []storyCell
+	for _, c := range cells[first:last] {
+		if c.text == "" {
+			kept = append(kept, c)
+		}
+	}
+	result := make([]storyCell, 0, len(cells)-(last-first)+len(kept)+len(inserted))
+	result = append(result, cells[:first]...)
+	result = append(result, inserted...)
+	result = append(result, kept...)
+	result = append(result, cells[last:]...)
+	return result, formats
+}
+
+// moveSpansIntoContent turns the StoryTextSpans of older files into ITEXTs and paras
+func (st *StoryText) moveSpansIntoContent() {
+	if len(st.StoryTextSpan) == 0 {
+		return
+	}
+	paragraphs := st.Paragraphs()
+	spans := st.StoryTextSpan
+	st.StoryTextSpan = nil
+	start := len(paragraphs) - len(spans)
+	if start > 0 {
+		// The spans follow the existing content
+		st.appendPara(paragraphs[start-1].Style)
+	}
+	for i, span := range spans {
+		st.appendRun(span.ITEXT)
+		if i < len(spans)-1 {
+			st.appendPara(span.Para)
+		}
+	}
+}
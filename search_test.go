@@ -0,0 +1,72 @@
+package scribus
+
+import (
+	"testing"
+)
+
+func TestFindAndReplace(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := &document.DOCUMENT
+	bold := ITEXT{FONT: "FreeSans Bold", CH: "ld"}
+	bold.SetUnderline(true)
+	doc.PAGEOBJECT[3].StoryText.SetParagraphs([]Paragraph{
+		{Runs: []ITEXT{NewRun("Hello wor"), bold}},
+		{Runs: []ITEXT{NewRun("second World, worldwide")}},
+	})
+	var master PAGEOBJECT
+	master.StoryText.SetPlainText("Footer: world")
+	doc.MASTEROBJECT = append(doc.MASTEROBJECT, master)
+
+	matches, err := doc.Find("world", SearchOptions{})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(matches) != 3 || !matches[0].Master || matches[1].PageObject != &doc.PAGEOBJECT[3] || matches[1].Start != 6 || matches[1].Text != "world" {
+		t.Errorf("Find() was incorrect, got: %+v", matches)
+	}
+
+	matches, _ = doc.Find("WORLD", SearchOptions{IgnoreCase: true, WholeWord: true})
+	if len(matches) != 3 || matches[2].Paragraph != 1 || matches[2].Text != "World" {
+		t.Errorf("Find() was incorrect, got: %+v", matches)
+	}
+
+	if _, err := doc.Find("(", SearchOptions{Regexp: true}); err == nil {
+		t.Errorf("Find() did not return an error for an invalid regular expression")
+	}
+
+	matches, err = doc.Replace(`wor(ld)\b`, "planet-$1", SearchOptions{Regexp: true})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(matches) != 2 || matches[1].Replacement != "planet-ld" {
+		t.Errorf("Replace() was incorrect, got: %+v", matches)
+	}
+
+	// The replacement gets the format of the run in which the match starts
+	clone, err := document.Clone()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	st := clone.DOCUMENT.PAGEOBJECT[3].StoryText
+	if st.PlainText() != "Hello planet-ld\nsecond World, worldwide" {
+		t.Errorf("PlainText() was incorrect, got: %q", st.PlainText())
+	}
+	runs := st.Paragraphs()[0].Runs
+	if len(runs) != 1 || runs[0].Underline() {
+		t.Errorf("runs were incorrect, got: %+v", runs)
+	}
+	if text := clone.DOCUMENT.MASTEROBJECT[0].StoryText.PlainText(); text != "Footer: planet-ld" {
+		t.Errorf("master text was incorrect, got: %q", text)
+	}
+
+	// "\n" starts a new paragraph and "\t" becomes a tab
+	doc.Replace("second", "2nd\tline\n", SearchOptions{})
+	doc.Replace("Hello", "Bye", SearchOptions{})
+	st = doc.PAGEOBJECT[3].StoryText
+	if st.PlainText() != "Bye planet-ld\n2nd\tline\n World, worldwide" {
+		t.Errorf("PlainText() was incorrect, got: %q", st.PlainText())
+	}
+}
@@ -0,0 +1,211 @@
+package scribus
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Pen styles of lines (PLINEART, TableBorderLine PenStyle), the values are those of Qt
+const (
+	PenSolid      = "1"
+	PenDash       = "2"
+	PenDot        = "3"
+	PenDashDot    = "4"
+	PenDashDotDot = "5"
+)
+
+// LineCap is the end of lines (PLINEEND), the values are those of Qt
+type LineCap int
+
+const (
+	CapFlat   LineCap = 0
+	CapSquare LineCap = 16
+	CapRound  LineCap = 32
+)
+
+// LineJoin is the corner where lines meet (PLINEJOIN), the values are those of Qt
+type LineJoin int
+
+const (
+	JoinMiter LineJoin = 0
+	JoinBevel LineJoin = 64
+	JoinRound LineJoin = 128
+)
+
+// ShapeStyle is the fill and stroke of a shape
+type ShapeStyle struct {
+	Fill      string  // Fill colour, "None" if empty
+	Stroke    string  // Line colour, "None" if empty
+	LineWidth float64 // 0 is a hairline
+	Dash      string  // Pen style, e.g., PenDash; solid if empty
+	Cap       LineCap
+	Join      LineJoin
+}
+
+// apply sets the fill and stroke of an item
+func (s ShapeStyle) apply(po *PAGEOBJECT) {
+	po.PCOLOR, po.PCOLOR2 = "None", "None"
+	if s.Fill != "" {
+		po.PCOLOR = s.Fill
+	}
+	if s.Stroke != "" {
+		po.PCOLOR2 = s.Stroke
+	}
+	po.PWIDTH = formatFloat(s.LineWidth)
+	po.PLINEART = PenSolid
+	if s.Dash != "" {
+		po.PLINEART = s.Dash
+	}
+	po.PLINEEND = strconv.Itoa(int(s.Cap))
+	po.PLINEJOIN = strconv.Itoa(int(s.Join))
+}
+
+// newShape returns an item of the type ptype with the outline path, which is moved so that
+// its bounds start at 0, 0. Returns the item and the offset of the bounds
+func newShape(ptype string, frtype string, path Path, style ShapeStyle) (PAGEOBJECT, Point) {
+	min, max := path.Bounds()
+	po := newPageObject(ptype, max.X-min.X, max.Y-min.Y)
+	po.FRTYPE = frtype
+	po.SetOutline(path.Transform(Translation(-min.X, -min.Y)))
+	style.apply(&po)
+	return po, min
+}
+
+// AddRectangle adds a rectangle with the size width x height at the position x, y relative
+// to the page with the index page. Corners are rounded with radius if it is not 0. Returns
+// a pointer to the item, error
+func (doc *DOCUMENT) AddRectangle(page int, x float64, y float64, width float64, height float64, radius float64, style ShapeStyle) (*PAGEOBJECT, error) {
+	radius = math.Min(math.Abs(radius), math.Min(width, height)/2)
+	var path Path
+	if radius == 0 {
+		path.MoveTo(0, 0)
+		path.LineTo(width, 0)
+		path.LineTo(width, height)
+		path.LineTo(0, height)
+		path.LineTo(0, 0)
+		path.Close()
+		po, _ := newShape("6", "0", path, style)
+		return doc.addPageObject(page, x, y, po)
+	}
+	path.MoveTo(radius, 0)
+	path.LineTo(width-radius, 0)
+	path.arcTo(width-radius, radius, radius, radius, -math.Pi/2, math.Pi/2)
+	path.LineTo(width, height-radius)
+	path.arcTo(width-radius, height-radius, radius, radius, 0, math.Pi/2)
+	path.LineTo(radius, height)
+	path.arcTo(radius, height-radius, radius, radius, math.Pi/2, math.Pi/2)
+	path.LineTo(0, radius)
+	path.arcTo(radius, radius, radius, radius, math.Pi, math.Pi/2)
+	path.Close()
+	po, _ := newShape("6", "2", path, style)
+	po.RADRECT = formatFloat(radius)
+	return doc.addPageObject(page, x, y, po)
+}
+
+// AddEllipse adds an ellipse with the size width x height at the position x, y (see
+// AddRectangle)
+func (doc *DOCUMENT) AddEllipse(page int, x float64, y float64, width float64, height float64, style ShapeStyle) (*PAGEOBJECT, error) {
+	var path Path
+	path.MoveTo(width, height/2)
+	path.arcTo(width/2, height/2, width/2, height/2, 0, 2*math.Pi)
+	path.Close()
+	po, _ := newShape("6", "1", path, style)
+	return doc.addPageObject(page, x, y, po)
+}
+
+// AddLine adds a line from x1, y1 to x2, y2 relative to the page with the index page. As
+// in Scribus, the line is horizontal and ROT turns it around its start. Returns a pointer
+// to the item, error
+func (doc *DOCUMENT) AddLine(page int, x1 float64, y1 float64, x2 float64, y2 float64, style ShapeStyle) (*PAGEOBJECT, error) {
+	length := math.Hypot(x2-x1, y2-y1)
+	var path Path
+	path.MoveTo(0, 0)
+	path.LineTo(length, 0)
+	po, _ := newShape("5", "0", path, style)
+	po.HEIGHT = "1"
+	po.ROT = formatFloat(math.Atan2(y2-y1, x2-x1) * 180 / math.Pi)
+	return doc.addPageObject(page, x1, y1, po)
+}
+
+// AddPolygon adds a regular polygon with sides corners that fits into width x height at the
+// position x, y relative to the page with the index page. The first corner is at the top,
+// rotation turns the corners clockwise in degrees. Returns a pointer to the item, error
+func (doc *DOCUMENT) AddPolygon(page int, x float64, y float64, width float64, height float64, sides int, rotation float64, style ShapeStyle) (*PAGEOBJECT, error) {
+	if sides < 3 {
+		return nil, fmt.Errorf("a polygon needs at least 3 sides, got %v", sides)
+	}
+	var path Path
+	for i := 0; i < sides; i++ {
+		a := (rotation-90)*math.Pi/180 + 2*math.Pi*float64(i)/float64(sides)
+		px, py := width/2+width/2*math.Cos(a), height/2+height/2*math.Sin(a)
+		if i == 0 {
+			path.MoveTo(px, py)
+		} else {
+			path.LineTo(px, py)
+		}
+	}
+	path = append(path, PathSegment{Command: 'L', Points: path[0].Points})
+	path.Close()
+	// The corners do not touch all sides of the box, the item is as large as the corners
+	po, offset := newShape("6", "3", path, style)
+	return doc.addPageObject(page, x+offset.X, y+offset.Y, po)
+}
+
+// AddArc adds an open arc of the ellipse that fits into width x height at the position x, y
+// relative to the page with the index page, from the angle start through sweep in degrees,
+// clockwise from 3 o'clock. Returns a pointer to the item, error
+func (doc *DOCUMENT) AddArc(page int, x float64, y float64, width float64, height float64, start float64, sweep float64, style ShapeStyle) (*PAGEOBJECT, error) {
+	if sweep == 0 {
+		return nil, fmt.Errorf("an arc needs a sweep angle")
+	}
+	a := start * math.Pi / 180
+	var path Path
+	path.MoveTo(width/2+width/2*math.Cos(a), height/2+height/2*math.Sin(a))
+	path.arcTo(width/2, height/2, width/2, height/2, a, sweep*math.Pi/180)
+	po, offset := newShape("7", "3", path, style)
+	return doc.addPageObject(page, x+offset.X, y+offset.Y, po)
+}
+
+// AddSpiral adds an Archimedean spiral around the centre cx, cy relative to the page with the
+// index page, which makes turns clockwise turns and grows from the centre to radius.
+// Returns a pointer to the item, error
+func (doc *DOCUMENT) AddSpiral(page int, cx float64, cy float64, radius float64, turns float64, style ShapeStyle) (*PAGEOBJECT, error) {
+	if turns <= 0 || radius <= 0 {
+		return nil, fmt.Errorf("a spiral needs turns and a radius")
+	}
+	// Each quarter turn is a Bezier curve whose control points follow the tangents of the
+	// spiral, which is r(a) = growth * a
+	quarters := int(math.Ceil(turns * 4))
+	step := turns * 2 * math.Pi / float64(quarters)
+	growth := radius / (turns * 2 * math.Pi)
+	h := 4.0 / 3 * math.Tan(step/4)
+	point := func(a float64) Point { return Point{growth * a * math.Cos(a), growth * a * math.Sin(a)} }
+	tangent := func(a float64) Point {
+		return Point{growth * (math.Cos(a) - a*math.Sin(a)), growth * (math.Sin(a) + a*math.Cos(a))}
+	}
+	var path Path
+	path.MoveTo(0, 0)
+	for i := 0; i < quarters; i++ {
+		a, b := float64(i)*step, float64(i+1)*step
+		pa, pb, ta, tb := point(a), point(b), tangent(a), tangent(b)
+		path.CurveTo(pa.X+h*ta.X, pa.Y+h*ta.Y, pb.X-h*tb.X, pb.Y-h*tb.Y, pb.X, pb.Y)
+	}
+	po, offset := newShape("7", "3", path, style)
+	return doc.addPageObject(page, cx+offset.X, cy+offset.Y, po)
+}
+
+// AddPath adds an item with the outline path at the position x, y relative to the page with
+// the index page; the coordinates of path are relative to x, y. Closed paths (see
+// Path.Closed) are polygons, open paths are polylines. Returns a pointer to the item, error
+func (doc *DOCUMENT) AddPath(page int, x float64, y float64, path Path, style ShapeStyle) (*PAGEOBJECT, error) {
+	if len(path) == 0 || path[0].Command != 'M' {
+		return nil, fmt.Errorf("a path must start with a move")
+	}
+	ptype := "7"
+	if path.Closed() {
+		ptype = "6"
+	}
+	po, offset := newShape(ptype, "3", path, style)
+	return doc.addPageObject(page, x+offset.X, y+offset.Y, po)
+}
@@ -0,0 +1,106 @@
+package scribus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShapes(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := &document.DOCUMENT
+	style := ShapeStyle{Fill: "Black", Stroke: "White", LineWidth: 2, Dash: PenDash, Cap: CapRound, Join: JoinBevel}
+
+	rect, err := doc.AddRectangle(0, 10, 20, 100, 50, 0, style)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if rect.PTYPE != "6" || rect.FRTYPE != "0" || rect.Path != "M0 0 L100 0 L100 50 L0 50 L0 0 Z" || rect.Copath != rect.Path || rect.XPOS != "110" || rect.YPOS != "40" {
+		t.Errorf("AddRectangle() was incorrect, got: %v, %v, %q at %v, %v", rect.PTYPE, rect.FRTYPE, rect.Path, rect.XPOS, rect.YPOS)
+	}
+	if rect.PCOLOR != "Black" || rect.PCOLOR2 != "White" || rect.PWIDTH != "2" || rect.PLINEART != "2" || rect.PLINEEND != "32" || rect.PLINEJOIN != "64" {
+		t.Errorf("style was incorrect, got: %+v", rect)
+	}
+
+	rounded, err := doc.AddRectangle(0, 0, 0, 100, 50, 10, ShapeStyle{})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if rounded.FRTYPE != "2" || rounded.RADRECT != "10" || rounded.WIDTH != "100" || rounded.HEIGHT != "50" || !strings.HasPrefix(rounded.Path, "M10 0 L90 0 C95.522847 0 100 4.477153 100 10 L100 40") {
+		t.Errorf("rounded rectangle was incorrect, got: %v, %v, %q", rounded.FRTYPE, rounded.RADRECT, rounded.Path)
+	}
+	if rounded.PCOLOR != "None" || rounded.PCOLOR2 != "None" || rounded.PLINEART != PenSolid {
+		t.Errorf("empty style was incorrect, got: %v, %v, %v", rounded.PCOLOR, rounded.PCOLOR2, rounded.PLINEART)
+	}
+
+	ellipse, err := doc.AddEllipse(0, 0, 0, 80, 40, style)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if ellipse.FRTYPE != "1" || ellipse.WIDTH != "80" || ellipse.HEIGHT != "40" || strings.Count(ellipse.Path, "C") != 4 || !strings.HasPrefix(ellipse.Path, "M80 20 C80 31.045695") {
+		t.Errorf("AddEllipse() was incorrect, got: %v, %v x %v, %q", ellipse.FRTYPE, ellipse.WIDTH, ellipse.HEIGHT, ellipse.Path)
+	}
+
+	line, err := doc.AddLine(0, 0, 0, 30, 40, style)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if line.PTYPE != "5" || line.WIDTH != "50" || line.HEIGHT != "1" || line.ROT != "53.130102" || line.Path != "M0 0 L50 0" {
+		t.Errorf("AddLine() was incorrect, got: %v, %v x %v, %v, %q", line.PTYPE, line.WIDTH, line.HEIGHT, line.ROT, line.Path)
+	}
+
+	triangle, err := doc.AddPolygon(0, 0, 0, 100, 100, 3, 0, style)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	// The corners of a triangle in a 100 x 100 box are 86.6 apart and reach down to 75
+	if triangle.FRTYPE != "3" || triangle.WIDTH != "86.60254" || triangle.HEIGHT != "75" || triangle.XPOS != "106.69873" || !strings.HasPrefix(triangle.Path, "M43.30127 0 L86.60254 75 L0 75 L43.30127 0 Z") {
+		t.Errorf("AddPolygon() was incorrect, got: %v x %v at %v, %q", triangle.WIDTH, triangle.HEIGHT, triangle.XPOS, triangle.Path)
+	}
+	if _, err := doc.AddPolygon(0, 0, 0, 10, 10, 2, 0, style); err == nil {
+		t.Errorf("AddPolygon() with 2 sides should be an error")
+	}
+
+	arc, err := doc.AddArc(0, 0, 0, 100, 100, 0, 90, style)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if arc.PTYPE != "7" || arc.Path != "M50 0 C50 27.614237 27.614237 50 0 50" || arc.XPOS != "150" || arc.YPOS != "70" {
+		t.Errorf("AddArc() was incorrect, got: %v, %q at %v, %v", arc.PTYPE, arc.Path, arc.XPOS, arc.YPOS)
+	}
+
+	spiral, err := doc.AddSpiral(0, 200, 200, 50, 2, style)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if spiral.PTYPE != "7" || strings.Count(spiral.Path, "C") != 8 || strings.HasSuffix(spiral.Path, "Z") {
+		t.Errorf("AddSpiral() was incorrect, got: %v, %q", spiral.PTYPE, spiral.Path)
+	}
+
+	var path Path
+	path.MoveTo(10, 10)
+	path.CurveTo(20, 0, 30, 0, 40, 10)
+	path.LineTo(25, 30)
+	path.Close()
+	shape, err := doc.AddPath(0, 0, 0, path, style)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	// The curve stays 2.5 short of its control points, which lie outside the item
+	if shape.PTYPE != "6" || shape.Path != "M0 7.5 C10 -2.5 20 -2.5 30 7.5 L15 27.5 Z" || shape.XPOS != "110" || shape.YPOS != "22.5" || shape.HEIGHT != "27.5" {
+		t.Errorf("AddPath() was incorrect, got: %v, %q at %v, %v", shape.PTYPE, shape.Path, shape.XPOS, shape.YPOS)
+	}
+	if _, err := doc.AddPath(0, 0, 0, Path{}, style); err == nil {
+		t.Errorf("AddPath() of an empty path should be an error")
+	}
+
+	ids := map[string]bool{}
+	for _, po := range doc.PAGEOBJECT {
+		if ids[po.ItemID] {
+			t.Errorf("ItemID %v is not unique", po.ItemID)
+		}
+		ids[po.ItemID] = true
+	}
+}
@@ -0,0 +1,263 @@
+package scribus
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// StoryElement is an element inside a StoryText other than DefaultStyle, ITEXT, para
+// and trail, e.g., a special character such as 'tab' or 'breakline'. It is kept as is
+type StoryElement struct {
+	XMLName  xml.Name
+	Attr     []xml.Attr `xml:",any,attr"`
+	InnerXML string     `xml:",innerxml"`
+}
+
+// storyRef refers to the n'th ITEXT, para or StoryElement of a StoryText
+type storyRef struct {
+	kind  byte // storyITEXT, storyPara or storyElement
+	index int
+}
+
+const (
+	storyITEXT byte = iota
+	storyPara
+	storyElement
+)
+
+// specialChars maps the StoryText elements that stand for special characters
+// to the characters Scribus uses for them internally
+var specialChars = map[string]string{
+	"tab":        "\t",
+	"breakline":  "\u2028",
+	"breakcol":   "\u001a",
+	"breakframe": "\u001b",
+	"nbhyphen":   "\u2011",
+	"nbspace":    "\u00a0",
+	"zwnbspace":  "\ufeff",
+	"zwspace":    "\u200b",
+}
+
+// specialCharElement returns the name of the StoryText element for a special
+// character, or "" if r is an ordinary character
+func specialCharElement(r rune) string {
+	for name, s := range specialChars {
+		if s == string(r) {
+			return name
+		}
+	}
+	return ""
+}
+
+// UnmarshalXML reads a StoryText and remembers the order of its elements
+func (st *StoryText) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*st = StoryText{XMLName: start.Name}
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			st.Text += string(t)
+		case xml.EndElement:
+			return nil
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "DefaultStyle":
+				err = d.DecodeElement(&st.DefaultStyle, &t)
+			case "trail":
+				err = d.DecodeElement(&st.Trail, &t)
+			case "StoryTextSpan":
+				var span StoryTextSpan
+				if err = d.DecodeElement(&span, &t); err == nil {
+					st.StoryTextSpan = append(st.StoryTextSpan, span)
+				}
+			case "ITEXT":
+				var itext ITEXT
+				if err = d.DecodeElement(&itext, &t); err == nil {
+					st.appendITEXT(itext)
+				}
+			case "para":
+				var para Para
+				if err = d.DecodeElement(&para, &t); err == nil {
+					st.appendPara(para)
+				}
+			default:
+				var element StoryElement
+				if err = d.DecodeElement(&element, &t); err == nil {
+					st.appendElement(element)
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// MarshalXML writes a StoryText with its elements in the original order
+func (st StoryText) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Local: "StoryText"}}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if strings.TrimSpace(st.Text) != "" {
+		if err := e.EncodeToken(xml.CharData(st.Text)); err != nil {
+			return err
+		}
+	}
+	if err := e.EncodeElement(st.DefaultStyle, xml.StartElement{Name: xml.Name{Local: "DefaultStyle"}}); err != nil {
+		return err
+	}
+	for _, ref := range st.sequence() {
+		var err error
+		switch ref.kind {
+		case storyITEXT:
+			err = e.EncodeElement(st.ITEXT[ref.index], xml.StartElement{Name: xml.Name{Local: "ITEXT"}})
+		case storyPara:
+			err = e.EncodeElement(st.Para[ref.index], xml.StartElement{Name: xml.Name{Local: "para"}})
+		case storyElement:
+			element := st.Elements[ref.index]
+			err = e.EncodeElement(element, xml.StartElement{Name: element.XMLName})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := e.EncodeElement(st.Trail, xml.StartElement{Name: xml.Name{Local: "trail"}}); err != nil {
+		return err
+	}
+	for _, span := range st.StoryTextSpan {
+		if err := e.EncodeElement(span, xml.StartElement{Name: xml.Name{Local: "StoryTextSpan"}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+func (st *StoryText) appendITEXT(itext ITEXT) {
+	st.order = append(st.order, storyRef{storyITEXT, len(st.ITEXT)})
+	st.ITEXT = append(st.ITEXT, itext)
+}
+
+func (st *StoryText) appendPara(para Para) {
+	st.order = append(st.order, storyRef{storyPara, len(st.Para)})
+	st.Para = append(st.Para, para)
+}
+
+func (st *StoryText) appendElement(element StoryElement) {
+	st.order = append(st.order, storyRef{storyElement, len(st.Elements)})
+	st.Elements = append(st.Elements, element)
+}
+
+// resetContent removes all ITEXTs, paras and special characters
+func (st *StoryText) resetContent() {
+	st.ITEXT = nil
+	st.Para = nil
+	st.Elements = nil
+	st.order = nil
+}
+
+// sequence returns the order in which the ITEXTs, paras and special characters are written:
+// the remembered order without the elements that were removed from ITEXT, Para or Elements
+// directly, followed by the elements that were appended to them directly. Of those, every
+// ITEXT is followed by the para with the same index, and the special characters come last
+func (st StoryText) sequence() []storyRef {
+	lengths := [3]int{len(st.ITEXT), len(st.Para), len(st.Elements)}
+	remembered := [3]int{}
+	var seq []storyRef
+	for _, ref := range st.order {
+		if ref.index < lengths[ref.kind] {
+			seq = append(seq, ref)
+			remembered[ref.kind] = max(remembered[ref.kind], ref.index+1)
+		}
+	}
+	for i, j := remembered[storyITEXT], remembered[storyPara]; i < lengths[storyITEXT] || j < lengths[storyPara]; i, j = i+1, j+1 {
+		if i < lengths[storyITEXT] {
+			seq = append(seq, storyRef{storyITEXT, i})
+		}
+		if j < lengths[storyPara] {
+			seq = append(seq, storyRef{storyPara, j})
+		}
+	}
+	for i := remembered[storyElement]; i < lengths[storyElement]; i++ {
+		seq = append(seq, storyRef{storyElement, i})
+	}
+	return seq
+}
+
+// PlainText returns the text of the StoryText. Paragraphs are separated by "\n",
+// special characters such as tabs are returned as the characters Scribus uses for them
+// (e.g., "\t" for a tab and "\u2028" for a line break)
+func (st StoryText) PlainText() string {
+	var sb strings.Builder
+	for _, ref := range st.sequence() {
+		switch ref.kind {
+		case storyITEXT:
+			sb.WriteString(st.ITEXT[ref.index].CH)
+		case storyPara:
+			sb.WriteString("\n")
+		case storyElement:
+			sb.WriteString(specialChars[st.Elements[ref.index].XMLName.Local])
+		}
+	}
+	for i, span := range st.StoryTextSpan {
+		if i > 0 || sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(span.ITEXT.CH)
+	}
+	return sb.String()
+}
+
+// SetPlainText replaces the content of the StoryText with text. Paragraphs are separated
+// by "\n", special characters (see PlainText) become the corresponding elements. The
+// character attributes of the first ITEXT and the first para are used for the new text;
+// the trail, which holds the style of the last paragraph, is kept
+func (st *StoryText) SetPlainText(text string) {
+	var templateItext ITEXT
+	if len(st.ITEXT) > 0 {
+		templateItext = st.ITEXT[0]
+	} else if len(st.StoryTextSpan) > 0 {
+		templateItext = st.StoryTextSpan[0].ITEXT
+	}
+	var templatePara Para
+	if len(st.Para) > 0 {
+		templatePara = st.Para[0]
+	} else if len(st.StoryTextSpan) > 0 {
+		templatePara = st.StoryTextSpan[0].Para
+	}
+
+	st.resetContent()
+	st.StoryTextSpan = nil
+	for i, paragraph := range strings.Split(text, "\n") {
+		if i > 0 {
+			st.appendPara(templatePara)
+		}
+		templateItext.CH = paragraph
+		st.appendRun(templateItext)
+	}
+}
+
+// appendRun appends run, turning special characters into elements that keep the attributes of run
+func (st *StoryText) appendRun(run ITEXT) {
+	var sb strings.Builder
+	flush := func() {
+		if sb.Len() > 0 {
+			itext := run
+			itext.CH = sb.String()
+			st.appendITEXT(itext)
+			sb.Reset()
+		}
+	}
+	for _, r := range run.CH {
+		if name := specialCharElement(r); name != "" {
+			flush()
+			st.appendElement(StoryElement{XMLName: xml.Name{Local: name}, Attr: itextAttrs(run)})
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	flush()
+}
@@ -0,0 +1,64 @@
+package scribus
+
+// Names of the default styles that every Scribus document has
+const (
+	DefaultParagraphStyle = "Default Paragraph Style"
+	DefaultCharStyle      = "Default Character Style"
+	DefaultTableStyle     = "Default Table Style"
+	DefaultCellStyle      = "Default Cell Style"
+)
+
+// GetParagraphStyle returns a pointer to the paragraph style with the given name, or nil
+func (doc DOCUMENT) GetParagraphStyle(name string) *STYLE {
+	for i := range doc.STYLE {
+		if doc.STYLE[i].NAME == name {
+			return &doc.STYLE[i]
+		}
+	}
+	return nil
+}
+
+// GetCharStyle returns a pointer to the character style with the given name, or nil
+func (doc DOCUMENT) GetCharStyle(name string) *CHARSTYLE {
+	for i := range doc.CHARSTYLE {
+		if doc.CHARSTYLE[i].CNAME == name {
+			return &doc.CHARSTYLE[i]
+		}
+	}
+	return nil
+}
+
+// EnsureParagraphStyle returns a pointer to the paragraph style with the given name.
+// If it does not exist yet, it is created as a copy of the default paragraph style that
+// inherits from it. The STYLE attributes are always written, so they are copied rather than
+// left empty
+func (doc *DOCUMENT) EnsureParagraphStyle(name string) *STYLE {
+	if s := doc.GetParagraphStyle(name); s != nil {
+		return s
+	}
+	style := STYLE{}
+	if def := doc.GetParagraphStyle(DefaultParagraphStyle); def != nil {
+		style = *def
+	}
+	style.NAME = name
+	style.PARENT = DefaultParagraphStyle
+	style.DefaultStyle = "0"
+	doc.STYLE = append(doc.STYLE, style)
+	return &doc.STYLE[len(doc.STYLE)-1]
+}
+
+// EnsureCharStyle returns a pointer to the character style with the given name.
+// If it does not exist yet, it is created as a copy of the default character style
+func (doc *DOCUMENT) EnsureCharStyle(name string) *CHARSTYLE {
+	if s := doc.GetCharStyle(name); s != nil {
+		return s
+	}
+	style := CHARSTYLE{}
+	if def := doc.GetCharStyle(DefaultCharStyle); def != nil {
+		style = *def
+	}
+	style.CNAME = name
+	style.DefaultStyle = "0"
+	doc.CHARSTYLE = append(doc.CHARSTYLE, style)
+	return &doc.CHARSTYLE[len(doc.CHARSTYLE)-1]
+}
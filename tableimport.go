@@ -0,0 +1,351 @@
+package scribus
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// TableImportOptions control how rows are imported into table frames (see ImportTable)
+type TableImportOptions struct {
+	HeaderRows      int       // Number of rows at the top that are repeated on every continued table
+	Width           float64   // Width of the table; the page width within the margins right of the table by default
+	ColumnWidths    []float64 // Widths of the columns; columns share Width equally by default
+	MinRowHeight    float64   // Rows are at least this high
+	Style           string    // Table style (see TableStyle)
+	CellStyle       string    // Cell style of the body rows
+	HeaderCellStyle string    // Cell style of the header rows, CellStyle by default
+	CharStyle       string    // Character style of the text of the body rows
+	HeaderCharStyle string    // Character style of the text of the header rows, CharStyle by default
+}
+
+// ReadCSVTable reads the rows of a CSV file, e.g., for ImportTable
+func ReadCSVTable(r io.Reader) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+	}
+	return rows, nil
+}
+
+// xlsxWorkbook is xl/workbook.xml of an xlsx file
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxRelationships is xl/_rels/workbook.xml.rels of an xlsx file
+type xlsxRelationships struct {
+	Relationship []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText is a string of an xlsx file, either plain or made of formatted runs
+type xlsxText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+// String returns the text of the string
+func (t xlsxText) String() string {
+	s := t.T
+	for _, r := range t.R {
+		s += r.T
+	}
+	return s
+}
+
+// xlsxSharedStrings is xl/sharedStrings.xml of an xlsx file
+type xlsxSharedStrings struct {
+	SI []xlsxText `xml:"si"`
+}
+
+// xlsxWorksheet is a worksheet of an xlsx file
+type xlsxWorksheet struct {
+	Rows []struct {
+		R string `xml:"r,attr"`
+		C []struct {
+			R  string   `xml:"r,attr"`
+			T  string   `xml:"t,attr"`
+			V  string   `xml:"v"`
+			Is xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readZipXML decodes the file name of the zip archive into v, returns whether it exists, error
+func readZipXML(archive *zip.ReadCloser, name string, v interface{}) (bool, error) {
+	for _, f := range archive.File {
+		if f.Name != name {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return true, err
+		}
+		defer r.Close()
+		return true, xml.NewDecoder(r).Decode(v)
+	}
+	return false, nil
+}
+
+// ReadXLSXTable reads the rows of the worksheet with the name sheet ("" for the first one)
+// of the xlsx file filename, e.g., for ImportTable. Cells hold their text or their value as
+// stored, formulas are not evaluated
+func ReadXLSXTable(filename string, sheet string) ([][]string, error) {
+	archive, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	var workbook xlsxWorkbook
+	var rels xlsxRelationships
+	if found, err := readZipXML(archive, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	} else if !found {
+		return nil, fmt.Errorf("%v is not an xlsx file", filename)
+	}
+	if _, err := readZipXML(archive, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	target := ""
+	for _, s := range workbook.Sheets {
+		if sheet != "" && s.Name != sheet {
+			continue
+		}
+		for _, rel := range rels.Relationship {
+			if rel.ID == s.ID {
+				target = rel.Target
+			}
+		}
+		break
+	}
+	if target == "" {
+		return nil, fmt.Errorf("sheet %q not found in %v", sheet, filename)
+	}
+	if strings.HasPrefix(target, "/") {
+		target = strings.TrimPrefix(target, "/")
+	} else {
+		target = path.Join("xl", target)
+	}
+
+	var shared xlsxSharedStrings
+	if _, err := readZipXML(archive, "xl/sharedStrings.xml", &shared); err != nil {
+		return nil, err
+	}
+	var worksheet xlsxWorksheet
+	if _, err := readZipXML(archive, target, &worksheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, r := range worksheet.Rows {
+		index := len(rows)
+		if n, err := strconv.Atoi(r.R); err == nil {
+			index = n - 1
+		}
+		for len(rows) <= index {
+			rows = append(rows, nil)
+		}
+		row := rows[index]
+		for _, c := range r.C {
+			column := len(row)
+			if c.R != "" {
+				column = xlsxColumn(c.R)
+			}
+			for len(row) <= column {
+				row = append(row, "")
+			}
+			switch c.T {
+			case "s":
+				if i, err := strconv.Atoi(c.V); err == nil && i >= 0 && i < len(shared.SI) {
+					row[column] = shared.SI[i].String()
+				}
+			case "inlineStr":
+				row[column] = c.Is.String()
+			case "b":
+				row[column] = strconv.FormatBool(c.V == "1")
+			default:
+				row[column] = c.V
+			}
+		}
+		rows[index] = row
+	}
+	return rows, nil
+}
+
+// xlsxColumn returns the index of the column of a cell reference such as "AB12"
+func xlsxColumn(ref string) int {
+	column := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+	}
+	return column - 1
+}
+
+// fontSize returns the font size of the character style with the name, or of the default
+// character style
+func (doc DOCUMENT) fontSize(charStyle string) float64 {
+	for _, name := range []string{charStyle, DefaultCharStyle} {
+		if s := doc.GetCharStyle(name); s != nil && parseFloat(s.FONTSIZE) > 0 {
+			return parseFloat(s.FONTSIZE)
+		}
+	}
+	return 12
+}
+
+// estimateRowHeight estimates the height of a row from the number of lines its cells need.
+// A character is taken to be half the font size wide and a line 1.2 times the font size high
+func estimateRowHeight(row []string, widths []float64, fontSize float64, padding CellStyle) float64 {
+	height := 0.0
+	for i, text := range row {
+		if i >= len(widths) {
+			break
+		}
+		width := math.Max(widths[i]-parseFloat(padding.LeftPadding)-parseFloat(padding.RightPadding), fontSize)
+		lines := 0.0
+		for _, line := range strings.Split(text, "\n") {
+			lines += math.Max(1, math.Ceil(float64(utf8.RuneCountInString(line))*fontSize/2/width))
+		}
+		height = math.Max(height, lines*fontSize*1.2+parseFloat(padding.TopPadding)+parseFloat(padding.BottomPadding))
+	}
+	return height
+}
+
+// ImportTable adds the rows as table frames starting at the position x, y on the page with
+// the index page. Row heights are estimated from the font sizes of the character styles and
+// the paddings of the cell styles. Rows that do not fit above the bottom margin continue in
+// a new table at the top margin of the next page, which is added as a copy of the page if
+// the document has no more pages, and the header rows are repeated there. Returns the
+// tables, error
+func (doc *DOCUMENT) ImportTable(page int, x float64, y float64, rows [][]string, options TableImportOptions) ([]Table, error) {
+	if page < 0 || page >= len(doc.PAGE) {
+		return nil, fmt.Errorf("page %v not found, the document has %v pages", page, len(doc.PAGE))
+	}
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 || options.HeaderRows < 0 || options.HeaderRows >= len(rows) {
+		return nil, fmt.Errorf("no rows to import")
+	}
+	if options.HeaderCellStyle == "" {
+		options.HeaderCellStyle = options.CellStyle
+	}
+	if options.HeaderCharStyle == "" {
+		options.HeaderCharStyle = options.CharStyle
+	}
+
+	widths := options.ColumnWidths
+	if len(widths) == 0 {
+		first := doc.PAGE[page]
+		width := options.Width
+		if width <= 0 {
+			width = parseFloat(first.PAGEWIDTH) - parseFloat(first.BORDERRIGHT) - x
+		}
+		widths = make([]float64, columns)
+		for i := range widths {
+			widths[i] = width / float64(columns)
+		}
+	} else if len(widths) != columns {
+		return nil, fmt.Errorf("%v column widths for %v columns", len(widths), columns)
+	}
+
+	header, body := rows[:options.HeaderRows], rows[options.HeaderRows:]
+	heights := make([]float64, len(rows))
+	for i, row := range rows {
+		cellStyle, charStyle := options.CellStyle, options.CharStyle
+		if i < len(header) {
+			cellStyle, charStyle = options.HeaderCellStyle, options.HeaderCharStyle
+		}
+		padding, err := doc.ResolveCellStyle(cellStyle)
+		if err != nil && cellStyle != "" {
+			return nil, err
+		}
+		heights[i] = math.Max(options.MinRowHeight, estimateRowHeight(row, widths, doc.fontSize(charStyle), padding))
+	}
+	headerHeight := 0.0
+	for _, h := range heights[:len(header)] {
+		headerHeight += h
+	}
+
+	var frames []int
+	for len(body) > 0 {
+		p := doc.PAGE[page]
+		available := parseFloat(p.PAGEHEIGHT) - parseFloat(p.BORDERBOTTOM) - y - headerHeight
+		n := 0
+		for used := 0.0; n < len(body); n++ {
+			h := heights[len(rows)-len(body)+n]
+			if n > 0 && used+h > available {
+				break
+			}
+			used += h
+		}
+		var chunk [][]string
+		for _, row := range append(append([][]string{}, header...), body[:n]...) {
+			chunk = append(chunk, append(append([]string{}, row...), make([]string, columns-len(row))...))
+		}
+		chunkHeights := append(append([]float64{}, heights[:len(header)]...), heights[len(rows)-len(body):len(rows)-len(body)+n]...)
+		t, err := doc.AddTable(page, x, y, chunk, widths[0], chunkHeights[0])
+		if err != nil {
+			return nil, err
+		}
+		t.Frame.TableData.ColumnWidths = formatFloats(widths)
+		t.Frame.TableData.RowHeights = formatFloats(chunkHeights)
+		t.resize()
+		t.SetStyle(options.Style)
+		t.SetCellStyle(CellArea{Rows: len(header), Columns: columns}, options.HeaderCellStyle)
+		t.SetCellStyle(CellArea{Row: len(header), Rows: n, Columns: columns}, options.CellStyle)
+		for i := range t.Frame.TableData.Cell {
+			c := &t.Frame.TableData.Cell[i]
+			row, _ := c.position()
+			charStyle := options.CharStyle
+			if row < len(header) {
+				charStyle = options.HeaderCharStyle
+			}
+			for j := range c.StoryText.ITEXT {
+				c.StoryText.ITEXT[j].CPARENT = charStyle
+			}
+		}
+		frames = append(frames, len(doc.PAGEOBJECT)-1)
+
+		body = body[n:]
+		if len(body) > 0 {
+			if page+1 < len(doc.PAGE) {
+				page++
+			} else {
+				doc.appendPage(doc.PAGE[page])
+				page = len(doc.PAGE) - 1
+			}
+			y = parseFloat(doc.PAGE[page].BORDERTOP)
+		}
+	}
+	// Pointers are taken at the end because adding frames moves the items
+	tables := make([]Table, len(frames))
+	for i, index := range frames {
+		tables[i] = Table{Frame: &doc.PAGEOBJECT[index]}
+	}
+	return tables, nil
+}
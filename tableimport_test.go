@@ -0,0 +1,103 @@
+package scribus
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportTable(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := &document.DOCUMENT
+
+	csv := "\ufeffItem,Price\n"
+	for i := 1; i <= 60; i++ {
+		csv += fmt.Sprintf("Product %v,%v.00\n", i, i)
+	}
+	csv += "\"A product with a very long name that needs a few lines in its cell\n(and a second paragraph)\",99.00\n"
+	rows, err := ReadCSVTable(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(rows) != 62 || rows[0][0] != "Item" {
+		t.Fatalf("ReadCSVTable() was incorrect, got: %v rows, %q", len(rows), rows[0][0])
+	}
+
+	doc.EnsureCharStyle("Header")
+	tables, err := doc.ImportTable(0, 0, 0, rows, TableImportOptions{HeaderRows: 1, ColumnWidths: []float64{150, 50}, HeaderCharStyle: "Header"})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	// Rows are 12 * 1.2 + 2 = 16.4 high, a page has room for 752 / 16.4 = 45 rows
+	if len(tables) != 2 || len(doc.PAGE) != 2 || tables[0].Rows() != 45 || tables[1].Rows() != 18 {
+		t.Fatalf("ImportTable() was incorrect, got: %v tables, %v pages", len(tables), len(doc.PAGE))
+	}
+	first, second := tables[0], tables[1]
+	if first.Frame.WIDTH != "200" || first.Frame.YPOS != "20" || first.RowHeights()[0] != 16.4 {
+		t.Errorf("first table was incorrect, got: %v, %v, %v", first.Frame.WIDTH, first.Frame.YPOS, first.RowHeights())
+	}
+	if second.Frame.OwnPage != "1" || second.Frame.YPOS != "892" || second.Grid()[0][1] != "Price" || second.Grid()[1][0] != "Product 45" {
+		t.Errorf("second table was incorrect, got: %v, %v, %q", second.Frame.OwnPage, second.Frame.YPOS, second.Grid()[:2])
+	}
+	// 67 characters of 6 points need 3 lines in 148 points, plus a line for the second paragraph
+	if h := second.RowHeights()[17]; h != 4*14.4+2 {
+		t.Errorf("height of the long row was incorrect, got: %v", h)
+	}
+	if second.Cell(0, 0).StoryText.ITEXT[0].CPARENT != "Header" || second.Cell(1, 0).StoryText.ITEXT[0].CPARENT != "" {
+		t.Errorf("character styles were incorrect, got: %+v", second.Cell(0, 0).StoryText.ITEXT)
+	}
+
+	if _, err := doc.ImportTable(0, 0, 0, rows, TableImportOptions{CellStyle: "Missing"}); err == nil {
+		t.Errorf("ImportTable() with a missing cell style should be an error")
+	}
+}
+
+func TestReadXLSXTable(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "prices.xlsx")
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	w := zip.NewWriter(f)
+	for name, content := range map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Notes" sheetId="1" r:id="rId1"/><sheet name="Prices" sheetId="2" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Target="worksheets/sheet2.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":     `<sst><si><t>Item</t></si><si><t>Price</t></si><si><r><t>Pen</t></r><r><t>cil</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>Nothing</t></is></c></row></sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>
+<row r="3"><c r="A3" t="s"><v>2</v></c><c r="C3"><v>1.5</v></c></row></sheetData></worksheet>`,
+	} {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+		fw.Write([]byte(content))
+	}
+	w.Close()
+	f.Close()
+
+	rows, err := ReadXLSXTable(filename, "Prices")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	want := [][]string{{"Item", "Price"}, nil, {"Pencil", "", "1.5"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("ReadXLSXTable() was incorrect, got: %q, want: %q.", rows, want)
+	}
+	if rows, err := ReadXLSXTable(filename, ""); err != nil || rows[0][0] != "Nothing" {
+		t.Errorf("ReadXLSXTable() of the first sheet was incorrect, got: %q, %v", rows, err)
+	}
+	if _, err := ReadXLSXTable(filename, "Missing"); err == nil {
+		t.Errorf("ReadXLSXTable() of a missing sheet should be an error")
+	}
+}
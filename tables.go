@@ -0,0 +1,317 @@
+package scribus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Table gives access to the rows, columns and cells of a table frame (PTYPE 16)
+type Table struct {
+	Frame *PAGEOBJECT
+}
+
+// CellArea is a range of cells, e.g., merged cells
+type CellArea struct {
+	Row     int
+	Column  int
+	Rows    int
+	Columns int
+}
+
+// contains tells whether the cell at row, column is in the area
+func (a CellArea) contains(row int, column int) bool {
+	return row >= a.Row && row < a.Row+a.Rows && column >= a.Column && column < a.Column+a.Columns
+}
+
+// Table returns the table of a table frame, error if the item is not a table
+func (po *PAGEOBJECT) Table() (Table, error) {
+	if po.PTYPE != "16" || po.TableData == nil {
+		return Table{}, fmt.Errorf("item %v is not a table", po.ItemID)
+	}
+	return Table{Frame: po}, nil
+}
+
+// Tables returns the tables on pages and master pages and in inline frames, including those
+// inside groups
+func (doc DOCUMENT) Tables() []Table {
+	var tables []Table
+	doc.walkAllPageObjects(func(po *PAGEOBJECT) {
+		if t, err := po.Table(); err == nil {
+			tables = append(tables, t)
+		}
+	})
+	return tables
+}
+
+// parseFloats parses a list of numbers separated by spaces
+func parseFloats(s string) []float64 {
+	var values []float64
+	for _, field := range strings.Fields(s) {
+		values = append(values, parseFloat(field))
+	}
+	return values
+}
+
+// formatFloats formats a list of numbers separated by spaces
+func formatFloats(values []float64) string {
+	fields := make([]string, len(values))
+	for i, v := range values {
+		fields[i] = formatFloat(v)
+	}
+	return strings.Join(fields, " ")
+}
+
+// Rows returns the number of rows
+func (t Table) Rows() int {
+	n, _ := strconv.Atoi(t.Frame.TableData.Rows)
+	return n
+}
+
+// Columns returns the number of columns
+func (t Table) Columns() int {
+	n, _ := strconv.Atoi(t.Frame.TableData.Columns)
+	return n
+}
+
+// RowHeights returns the heights of the rows in points
+func (t Table) RowHeights() []float64 { return parseFloats(t.Frame.TableData.RowHeights) }
+
+// ColumnWidths returns the widths of the columns in points
+func (t Table) ColumnWidths() []float64 { return parseFloats(t.Frame.TableData.ColumnWidths) }
+
+// SetRowHeight sets the height of a row and resizes the frame, returns error if there is no such row
+func (t Table) SetRowHeight(row int, height float64) error {
+	heights := t.RowHeights()
+	if row < 0 || row >= len(heights) {
+		return fmt.Errorf("row %v not found, the table has %v rows", row, len(heights))
+	}
+	heights[row] = height
+	t.Frame.TableData.RowHeights = formatFloats(heights)
+	t.resize()
+	return nil
+}
+
+// SetColumnWidth sets the width of a column and resizes the frame, returns error if there is no such column
+func (t Table) SetColumnWidth(column int, width float64) error {
+	widths := t.ColumnWidths()
+	if column < 0 || column >= len(widths) {
+		return fmt.Errorf("column %v not found, the table has %v columns", column, len(widths))
+	}
+	widths[column] = width
+	t.Frame.TableData.ColumnWidths = formatFloats(widths)
+	t.resize()
+	return nil
+}
+
+// resize makes the frame as large as the rows and columns
+func (t Table) resize() {
+	width, height := 0.0, 0.0
+	for _, w := range t.ColumnWidths() {
+		width += w
+	}
+	for _, h := range t.RowHeights() {
+		height += h
+	}
+	size := newPageObject(t.Frame.PTYPE, width, height)
+	t.Frame.WIDTH, t.Frame.HEIGHT, t.Frame.Path, t.Frame.Copath = size.WIDTH, size.HEIGHT, size.Path, size.Copath
+}
+
+// MergedCells returns the areas of merged cells
+func (t Table) MergedCells() []CellArea {
+	values := strings.Fields(t.Frame.TableData.CellAreas)
+	var areas []CellArea
+	for i := 0; i+3 < len(values); i += 4 {
+		var v [4]int
+		for j := range v {
+			v[j], _ = strconv.Atoi(values[i+j])
+		}
+		areas = append(areas, CellArea{Row: v[0], Column: v[1], Rows: v[2], Columns: v[3]})
+	}
+	return areas
+}
+
+// MergeCells merges the cells of an area into its first cell. The text of the other cells is
+// removed. Returns error if the area is outside the table or overlaps merged cells
+func (t Table) MergeCells(area CellArea) error {
+	if area.Rows < 1 || area.Columns < 1 || area.Row < 0 || area.Column < 0 ||
+		area.Row+area.Rows > t.Rows() || area.Column+area.Columns > t.Columns() {
+		return fmt.Errorf("cells %+v are outside the table", area)
+	}
+	areas := t.MergedCells()
+	for _, a := range areas {
+		if a.Row < area.Row+area.Rows && area.Row < a.Row+a.Rows && a.Column < area.Column+area.Columns && area.Column < a.Column+a.Columns {
+			return fmt.Errorf("cells %+v overlap merged cells %+v", area, a)
+		}
+	}
+	for i := range t.Frame.TableData.Cell {
+		c := &t.Frame.TableData.Cell[i]
+		row, column := c.position()
+		if area.contains(row, column) && (row != area.Row || column != area.Column) {
+			c.StoryText.SetParagraphs(nil)
+		}
+	}
+	areas = append(areas, area)
+	var values []string
+	for _, a := range areas {
+		values = append(values, strconv.Itoa(a.Row), strconv.Itoa(a.Column), strconv.Itoa(a.Rows), strconv.Itoa(a.Columns))
+	}
+	t.Frame.TableData.CellAreas = strings.Join(values, " ")
+	return nil
+}
+
+// position returns the row and column of the cell
+func (c Cell) position() (int, int) {
+	row, _ := strconv.Atoi(c.Row)
+	column, _ := strconv.Atoi(c.Column)
+	return row, column
+}
+
+// Cell returns the cell at row, column, or the first cell of the merged cells that cover it;
+// nil if there is no such cell
+func (t Table) Cell(row int, column int) *Cell {
+	for _, a := range t.MergedCells() {
+		if a.contains(row, column) {
+			row, column = a.Row, a.Column
+			break
+		}
+	}
+	for i := range t.Frame.TableData.Cell {
+		if r, c := t.Frame.TableData.Cell[i].position(); r == row && c == column {
+			return &t.Frame.TableData.Cell[i]
+		}
+	}
+	return nil
+}
+
+// covered tells whether the cell at row, column is covered by merged cells that start elsewhere
+func (t Table) covered(row int, column int) bool {
+	for _, a := range t.MergedCells() {
+		if a.contains(row, column) && (row != a.Row || column != a.Column) {
+			return true
+		}
+	}
+	return false
+}
+
+// Grid returns the text of the cells by row and column (see StoryText.PlainText); cells covered
+// by merged cells are empty
+func (t Table) Grid() [][]string {
+	grid := make([][]string, t.Rows())
+	for row := range grid {
+		grid[row] = make([]string, t.Columns())
+		for column := range grid[row] {
+			if c := t.Cell(row, column); c != nil && !t.covered(row, column) {
+				grid[row][column] = c.StoryText.PlainText()
+			}
+		}
+	}
+	return grid
+}
+
+// SetGrid replaces the text of the cells with that of grid by row and column; cells that are
+// not in grid keep their text. Returns error if grid has more rows or columns than the table
+func (t Table) SetGrid(grid [][]string) error {
+	cells := make([][][]Paragraph, len(grid))
+	for row := range grid {
+		cells[row] = make([][]Paragraph, len(grid[row]))
+		for column, text := range grid[row] {
+			cells[row][column] = textParagraphs(text)
+		}
+	}
+	return t.SetRichGrid(cells)
+}
+
+// SetRichGrid replaces the text of the cells with the paragraphs of cells by row and column
+// (see SetGrid)
+func (t Table) SetRichGrid(cells [][][]Paragraph) error {
+	for row := range cells {
+		for column, paragraphs := range cells[row] {
+			c := t.Cell(row, column)
+			if c == nil || row >= t.Rows() || column >= t.Columns() {
+				return fmt.Errorf("cell %v, %v not found, the table has %v rows and %v columns", row, column, t.Rows(), t.Columns())
+			}
+			if !t.covered(row, column) {
+				c.StoryText.SetParagraphs(paragraphs)
+			}
+		}
+	}
+	return nil
+}
+
+// SetStyle sets the table style (see TableStyle)
+func (t Table) SetStyle(name string) { t.Frame.TableData.Style = name }
+
+// SetCellStyle sets the cell style (see CellStyle) of the cells of an area
+func (t Table) SetCellStyle(area CellArea, name string) {
+	for i := range t.Frame.TableData.Cell {
+		if area.contains(t.Frame.TableData.Cell[i].position()) {
+			t.Frame.TableData.Cell[i].Style = name
+		}
+	}
+}
+
+// textParagraphs returns the paragraphs of text, in which "\n" separates paragraphs
+func textParagraphs(text string) []Paragraph {
+	if text == "" {
+		return nil
+	}
+	var paragraphs []Paragraph
+	for _, line := range strings.Split(text, "\n") {
+		paragraphs = append(paragraphs, Paragraph{Runs: []ITEXT{NewRun(line)}})
+	}
+	return paragraphs
+}
+
+// AddTable adds a table frame with the text of cells by row and column to the page with the
+// index page, at the position x, y relative to the page. The columns are columnWidth wide
+// and the rows rowHeight high. Returns the table, error
+func (doc *DOCUMENT) AddTable(page int, x float64, y float64, cells [][]string, columnWidth float64, rowHeight float64) (Table, error) {
+	rich := make([][][]Paragraph, len(cells))
+	for row := range cells {
+		rich[row] = make([][]Paragraph, len(cells[row]))
+		for column, text := range cells[row] {
+			rich[row][column] = textParagraphs(text)
+		}
+	}
+	return doc.AddRichTable(page, x, y, rich, columnWidth, rowHeight)
+}
+
+// AddRichTable adds a table frame with the paragraphs of cells by row and column (see AddTable)
+func (doc *DOCUMENT) AddRichTable(page int, x float64, y float64, cells [][][]Paragraph, columnWidth float64, rowHeight float64) (Table, error) {
+	rows, columns := len(cells), 0
+	for _, row := range cells {
+		columns = max(columns, len(row))
+	}
+	if rows == 0 || columns == 0 {
+		return Table{}, fmt.Errorf("a table needs at least one row and one column")
+	}
+	heights, widths := make([]float64, rows), make([]float64, columns)
+	for i := range heights {
+		heights[i] = rowHeight
+	}
+	for i := range widths {
+		widths[i] = columnWidth
+	}
+
+	po := newPageObject("16", columnWidth*float64(columns), rowHeight*float64(rows))
+	data := &TableData{
+		Rows: strconv.Itoa(rows), Columns: strconv.Itoa(columns),
+		RowHeights: formatFloats(heights), ColumnWidths: formatFloats(widths),
+	}
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			c := Cell{Row: strconv.Itoa(row), Column: strconv.Itoa(column)}
+			if column < len(cells[row]) {
+				c.StoryText.SetParagraphs(cells[row][column])
+			}
+			data.Cell = append(data.Cell, c)
+		}
+	}
+	po.TableData = data
+	frame, err := doc.addPageObject(page, x, y, po)
+	if err != nil {
+		return Table{}, err
+	}
+	return Table{Frame: frame}, nil
+}
@@ -0,0 +1,82 @@
+package scribus
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTables(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := &document.DOCUMENT
+	if _, err := doc.PAGEOBJECT[0].Table(); err == nil {
+		t.Errorf("Table() of a text frame should be an error")
+	}
+	if _, err := doc.AddTable(5, 0, 0, [][]string{{"a"}}, 10, 10); err == nil {
+		t.Errorf("AddTable() on a missing page should be an error")
+	}
+
+	cells := [][]string{{"Name", "Price", "Stock"}, {"Pen", "1.50", "12"}, {"Ink", "4.00"}}
+	table, err := doc.AddTable(0, 40, 100, cells, 80, 20)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	frame := table.Frame
+	if frame.PTYPE != "16" || frame.WIDTH != "240" || frame.HEIGHT != "60" || frame.XPOS != "140" || frame.YPOS != "120" || frame.OwnPage != "0" {
+		t.Errorf("AddTable() was incorrect, got: %v, %v x %v at %v, %v", frame.PTYPE, frame.WIDTH, frame.HEIGHT, frame.XPOS, frame.YPOS)
+	}
+	want := [][]string{{"Name", "Price", "Stock"}, {"Pen", "1.50", "12"}, {"Ink", "4.00", ""}}
+	if got := table.Grid(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Grid() was incorrect, got: %q, want: %q.", got, want)
+	}
+
+	if err := table.SetColumnWidth(0, 120); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if err := table.SetRowHeight(3, 10); err == nil {
+		t.Errorf("SetRowHeight() of a missing row should be an error")
+	}
+	if frame.TableData.ColumnWidths != "120 80 80" || frame.WIDTH != "280" {
+		t.Errorf("SetColumnWidth() was incorrect, got: %v, %v", frame.TableData.ColumnWidths, frame.WIDTH)
+	}
+
+	if err := table.MergeCells(CellArea{Row: 2, Column: 1, Rows: 1, Columns: 2}); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if err := table.MergeCells(CellArea{Row: 1, Column: 2, Rows: 2, Columns: 1}); err == nil {
+		t.Errorf("MergeCells() of overlapping cells should be an error")
+	}
+	if table.Cell(2, 2) != table.Cell(2, 1) || frame.TableData.CellAreas != "2 1 1 2" {
+		t.Errorf("MergeCells() was incorrect, got: %v", frame.TableData.CellAreas)
+	}
+	if err := table.SetGrid([][]string{{"Item"}, {}, {"Paper", "Sold out", "ignored"}}); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	table.SetStyle("Prices")
+	table.SetCellStyle(CellArea{Row: 0, Column: 0, Rows: 1, Columns: 3}, "Header")
+
+	// The table survives a round trip through XML
+	clone, err := frame.Clone()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	copied, err := clone.Table()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	want = [][]string{{"Item", "Price", "Stock"}, {"Pen", "1.50", "12"}, {"Paper", "Sold out", ""}}
+	if got := copied.Grid(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Grid() was incorrect, got: %q, want: %q.", got, want)
+	}
+	if copied.Rows() != 3 || copied.Columns() != 3 || copied.Frame.TableData.Style != "Prices" || copied.Cell(0, 2).Style != "Header" || copied.Cell(1, 0).Style != "" {
+		t.Errorf("styles were incorrect, got: %+v", copied.Frame.TableData)
+	}
+	if got := copied.MergedCells(); !reflect.DeepEqual(got, []CellArea{{Row: 2, Column: 1, Rows: 1, Columns: 2}}) {
+		t.Errorf("MergedCells() was incorrect, got: %v", got)
+	}
+	if len(doc.Tables()) != 1 {
+		t.Errorf("Tables() was incorrect, got: %v", len(doc.Tables()))
+	}
+}
@@ -0,0 +1,349 @@
+package scribus
+
+import (
+	"fmt"
+)
+
+// NewTableBorderLine returns a solid border line with the width in points and the colour
+func NewTableBorderLine(width float64, color string) TableBorderLine {
+	return TableBorderLine{Width: formatFloat(width), PenStyle: PenSolid, Color: color, Shade: "100"}
+}
+
+// NewTableBorder returns a border drawn with the lines
+func NewTableBorder(lines ...TableBorderLine) *TableBorder {
+	return &TableBorder{TableBorderLine: append([]TableBorderLine(nil), lines...)}
+}
+
+// Lines returns the lines of the border
+func (b TableBorder) Lines() []TableBorderLine { return b.TableBorderLine }
+
+// AddLine appends a line to the border
+func (b *TableBorder) AddLine(line TableBorderLine) {
+	b.TableBorderLine = append(b.TableBorderLine, line)
+}
+
+// SetLine replaces the i'th line of the border, returns error if there is no such line
+func (b *TableBorder) SetLine(i int, line TableBorderLine) error {
+	if i < 0 || i >= len(b.TableBorderLine) {
+		return fmt.Errorf("line %v not found, the border has %v lines", i, len(b.TableBorderLine))
+	}
+	b.TableBorderLine[i] = line
+	return nil
+}
+
+// RemoveLine removes the i'th line of the border, returns error if there is no such line
+func (b *TableBorder) RemoveLine(i int) error {
+	if i < 0 || i >= len(b.TableBorderLine) {
+		return fmt.Errorf("line %v not found, the border has %v lines", i, len(b.TableBorderLine))
+	}
+	b.TableBorderLine = append(b.TableBorderLine[:i], b.TableBorderLine[i+1:]...)
+	return nil
+}
+
+// copyBorder returns a copy of the border that does not share its lines, nil for nil
+func copyBorder(b *TableBorder) *TableBorder {
+	if b == nil {
+		return nil
+	}
+	return NewTableBorder(b.TableBorderLine...)
+}
+
+// borderSides returns the borders of a table or cell keyed by the name of their element
+func borderSides(left, right, top, bottom **TableBorder) map[string]**TableBorder {
+	return map[string]**TableBorder{
+		"TableBorderLeft": left, "TableBorderRight": right, "TableBorderTop": top, "TableBorderBottom": bottom,
+	}
+}
+
+// Borders returns the borders of the style keyed by the name of their element, e.g.,
+// TableBorderLeft; borders that are inherited are nil
+func (s *TableStyle) Borders() map[string]**TableBorder {
+	return borderSides(&s.TableBorderLeft, &s.TableBorderRight, &s.TableBorderTop, &s.TableBorderBottom)
+}
+
+// SetBorders sets all four borders of the style to copies of b; nil inherits them
+func (s *TableStyle) SetBorders(b *TableBorder) { setBorders(s.Borders(), b) }
+
+// Borders returns the borders of the style keyed by the name of their element (see TableStyle)
+func (s *CellStyle) Borders() map[string]**TableBorder {
+	return borderSides(&s.TableBorderLeft, &s.TableBorderRight, &s.TableBorderTop, &s.TableBorderBottom)
+}
+
+// SetBorders sets all four borders of the style to copies of b; nil inherits them
+func (s *CellStyle) SetBorders(b *TableBorder) { setBorders(s.Borders(), b) }
+
+// Borders returns the borders of the table keyed by the name of their element; borders
+// that come from the table style are nil
+func (t *TableData) Borders() map[string]**TableBorder {
+	return borderSides(&t.TableBorderLeft, &t.TableBorderRight, &t.TableBorderTop, &t.TableBorderBottom)
+}
+
+// SetBorders sets all four borders of the table to copies of b; nil uses those of the style
+func (t *TableData) SetBorders(b *TableBorder) { setBorders(t.Borders(), b) }
+
+// Borders returns the borders of the cell keyed by the name of their element; borders
+// that come from the cell style are nil
+func (c *Cell) Borders() map[string]**TableBorder {
+	return borderSides(&c.TableBorderLeft, &c.TableBorderRight, &c.TableBorderTop, &c.TableBorderBottom)
+}
+
+// SetBorders sets all four borders of the cell to copies of b; nil uses those of the style
+func (c *Cell) SetBorders(b *TableBorder) { setBorders(c.Borders(), b) }
+
+// setBorders sets the borders to copies of b
+func setBorders(borders map[string]**TableBorder, b *TableBorder) {
+	for _, border := range borders {
+		*border = copyBorder(b)
+	}
+}
+
+// inherit sets the attribute to that of the parent if it is not set
+func inherit(attr *string, parent string) {
+	if *attr == "" {
+		*attr = parent
+	}
+}
+
+// inheritBorders sets the borders that are not set to copies of those of the parent
+func inheritBorders(borders, parent map[string]**TableBorder) {
+	for side, border := range borders {
+		if *border == nil {
+			*border = copyBorder(*parent[side])
+		}
+	}
+}
+
+// styleChain returns the names of a style and its ancestors up to the default style, which
+// styles without PARENT inherit from. Returns error if a style is missing or inherits from
+// itself
+func styleChain(name string, defaultName string, parent func(name string) (string, bool)) ([]string, error) {
+	if name == "" {
+		name = defaultName
+	}
+	var chain []string
+	seen := map[string]bool{}
+	for name != "" {
+		if seen[name] {
+			return nil, fmt.Errorf("style %v inherits from itself", name)
+		}
+		seen[name] = true
+		p, ok := parent(name)
+		if !ok {
+			return nil, fmt.Errorf("style %v not found", name)
+		}
+		chain = append(chain, name)
+		if p == "" && name != defaultName {
+			if _, ok := parent(defaultName); ok {
+				p = defaultName
+			}
+		}
+		name = p
+	}
+	return chain, nil
+}
+
+// GetTableStyle returns a pointer to the table style with the given name, or nil
+func (doc DOCUMENT) GetTableStyle(name string) *TableStyle {
+	for i := range doc.TableStyle {
+		if doc.TableStyle[i].NAME == name {
+			return &doc.TableStyle[i]
+		}
+	}
+	return nil
+}
+
+// tableStyleParent returns the PARENT of the table style with the name, and whether it exists
+func (doc DOCUMENT) tableStyleParent(name string) (string, bool) {
+	if s := doc.GetTableStyle(name); s != nil {
+		return s.PARENT, true
+	}
+	return "", false
+}
+
+// ResolveTableStyle returns the table style with the given name ("" for the default style)
+// with the attributes and borders it inherits, error
+func (doc DOCUMENT) ResolveTableStyle(name string) (TableStyle, error) {
+	chain, err := styleChain(name, DefaultTableStyle, doc.tableStyleParent)
+	if err != nil {
+		return TableStyle{}, err
+	}
+	style := *doc.GetTableStyle(chain[0])
+	style.SetBorders(nil)
+	for _, n := range chain {
+		s := doc.GetTableStyle(n)
+		inherit(&style.FillColor, s.FillColor)
+		inherit(&style.FillShade, s.FillShade)
+		inheritBorders(style.Borders(), s.Borders())
+	}
+	return style, nil
+}
+
+// AddTableStyle adds a table style that inherits from parent ("" for the default style)
+// and returns a pointer to it, error
+func (doc *DOCUMENT) AddTableStyle(name string, parent string) (*TableStyle, error) {
+	if name == "" || doc.GetTableStyle(name) != nil {
+		return nil, fmt.Errorf("table style %q already exists", name)
+	}
+	if parent != "" && doc.GetTableStyle(parent) == nil {
+		return nil, fmt.Errorf("table style %v not found", parent)
+	}
+	doc.TableStyle = append(doc.TableStyle, TableStyle{NAME: name, PARENT: parent, DefaultStyle: "0"})
+	return &doc.TableStyle[len(doc.TableStyle)-1], nil
+}
+
+// RenameTableStyle renames a table style and updates the styles and tables that refer to
+// it, returns error
+func (doc *DOCUMENT) RenameTableStyle(name string, newName string) error {
+	s := doc.GetTableStyle(name)
+	if s == nil {
+		return fmt.Errorf("table style %v not found", name)
+	}
+	if name != newName && (newName == "" || doc.GetTableStyle(newName) != nil) {
+		return fmt.Errorf("table style %q already exists", newName)
+	}
+	s.NAME = newName
+	doc.replaceTableStyle(name, newName)
+	return nil
+}
+
+// DeleteTableStyle deletes a table style. The styles and tables that refer to it get its
+// parent instead. The default style cannot be deleted. Returns error
+func (doc *DOCUMENT) DeleteTableStyle(name string) error {
+	s := doc.GetTableStyle(name)
+	if s == nil {
+		return fmt.Errorf("table style %v not found", name)
+	}
+	if s.DefaultStyle == "1" {
+		return fmt.Errorf("cannot delete the default table style %v", name)
+	}
+	parent := s.PARENT
+	styles := doc.TableStyle[:0]
+	for _, style := range doc.TableStyle {
+		if style.NAME != name {
+			styles = append(styles, style)
+		}
+	}
+	doc.TableStyle = styles
+	doc.replaceTableStyle(name, parent)
+	return nil
+}
+
+// replaceTableStyle replaces references to the table style name with newName
+func (doc *DOCUMENT) replaceTableStyle(name string, newName string) {
+	for i := range doc.TableStyle {
+		if doc.TableStyle[i].PARENT == name {
+			doc.TableStyle[i].PARENT = newName
+		}
+	}
+	for _, t := range doc.Tables() {
+		if t.Frame.TableData.Style == name {
+			t.Frame.TableData.Style = newName
+		}
+	}
+}
+
+// GetCellStyle returns a pointer to the cell style with the given name, or nil
+func (doc DOCUMENT) GetCellStyle(name string) *CellStyle {
+	for i := range doc.CellStyle {
+		if doc.CellStyle[i].NAME == name {
+			return &doc.CellStyle[i]
+		}
+	}
+	return nil
+}
+
+// cellStyleParent returns the PARENT of the cell style with the name, and whether it exists
+func (doc DOCUMENT) cellStyleParent(name string) (string, bool) {
+	if s := doc.GetCellStyle(name); s != nil {
+		return s.PARENT, true
+	}
+	return "", false
+}
+
+// ResolveCellStyle returns the cell style with the given name ("" for the default style)
+// with the attributes and borders it inherits, error
+func (doc DOCUMENT) ResolveCellStyle(name string) (CellStyle, error) {
+	chain, err := styleChain(name, DefaultCellStyle, doc.cellStyleParent)
+	if err != nil {
+		return CellStyle{}, err
+	}
+	style := *doc.GetCellStyle(chain[0])
+	style.SetBorders(nil)
+	for _, n := range chain {
+		s := doc.GetCellStyle(n)
+		inherit(&style.FillColor, s.FillColor)
+		inherit(&style.FillShade, s.FillShade)
+		inherit(&style.LeftPadding, s.LeftPadding)
+		inherit(&style.RightPadding, s.RightPadding)
+		inherit(&style.TopPadding, s.TopPadding)
+		inherit(&style.BottomPadding, s.BottomPadding)
+		inheritBorders(style.Borders(), s.Borders())
+	}
+	return style, nil
+}
+
+// AddCellStyle adds a cell style that inherits from parent ("" for the default style)
+// and returns a pointer to it, error
+func (doc *DOCUMENT) AddCellStyle(name string, parent string) (*CellStyle, error) {
+	if name == "" || doc.GetCellStyle(name) != nil {
+		return nil, fmt.Errorf("cell style %q already exists", name)
+	}
+	if parent != "" && doc.GetCellStyle(parent) == nil {
+		return nil, fmt.Errorf("cell style %v not found", parent)
+	}
+	doc.CellStyle = append(doc.CellStyle, CellStyle{NAME: name, PARENT: parent, DefaultStyle: "0"})
+	return &doc.CellStyle[len(doc.CellStyle)-1], nil
+}
+
+// RenameCellStyle renames a cell style and updates the styles and cells that refer to it,
+// returns error
+func (doc *DOCUMENT) RenameCellStyle(name string, newName string) error {
+	s := doc.GetCellStyle(name)
+	if s == nil {
+		return fmt.Errorf("cell style %v not found", name)
+	}
+	if name != newName && (newName == "" || doc.GetCellStyle(newName) != nil) {
+		return fmt.Errorf("cell style %q already exists", newName)
+	}
+	s.NAME = newName
+	doc.replaceCellStyle(name, newName)
+	return nil
+}
+
+// DeleteCellStyle deletes a cell style. The styles and cells that refer to it get its
+// parent instead. The default style cannot be deleted. Returns error
+func (doc *DOCUMENT) DeleteCellStyle(name string) error {
+	s := doc.GetCellStyle(name)
+	if s == nil {
+		return fmt.Errorf("cell style %v not found", name)
+	}
+	if s.DefaultStyle == "1" {
+		return fmt.Errorf("cannot delete the default cell style %v", name)
+	}
+	parent := s.PARENT
+	styles := doc.CellStyle[:0]
+	for _, style := range doc.CellStyle {
+		if style.NAME != name {
+			styles = append(styles, style)
+		}
+	}
+	doc.CellStyle = styles
+	doc.replaceCellStyle(name, parent)
+	return nil
+}
+
+// replaceCellStyle replaces references to the cell style name with newName
+func (doc *DOCUMENT) replaceCellStyle(name string, newName string) {
+	for i := range doc.CellStyle {
+		if doc.CellStyle[i].PARENT == name {
+			doc.CellStyle[i].PARENT = newName
+		}
+	}
+	for _, t := range doc.Tables() {
+		for i := range t.Frame.TableData.Cell {
+			if t.Frame.TableData.Cell[i].Style == name {
+				t.Frame.TableData.Cell[i].Style = newName
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package scribus
+
+import (
+	"testing"
+)
+
+func TestTableStyles(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := &document.DOCUMENT
+	if len(doc.TableStyle) != 1 || len(doc.CellStyle) != 1 || len(doc.GetTableStyle(DefaultTableStyle).TableBorderTop.Lines()) != 1 {
+		t.Fatalf("table and cell styles were read incorrectly, got: %+v, %+v", doc.TableStyle, doc.CellStyle)
+	}
+
+	prices, err := doc.AddTableStyle("Prices", "")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	prices.FillColor = "White"
+	double := NewTableBorder(NewTableBorderLine(2, "Black"), NewTableBorderLine(0.5, "Red"))
+	prices.TableBorderTop = double
+	if _, err := doc.AddTableStyle("Summary", "Prices"); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if _, err := doc.AddTableStyle("Prices", ""); err == nil {
+		t.Errorf("AddTableStyle() of an existing style should be an error")
+	}
+	if _, err := doc.AddTableStyle("Other", "Missing"); err == nil {
+		t.Errorf("AddTableStyle() with a missing parent should be an error")
+	}
+
+	summary, err := doc.ResolveTableStyle("Summary")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	// FillColor and the top border come from Prices, FillShade and the other borders from the default style
+	if summary.NAME != "Summary" || summary.FillColor != "White" || summary.FillShade != "100" || len(summary.TableBorderTop.Lines()) != 2 || summary.TableBorderLeft.Lines()[0].Width != "1" {
+		t.Errorf("ResolveTableStyle() was incorrect, got: %+v", summary)
+	}
+	summary.TableBorderTop.TableBorderLine[0].Color = "Blue"
+	if double.TableBorderLine[0].Color != "Black" {
+		t.Errorf("ResolveTableStyle() should copy the borders")
+	}
+
+	if err := double.SetLine(1, NewTableBorderLine(1, "Blue")); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if err := double.RemoveLine(0); err != nil || len(double.Lines()) != 1 || double.Lines()[0].Color != "Blue" {
+		t.Errorf("RemoveLine() was incorrect, got: %v, %v", double.Lines(), err)
+	}
+	if err := double.RemoveLine(3); err == nil {
+		t.Errorf("RemoveLine() of a missing line should be an error")
+	}
+
+	table, err := doc.AddTable(0, 0, 0, [][]string{{"a", "b"}}, 50, 20)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	table.SetStyle("Prices")
+	if err := doc.RenameTableStyle("Prices", "Price list"); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if table.Frame.TableData.Style != "Price list" || doc.GetTableStyle("Summary").PARENT != "Price list" {
+		t.Errorf("RenameTableStyle() was incorrect, got: %v, %v", table.Frame.TableData.Style, doc.GetTableStyle("Summary").PARENT)
+	}
+	if err := doc.DeleteTableStyle("Price list"); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if table.Frame.TableData.Style != "" || doc.GetTableStyle("Summary").PARENT != "" || len(doc.TableStyle) != 2 {
+		t.Errorf("DeleteTableStyle() was incorrect, got: %+v", doc.TableStyle)
+	}
+	if err := doc.DeleteTableStyle(DefaultTableStyle); err == nil {
+		t.Errorf("DeleteTableStyle() of the default style should be an error")
+	}
+
+	header, err := doc.AddCellStyle("Header", "")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	header.FillColor = "Red"
+	header.TopPadding = "4"
+	header.SetBorders(NewTableBorder(NewTableBorderLine(0.5, "White")))
+	header.PARENT = "Header"
+	if _, err := doc.ResolveCellStyle("Header"); err == nil {
+		t.Errorf("ResolveCellStyle() of a style that inherits from itself should be an error")
+	}
+	header.PARENT = ""
+	resolved, err := doc.ResolveCellStyle("Header")
+	if err != nil || resolved.TopPadding != "4" || resolved.LeftPadding != "1" || resolved.TableBorderBottom.Lines()[0].Color != "White" {
+		t.Errorf("ResolveCellStyle() was incorrect, got: %+v, %v", resolved, err)
+	}
+	table.SetCellStyle(CellArea{Rows: 1, Columns: 1}, "Header")
+	if err := doc.DeleteCellStyle("Header"); err != nil || table.Cell(0, 0).Style != "" {
+		t.Errorf("DeleteCellStyle() was incorrect, got: %v, %v", table.Cell(0, 0).Style, err)
+	}
+
+	usage := doc.ColorUsage()
+	styles := 0
+	for _, ref := range usage["Black"] {
+		if ref.Kind == "TableStyle" || ref.Kind == "CellStyle" {
+			styles++
+		}
+	}
+	if len(usage["Red"]) != 0 || len(usage["Blue"]) != 0 || styles != 8 {
+		t.Errorf("ColorUsage() was incorrect, got: %v, %v", usage["Red"], styles)
+	}
+}
@@ -0,0 +1,83 @@
+package scribus
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TabAlignment is the alignment of the text at a tab stop (Tabs Type)
+type TabAlignment int
+
+const (
+	TabLeft         TabAlignment = iota // Text starts at the tab stop
+	TabRight                            // Text ends at the tab stop
+	TabDecimalPoint                     // The first "." of the text is at the tab stop
+	TabDecimalComma                     // The first "," of the text is at the tab stop
+	TabCenter                           // Text is centred on the tab stop
+)
+
+// TabStop is a tab stop of a paragraph style or paragraph
+type TabStop struct {
+	Position  float64 // Distance from the left edge of the column in points
+	Alignment TabAlignment
+	Fill      string // Character that fills the space before the tab stop, e.g., "."; none if empty
+}
+
+// tabStops converts Tabs elements to TabStops
+func tabStops(tabs []Tabs) []TabStop {
+	var stops []TabStop
+	for _, t := range tabs {
+		alignment, _ := strconv.Atoi(t.Type)
+		stops = append(stops, TabStop{Position: parseFloat(t.Pos), Alignment: TabAlignment(alignment), Fill: t.Fill})
+	}
+	return stops
+}
+
+// tabsElements converts TabStops to Tabs elements
+func tabsElements(stops []TabStop) []Tabs {
+	var tabs []Tabs
+	for _, s := range stops {
+		tabs = append(tabs, Tabs{Type: strconv.Itoa(int(s.Alignment)), Pos: formatFloat(s.Position), Fill: s.Fill})
+	}
+	return tabs
+}
+
+// TabStops returns the tab stops of the paragraph style
+func (s STYLE) TabStops() []TabStop { return tabStops(s.Tabs) }
+
+// SetTabStops replaces the tab stops of the paragraph style
+func (s *STYLE) SetTabStops(stops []TabStop) { s.Tabs = tabsElements(stops) }
+
+// TabStops returns the tab stops of the paragraph, which override those of its style
+func (p Para) TabStops() []TabStop { return tabStops(p.Tabs) }
+
+// SetTabStops replaces the tab stops of the paragraph
+func (p *Para) SetTabStops(stops []TabStop) { p.Tabs = tabsElements(stops) }
+
+// TabbedParagraphs returns one paragraph per row, in which the cells are separated by tabs.
+// The paragraphs get style with the tab stops and their text gets the attributes of format
+func TabbedParagraphs(rows [][]string, stops []TabStop, style Para, format ITEXT) []Paragraph {
+	style.SetTabStops(stops)
+	paragraphs := make([]Paragraph, len(rows))
+	for i, row := range rows {
+		run := format
+		run.CH = strings.Join(row, "\t")
+		paragraphs[i] = Paragraph{Runs: []ITEXT{run}, Style: style}
+	}
+	return paragraphs
+}
+
+// SetTabbedRows replaces the text of the StoryText with one paragraph per row, in which the
+// cells are separated by tabs and aligned by stops (see TabbedParagraphs)
+func (st *StoryText) SetTabbedRows(rows [][]string, stops []TabStop) {
+	st.SetParagraphs(TabbedParagraphs(rows, stops, Para{}, NewRun("")))
+}
+
+// TabbedRows returns the paragraphs of the StoryText split into cells at the tabs
+func (st StoryText) TabbedRows() [][]string {
+	var rows [][]string
+	for _, p := range st.Paragraphs() {
+		rows = append(rows, strings.Split(p.Text(), "\t"))
+	}
+	return rows
+}
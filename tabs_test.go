@@ -0,0 +1,39 @@
+package scribus
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTabStops(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	stops := []TabStop{{Position: 200, Alignment: TabLeft}, {Position: 320.5, Alignment: TabDecimalPoint, Fill: "."}}
+	style := document.DOCUMENT.EnsureParagraphStyle("Price List")
+	style.SetTabStops(stops)
+	rows := [][]string{{"Apples", "1 kg", "2.50"}, {"Pears", "500 g", "12.00"}}
+	document.DOCUMENT.PAGEOBJECT[0].StoryText.SetTabbedRows(rows, stops)
+
+	clone, err := document.Clone()
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if got := clone.DOCUMENT.GetParagraphStyle("Price List").TabStops(); !reflect.DeepEqual(got, stops) {
+		t.Errorf("STYLE.TabStops() was incorrect, got: %+v, want: %+v.", got, stops)
+	}
+	st := clone.DOCUMENT.PAGEOBJECT[0].StoryText
+	if got := st.TabbedRows(); !reflect.DeepEqual(got, rows) {
+		t.Errorf("TabbedRows() was incorrect, got: %q, want: %q.", got, rows)
+	}
+	paragraphs := st.Paragraphs()
+	for _, p := range paragraphs {
+		if got := p.Style.TabStops(); !reflect.DeepEqual(got, stops) {
+			t.Errorf("Para.TabStops() was incorrect, got: %+v, want: %+v.", got, stops)
+		}
+	}
+	if len(st.Elements) != 4 || st.Elements[0].XMLName.Local != "tab" {
+		t.Errorf("tab elements were incorrect, got: %+v", st.Elements)
+	}
+}
@@ -0,0 +1,496 @@
+package scribus
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// TemplateFilter converts the value of a placeholder. value is nil for fields without
+// value; arg is the text after the colon, e.g., "2" in {{total | number:2}}
+type TemplateFilter func(value interface{}, arg string) (interface{}, error)
+
+// TemplateOptions configures FillTemplate
+type TemplateOptions struct {
+	Filters            map[string]TemplateFilter // Filters in addition to (or instead of) the built-in ones
+	DateFormat         string                    // Layout of dates (see package time), "2006-01-02" if empty
+	DecimalSeparator   string                    // Used by the number filter, "." if empty
+	ThousandsSeparator string                    // Used by the number filter, e.g., ","
+	RemoveUnresolved   bool                      // Remove placeholders without value instead of leaving them in place
+}
+
+// TemplateReport tells how FillTemplate went
+type TemplateReport struct {
+	Filled     int      // Number of placeholders that were filled
+	Unresolved []string // Fields of placeholders without value, sorted
+	Unused     []string // Fields of the data that no placeholder refers to, sorted
+}
+
+// placeholderPattern matches placeholders such as {{customer.name | upper}}
+var placeholderPattern = regexp.MustCompile(`\{\{([^{}\n]*)\}\}`)
+
+// placeholder is a parsed placeholder
+type placeholder struct {
+	field   string
+	filters []placeholderFilter
+}
+
+type placeholderFilter struct {
+	name string
+	arg  string
+}
+
+// parsePlaceholder parses the text between the braces of a placeholder
+func parsePlaceholder(expr string) placeholder {
+	parts := splitUnquoted(expr, '|')
+	p := placeholder{field: strings.TrimSpace(parts[0])}
+	for _, part := range parts[1:] {
+		name, arg, _ := strings.Cut(part, ":")
+		arg = strings.TrimSpace(arg)
+		if unquoted, err := strconv.Unquote(arg); err == nil {
+			arg = unquoted
+		}
+		p.filters = append(p.filters, placeholderFilter{name: strings.TrimSpace(name), arg: arg})
+	}
+	return p
+}
+
+// splitUnquoted splits s at sep, except inside double quotes
+func splitUnquoted(s string, sep rune) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i, r := range s {
+		switch {
+		case r == '"' && (i == 0 || s[i-1] != '\\'):
+			quoted = !quoted
+		case r == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// templateFiller fills the placeholders of a document with data
+type templateFiller struct {
+	data       reflect.Value
+	options    TemplateOptions
+	filters    map[string]TemplateFilter
+	used       map[string]bool
+	unresolved map[string]bool
+	aliases    map[string]string // Paths of the elements of repeated lists by name
+	deleted    bool              // Whether rules deleted items
+	overflow   map[string][]PAGE // Pages added for repeated items by the number of the page they flow from
+	nextID     int               // Next ItemID for repeated items, 0 until one is needed
+	filled     int
+	errs       []error
+}
+
+// FillTemplate replaces the placeholders such as {{customer.name}} in the text of all frames
+// (also across ITEXTs), in the names (ANNAME) of items and in the values of item attributes
+// with the values of data, which is a map with string keys or a struct (exported fields, or
+// their json tag names) that may contain further maps, structs and slices ({{items.0.name}}).
+// Filters convert values, e.g., {{name | upper}}, {{date | date:"02.01.2006"}} or
+// {{total | number:2}}; there are also lower, title, trim and default:"text" for fields without
+// value. Image frames named {{field}}, or named like a field, get the value as their PFILE.
+// Items may have rules that delete them, hide them, change their layer or colours depending on
+// the data, or repeat them per element of a list (see RuleIf and the other rules); the rules are
+// applied before the placeholders are filled and removed from the items. Placeholders with unknown
+// filters or filters that fail, and rules that cannot be applied, are returned as error
+func (doc *DOCUMENT) FillTemplate(data interface{}, options TemplateOptions) (TemplateReport, error) {
+	tf := &templateFiller{
+		data:       reflect.ValueOf(data),
+		options:    options,
+		filters:    templateFilters(options),
+		used:       map[string]bool{},
+		unresolved: map[string]bool{},
+		aliases:    map[string]string{},
+		overflow:   map[string][]PAGE{},
+	}
+	doc.MASTEROBJECT = tf.fillItems(doc, doc.MASTEROBJECT)
+	var objs []PAGEOBJECT
+	for _, po := range doc.PAGEOBJECT {
+		if arg, ok := ruleArg(po, RuleRepeat); ok {
+			objs = append(objs, tf.repeatItem(doc, po, arg)...)
+		} else {
+			objs = append(objs, tf.fillItems(doc, []PAGEOBJECT{po})...)
+		}
+	}
+	doc.PAGEOBJECT = objs
+	if tf.deleted {
+		doc.RepairChains()
+	}
+
+	report := TemplateReport{Filled: tf.filled, Unresolved: sortedKeys(tf.unresolved)}
+	for _, leaf := range templateLeaves(tf.data, "") {
+		if !tf.isUsed(leaf) {
+			report.Unused = append(report.Unused, leaf)
+		}
+	}
+	sort.Strings(report.Unused)
+	return report, errors.Join(tf.errs...)
+}
+
+// fillPageObject fills the placeholders of an item
+func (tf *templateFiller) fillPageObject(po *PAGEOBJECT) {
+	if po.PTYPE == "2" {
+		name := po.ANNAME
+		if m := placeholderPattern.FindStringSubmatch(name); m != nil && m[0] == name {
+			if value, ok := tf.expand(m[1]); ok {
+				po.PFILE = value
+			}
+		} else if name != "" {
+			if _, _, ok := tf.lookup(name); ok {
+				if value, ok := tf.expand(name); ok {
+					po.PFILE = value
+				}
+			}
+		}
+	} else {
+		po.ANNAME = tf.fillString(po.ANNAME)
+	}
+	if po.PageItemAttributes != nil {
+		for i := range po.PageItemAttributes.ItemAttribute {
+			attr := &po.PageItemAttributes.ItemAttribute[i]
+			attr.Value = tf.fillString(attr.Value)
+		}
+	}
+
+	text := po.StoryText.PlainText()
+	var matches []TextMatch
+	for _, m := range placeholderPattern.FindAllStringSubmatchIndex(text, -1) {
+		if value, ok := tf.expand(text[m[2]:m[3]]); ok {
+			matches = append(matches, TextMatch{Start: m[0], End: m[1], Replacement: value})
+		}
+	}
+	po.StoryText.replaceMatches(matches)
+}
+
+// fillString replaces the placeholders in s
+func (tf *templateFiller) fillString(s string) string {
+	return placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if value, ok := tf.expand(match[2 : len(match)-2]); ok {
+			return value
+		}
+		return match
+	})
+}
+
+// expand returns the text for the placeholder expression expr and whether the placeholder is
+// to be replaced
+func (tf *templateFiller) expand(expr string) (string, bool) {
+	p := parsePlaceholder(expr)
+	value, path, found := tf.lookup(p.field)
+	if found {
+		tf.used[path] = true
+	}
+	hasDefault := false
+	for _, f := range p.filters {
+		hasDefault = hasDefault || f.name == "default"
+	}
+	if !found && !hasDefault {
+		tf.unresolved[p.field] = true
+		return "", tf.options.RemoveUnresolved
+	}
+
+	for _, f := range p.filters {
+		filter, ok := tf.filters[f.name]
+		if !ok {
+			tf.errs = append(tf.errs, fmt.Errorf("{{%v}}: unknown filter %v", expr, f.name))
+			return "", false
+		}
+		var err error
+		if value, err = filter(value, f.arg); err != nil {
+			tf.errs = append(tf.errs, fmt.Errorf("{{%v}}: %v: %w", expr, f.name, err))
+			return "", false
+		}
+	}
+	tf.filled++
+	return templateString(value, tf.options), true
+}
+
+// lookup returns the value of the field with the dotted path, the path with the names
+// used in the data, and whether the field was found
+func (tf *templateFiller) lookup(field string) (interface{}, string, bool) {
+	if field == "" {
+		return nil, "", false
+	}
+	v := tf.data
+	var names []string
+	path := strings.Split(field, ".")
+	if alias, ok := tf.aliases[path[0]]; ok {
+		path = append(strings.Split(alias, "."), path[1:]...)
+	}
+	for _, name := range path {
+		v = indirect(v)
+		switch v.Kind() {
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return nil, "", false
+			}
+			v = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		case reflect.Struct:
+			var ok bool
+			if v, name, ok = structField(v, name); !ok {
+				return nil, "", false
+			}
+		case reflect.Slice, reflect.Array:
+			i, err := strconv.Atoi(name)
+			if err != nil || i < 0 || i >= v.Len() {
+				return nil, "", false
+			}
+			v = v.Index(i)
+		default:
+			return nil, "", false
+		}
+		if !v.IsValid() {
+			return nil, "", false
+		}
+		names = append(names, name)
+	}
+	v = indirect(v)
+	if !v.IsValid() {
+		return nil, strings.Join(names, "."), true
+	}
+	return v.Interface(), strings.Join(names, "."), true
+}
+
+// isUsed tells whether a placeholder refers to the field with path, to a field inside
+// it, or to a field that contains it
+func (tf *templateFiller) isUsed(path string) bool {
+	for used := range tf.used {
+		if used == path || strings.HasPrefix(path, used+".") || strings.HasPrefix(used, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// indirect follows pointers and interfaces
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		v = v.Elem()
+	}
+	return v
+}
+
+// templateFieldName returns the name of a struct field in placeholders, or "" if it cannot be used
+func templateFieldName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	switch tag {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+	return tag
+}
+
+// structField returns the field of the struct v with name, which is compared with the
+// json tag name or, ignoring case, with the field name
+func structField(v reflect.Value, name string) (reflect.Value, string, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fieldName := templateFieldName(t.Field(i))
+		if fieldName != "" && (fieldName == name || strings.EqualFold(fieldName, name)) {
+			return v.Field(i), fieldName, true
+		}
+	}
+	return reflect.Value{}, "", false
+}
+
+// templateLeaves returns the paths of the fields of v that contain no further fields;
+// slices count as single fields
+func templateLeaves(v reflect.Value, prefix string) []string {
+	join := func(name string) string {
+		if prefix == "" {
+			return name
+		}
+		return prefix + "." + name
+	}
+	v = indirect(v)
+	var leaves []string
+	switch {
+	case !v.IsValid():
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		for _, key := range v.MapKeys() {
+			leaves = append(leaves, templateLeaves(v.MapIndex(key), join(key.String()))...)
+		}
+	case v.Kind() == reflect.Struct && v.Type() != reflect.TypeOf(time.Time{}):
+		for i := 0; i < v.NumField(); i++ {
+			if name := templateFieldName(v.Type().Field(i)); name != "" {
+				leaves = append(leaves, templateLeaves(v.Field(i), join(name))...)
+			}
+		}
+	case prefix != "":
+		leaves = append(leaves, prefix)
+	}
+	return leaves
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string]bool) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// templateString returns the text for a value
+func templateString(value interface{}, options TemplateOptions) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(dateFormat(options))
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(value)
+}
+
+// dateFormat returns the layout of dates
+func dateFormat(options TemplateOptions) string {
+	if options.DateFormat == "" {
+		return "2006-01-02"
+	}
+	return options.DateFormat
+}
+
+// templateFilters returns the built-in filters and those of options
+func templateFilters(options TemplateOptions) map[string]TemplateFilter {
+	text := func(fn func(string) string) TemplateFilter {
+		return func(value interface{}, arg string) (interface{}, error) {
+			return fn(templateString(value, options)), nil
+		}
+	}
+	filters := map[string]TemplateFilter{
+		"upper": text(strings.ToUpper),
+		"lower": text(strings.ToLower),
+		"trim":  text(strings.TrimSpace),
+		"title": text(titleCase),
+		"default": func(value interface{}, arg string) (interface{}, error) {
+			if templateString(value, options) == "" {
+				return arg, nil
+			}
+			return value, nil
+		},
+		"date": func(value interface{}, arg string) (interface{}, error) {
+			if arg == "" {
+				arg = dateFormat(options)
+			}
+			t, err := templateTime(value)
+			if err != nil {
+				return nil, err
+			}
+			return t.Format(arg), nil
+		},
+		"number": func(value interface{}, arg string) (interface{}, error) {
+			decimals := -1
+			if arg != "" {
+				var err error
+				if decimals, err = strconv.Atoi(arg); err != nil {
+					return nil, fmt.Errorf("invalid number of decimals %q", arg)
+				}
+			}
+			f, err := templateNumber(value)
+			if err != nil {
+				return nil, err
+			}
+			return formatDecimal(f, decimals, options.DecimalSeparator, options.ThousandsSeparator), nil
+		},
+	}
+	for name, filter := range options.Filters {
+		filters[name] = filter
+	}
+	return filters
+}
+
+// titleCase makes the first letter of every word upper case
+func titleCase(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if i == 0 || unicode.IsSpace(runes[i-1]) || runes[i-1] == '-' {
+			runes[i] = unicode.ToTitle(r)
+		}
+	}
+	return string(runes)
+}
+
+// templateTime returns value as time: a time.Time, or a string in RFC 3339 format or
+// in the formats 2006-01-02 and 2006-01-02 15:04:05
+func templateTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		for _, layout := range []string{time.RFC3339, "2006-01-02", "2006-01-02 15:04:05"} {
+			if t, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date", templateString(value, TemplateOptions{}))
+}
+
+// templateNumber returns value as number: a number, or a string with a number
+func templateNumber(value interface{}) (float64, error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64); err == nil {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("%q is not a number", templateString(value, TemplateOptions{}))
+}
+
+// formatDecimal formats f with decimals digits after the decimal separator (as many as
+// needed if decimals is negative), grouping the digits before it with thousands
+func formatDecimal(f float64, decimals int, decimal string, thousands string) string {
+	if decimal == "" {
+		decimal = "."
+	}
+	s := strconv.FormatFloat(f, 'f', decimals, 64)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(s, ".")
+	if thousands != "" {
+		var sb strings.Builder
+		for i, r := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				sb.WriteString(thousands)
+			}
+			sb.WriteRune(r)
+		}
+		whole = sb.String()
+	}
+	if hasFraction {
+		return sign + whole + decimal + fraction
+	}
+	return sign + whole
+}
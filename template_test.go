@@ -0,0 +1,63 @@
+package scribus
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFillTemplate(t *testing.T) {
+	type customer struct {
+		Name  string
+		Since time.Time `json:"since"`
+		Email string
+	}
+	data := map[string]interface{}{
+		"customer": customer{Name: "jane doe", Since: time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)},
+		"total":    1234.5,
+		"photo":    "/images/jane.jpg",
+		"items":    []string{"Pen", "Paper"},
+		"phone":    "555-0100",
+	}
+
+	var doc DOCUMENT
+	bold := ITEXT{FONT: "FreeSans Bold", CH: "name | title}}"}
+	var letter PAGEOBJECT
+	letter.PTYPE = "4"
+	letter.ANNAME = "Letter {{customer.name}}"
+	letter.StoryText.SetParagraphs([]Paragraph{
+		{Runs: []ITEXT{NewRun("Dear {{customer."), bold}},
+		{Runs: []ITEXT{NewRun(`Since {{customer.since | date:"02.01.2006"}}, total {{total | number:2}}, {{items.1 | upper}}`)}},
+		{Runs: []ITEXT{NewRun("{{missing}} {{customer.email | default:\"n/a\"}} {{total | nosuchfilter}}")}},
+	})
+	photo := PAGEOBJECT{PTYPE: "2", ANNAME: "photo"}
+	logo := PAGEOBJECT{PTYPE: "2", ANNAME: "logo", PFILE: "logo.png"}
+	letter.PageItemAttributes = &PageItemAttributes{ItemAttribute: []ItemAttribute{{Name: "id", Value: "{{customer.name | upper}}"}}}
+	doc.PAGEOBJECT = []PAGEOBJECT{letter, photo, logo}
+
+	report, err := doc.FillTemplate(data, TemplateOptions{ThousandsSeparator: ","})
+	if err == nil {
+		t.Errorf("FillTemplate() did not return an error for an unknown filter")
+	}
+	want := TemplateReport{Filled: 8, Unresolved: []string{"missing"}, Unused: []string{"phone"}}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("FillTemplate() was incorrect, got: %+v, want: %+v.", report, want)
+	}
+
+	st := doc.PAGEOBJECT[0].StoryText
+	if text := st.PlainText(); text != "Dear Jane Doe\nSince 01.05.2019, total 1,234.50, PAPER\n{{missing}} n/a {{total | nosuchfilter}}" {
+		t.Errorf("PlainText() was incorrect, got: %q", text)
+	}
+	if doc.PAGEOBJECT[0].ANNAME != "Letter jane doe" || doc.PAGEOBJECT[0].PageItemAttributes.ItemAttribute[0].Value != "JANE DOE" {
+		t.Errorf("names and attributes were incorrect, got: %v, %+v", doc.PAGEOBJECT[0].ANNAME, doc.PAGEOBJECT[0].PageItemAttributes)
+	}
+	if doc.PAGEOBJECT[1].PFILE != "/images/jane.jpg" || doc.PAGEOBJECT[2].PFILE != "logo.png" {
+		t.Errorf("PFILEs were incorrect, got: %v, %v", doc.PAGEOBJECT[1].PFILE, doc.PAGEOBJECT[2].PFILE)
+	}
+
+	report, _ = doc.FillTemplate(data, TemplateOptions{RemoveUnresolved: true})
+	if text := doc.PAGEOBJECT[0].StoryText.PlainText(); report.Filled != 1 || !strings.HasSuffix(text, "\n n/a {{total | nosuchfilter}}") {
+		t.Errorf("FillTemplate() with RemoveUnresolved was incorrect, got: %+v, %q", report, text)
+	}
+}
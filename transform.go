@@ -0,0 +1,126 @@
+package scribus
+
+import (
+	"math"
+)
+
+// Anchor is the point of an item that stays in place when it is resized or that it is
+// rotated around
+type Anchor int
+
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTop
+	AnchorTopRight
+	AnchorLeft
+	AnchorCenter
+	AnchorRight
+	AnchorBottomLeft
+	AnchorBottom
+	AnchorBottomRight
+)
+
+// AnchorPoint returns the position of the anchor of the item on the page, taking its rotation
+// into account
+func (po PAGEOBJECT) AnchorPoint(anchor Anchor) Point {
+	local := Point{parseFloat(po.WIDTH) * float64(anchor%3) / 2, parseFloat(po.HEIGHT) * float64(anchor/3) / 2}
+	return Rotation(parseFloat(po.ROT)).Then(Translation(parseFloat(po.XPOS), parseFloat(po.YPOS))).Apply(local)
+}
+
+// Resize changes the size of the item to width x height, keeping the anchor in place, and
+// returns error. If proportional, both sides are scaled by the smaller of the two factors.
+// The outline, the image in image frames (LOCALSCX, LOCALSCY) and the items inside groups
+// are scaled with it; lines only change their length
+func (po *PAGEOBJECT) Resize(width float64, height float64, anchor Anchor, proportional bool) error {
+	sx, sy := 1.0, 1.0
+	if w := parseFloat(po.WIDTH); w != 0 {
+		sx = width / w
+	}
+	if h := parseFloat(po.HEIGHT); h != 0 {
+		sy = height / h
+	}
+	if proportional {
+		sx = math.Min(sx, sy)
+		sy = sx
+	}
+	before := po.AnchorPoint(anchor)
+	if err := scaleItem(po, sx, sy); err != nil {
+		return err
+	}
+	after := po.AnchorPoint(anchor)
+	shiftPageObject(po, before.X-after.X, before.Y-after.Y)
+	return nil
+}
+
+// scaleItem scales the item by sx and sy, keeping its top left corner in place. The items
+// inside groups are kept unrotated, the rotation of the group is applied to them when drawn
+func scaleItem(po *PAGEOBJECT, sx float64, sy float64) error {
+	width, height := parseFloat(po.WIDTH)*sx, parseFloat(po.HEIGHT)*sy
+	if po.IsGroup() {
+		origin := Point{parseFloat(po.XPOS), parseFloat(po.YPOS)}
+		for i := range po.PAGEOBJECT {
+			c := &po.PAGEOBJECT[i]
+			p := Point{parseFloat(c.XPOS), parseFloat(c.YPOS)}
+			local := Point{(p.X - origin.X) * sx, (p.Y - origin.Y) * sy}
+			if err := scaleItem(c, sx, sy); err != nil {
+				return err
+			}
+			shiftPageObject(c, origin.X+local.X-p.X, origin.Y+local.Y-p.Y)
+			c.GXpos, c.GYpos = formatFloat(local.X), formatFloat(local.Y)
+			c.GWidth, c.GHeight = formatFloat(width), formatFloat(height)
+		}
+		po.GroupWidth, po.GroupHeight = formatFloat(width), formatFloat(height)
+	}
+	if po.PTYPE == "2" {
+		po.LOCALSCX = formatFloat(parseFloat(po.LOCALSCX) * sx)
+		po.LOCALSCY = formatFloat(parseFloat(po.LOCALSCY) * sy)
+	}
+	return po.SetSize(width, height)
+}
+
+// RotateAround rotates the item by angle degrees clockwise around center, which is a point on
+// the page, and updates its position and ROT. The items inside groups move with the group
+func (po *PAGEOBJECT) RotateAround(angle float64, center Point) {
+	m := Translation(-center.X, -center.Y).Then(Rotation(angle)).Then(Translation(center.X, center.Y))
+	p := Point{parseFloat(po.XPOS), parseFloat(po.YPOS)}
+	q := m.Apply(p)
+	shiftPageObject(po, q.X-p.X, q.Y-p.Y)
+	po.ROT = formatFloat(math.Mod(parseFloat(po.ROT)+angle, 360))
+}
+
+// Rotate rotates the item by angle degrees clockwise around its anchor
+func (po *PAGEOBJECT) Rotate(angle float64, anchor Anchor) {
+	po.RotateAround(angle, po.AnchorPoint(anchor))
+}
+
+// Flip mirrors the item within its box horizontally and/or vertically, returns error. The
+// items inside groups swap sides and are flipped themselves
+func (po *PAGEOBJECT) Flip(horizontal bool, vertical bool) error {
+	if po.IsGroup() {
+		origin := Point{parseFloat(po.XPOS), parseFloat(po.YPOS)}
+		width, height := parseFloat(po.WIDTH), parseFloat(po.HEIGHT)
+		for i := range po.PAGEOBJECT {
+			c := &po.PAGEOBJECT[i]
+			// The top left corner of the item becomes the corner it is mirrored to
+			p := Point{parseFloat(c.XPOS), parseFloat(c.YPOS)}
+			mirrored, corner := p, Point{}
+			if horizontal {
+				mirrored.X, corner.X = 2*origin.X+width-p.X, parseFloat(c.WIDTH)
+			}
+			if vertical {
+				mirrored.Y, corner.Y = 2*origin.Y+height-p.Y, parseFloat(c.HEIGHT)
+			}
+			rotation := parseFloat(c.ROT)
+			if horizontal != vertical {
+				rotation = -rotation
+			}
+			offset := Rotation(rotation).Apply(corner)
+			shiftPageObject(c, mirrored.X-offset.X-p.X, mirrored.Y-offset.Y-p.Y)
+			c.ROT = formatFloat(rotation)
+			if err := c.Flip(horizontal, vertical); err != nil {
+				return err
+			}
+		}
+	}
+	return po.FlipOutline(horizontal, vertical)
+}
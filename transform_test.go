@@ -0,0 +1,66 @@
+package scribus
+
+import (
+	"testing"
+)
+
+func TestTransforms(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := &document.DOCUMENT
+	// Items at 140, 60 (118.5 x 52.5), 140, 113, 140, 181 (an image, 150.25 x 163.5) and 140, 368
+	a, b := &doc.PAGEOBJECT[0], &doc.PAGEOBJECT[1]
+
+	if err := a.Resize(237, 105, AnchorBottomRight, false); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if a.XPOS != "21.5" || a.YPOS != "7.5" || a.WIDTH != "237" || a.Path != "M0 0 L237 0 L237 105 L0 105 L0 0 Z" {
+		t.Errorf("Resize() was incorrect, got: %v, %v, %v x %v, %q", a.XPOS, a.YPOS, a.WIDTH, a.HEIGHT, a.Path)
+	}
+	if err := b.Resize(59.25, 500, AnchorCenter, true); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if b.XPOS != "169.625" || b.YPOS != "126.125" || b.WIDTH != "59.25" || b.HEIGHT != "26.25" {
+		t.Errorf("Resize() proportional was incorrect, got: %v, %v, %v x %v", b.XPOS, b.YPOS, b.WIDTH, b.HEIGHT)
+	}
+
+	// The centre of b is at 199.25, 139.25
+	b.Rotate(90, AnchorCenter)
+	if b.XPOS != "212.375" || b.YPOS != "109.625" || b.ROT != "90" {
+		t.Errorf("Rotate() was incorrect, got: %v, %v, %v", b.XPOS, b.YPOS, b.ROT)
+	}
+	if got := b.AnchorPoint(AnchorCenter); got != (Point{199.25, 139.25}) {
+		t.Errorf("AnchorPoint() was incorrect, got: %+v", got)
+	}
+	b.RotateAround(-90, Point{0, 0})
+	if b.XPOS != "109.625" || b.YPOS != "-212.375" || b.ROT != "0" {
+		t.Errorf("RotateAround() was incorrect, got: %v, %v, %v", b.XPOS, b.YPOS, b.ROT)
+	}
+	b.MovePageObject(140, 113)
+	if b.XPOS != "140" || b.YPOS != "113" || b.GXpos != "140" {
+		t.Errorf("MovePageObject() was incorrect, got: %v, %v", b.XPOS, b.YPOS)
+	}
+
+	// Groups flip and scale their items
+	group, err := doc.Group(&doc.PAGEOBJECT[3], &doc.PAGEOBJECT[2])
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if err := group.Flip(true, false); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	image, last := group.PAGEOBJECT[0], group.PAGEOBJECT[1]
+	if image.XPOS != "140" || last.XPOS != "171.75" || last.GXpos != "31.75" || last.FLIPPEDH != "1" || group.FLIPPEDH != "1" {
+		t.Errorf("Flip() of a group was incorrect, got: %v, %v, %v", image.XPOS, last.XPOS, last.FLIPPEDH)
+	}
+	// The group is 150.25 x 239.5, so the width doubles and limits the height
+	if err := group.Resize(300.5, 500, AnchorTopLeft, true); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	image, last = group.PAGEOBJECT[0], group.PAGEOBJECT[1]
+	if group.WIDTH != "300.5" || group.GroupHeight != "479" || image.WIDTH != "300.5" || image.LOCALSCX != "1.5" || last.YPOS != "555" || last.GYpos != "374" || last.GHeight != "479" {
+		t.Errorf("Resize() of a group was incorrect, got: %v x %v, %+v", group.WIDTH, group.HEIGHT, last)
+	}
+}
@@ -0,0 +1,98 @@
+package scribus
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// LayerVariant is one variant of a document, e.g., one language of a multilingual
+// brochure, made up of the layers that are only shown in this variant
+type LayerVariant struct {
+	Name   string   // e.g., "EN"
+	Layers []string // e.g., []string{"EN"}
+}
+
+// VariantDocument is a Document derived for a LayerVariant
+type VariantDocument struct {
+	Variant  LayerVariant
+	Document Document
+}
+
+// LayerVariants derives one Document per variant from the Document. In each of them the
+// layers of the variant are made visible and printable, while the layers that belong only
+// to other variants are made non-visible and non-printable, or are deleted along with their
+// items if remove is true. Layers that belong to no variant (e.g., shared artwork) are left
+// alone. Returns the derived documents in the order of variants, error
+func (scribusDocument Document) LayerVariants(variants []LayerVariant, remove bool) ([]VariantDocument, error) {
+	variantLayers := map[string]bool{}
+	for _, v := range variants {
+		for _, name := range v.Layers {
+			if scribusDocument.DOCUMENT.GetLayerByName(name) == nil {
+				return nil, fmt.Errorf("variant %v: layer %v not found", v.Name, name)
+			}
+			variantLayers[name] = true
+		}
+	}
+
+	var docs []VariantDocument
+	for _, v := range variants {
+		doc, err := scribusDocument.Clone()
+		if err != nil {
+			return nil, err
+		}
+		own := map[string]bool{}
+		for _, name := range v.Layers {
+			own[name] = true
+			l := doc.DOCUMENT.GetLayerByName(name)
+			l.SetVisible(true)
+			l.SetPrintable(true)
+		}
+		for name := range variantLayers {
+			if own[name] {
+				continue
+			}
+			if remove {
+				if err := doc.DOCUMENT.DeleteLayer(name, ""); err != nil {
+					return nil, fmt.Errorf("variant %v: %v", v.Name, err)
+				}
+				continue
+			}
+			l := doc.DOCUMENT.GetLayerByName(name)
+			l.SetVisible(false)
+			l.SetPrintable(false)
+		}
+		docs = append(docs, VariantDocument{Variant: v, Document: doc})
+	}
+	return docs, nil
+}
+
+// WriteLayerVariants writes one Scribus file per variant (see LayerVariants) and returns
+// the paths of the written files, error. The path of each file is pattern with "{variant}"
+// replaced by the name of the variant; if pattern does not contain "{variant}", the name is
+// appended to the file name, e.g., "brochure.sla" becomes "brochure-EN.sla"
+func (scribusDocument Document) WriteLayerVariants(pattern string, variants []LayerVariant, remove bool) ([]string, error) {
+	docs, err := scribusDocument.LayerVariants(variants, remove)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, d := range docs {
+		path := namedPath(pattern, "{variant}", d.Variant.Name)
+		if err := d.Document.WriteScribusFile(path); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// namedPath returns pattern with key replaced by name, or with name appended to the
+// file name if pattern does not contain key
+func namedPath(pattern string, key string, name string) string {
+	if strings.Contains(pattern, key) {
+		return strings.Replace(pattern, key, name, -1)
+	}
+	ext := filepath.Ext(pattern)
+	return strings.TrimSuffix(pattern, ext) + "-" + name + ext
+}
@@ -0,0 +1,55 @@
+package scribus
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLayerVariants(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	for _, name := range []string{"EN", "DE"} {
+		if _, err := document.DOCUMENT.AddLayer(name); err != nil {
+			t.Fatalf("error: %v", err)
+		}
+	}
+	document.DOCUMENT.MovePageObjectToLayer(&document.DOCUMENT.PAGEOBJECT[0], "EN")
+	document.DOCUMENT.MovePageObjectToLayer(&document.DOCUMENT.PAGEOBJECT[1], "DE")
+	variants := []LayerVariant{{Name: "EN", Layers: []string{"EN"}}, {Name: "DE", Layers: []string{"DE"}}}
+
+	docs, err := document.LayerVariants(variants, false)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("len(docs) was incorrect, got: %v, want: %v.", len(docs), 2)
+	}
+	en := docs[0].Document.DOCUMENT
+	if en.GetLayerByName("DE").Visible() || en.GetLayerByName("DE").Printable() || !en.GetLayerByName("EN").Visible() {
+		t.Errorf("layer DE was not hidden in variant EN")
+	}
+	if !document.DOCUMENT.GetLayerByName("DE").Visible() {
+		t.Errorf("the base document was modified")
+	}
+
+	paths, err := document.WriteLayerVariants(filepath.Join(t.TempDir(), "brochure.sla"), variants, true)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if filepath.Base(paths[1]) != "brochure-DE.sla" {
+		t.Errorf("paths[1] was incorrect, got: %v, want: %v.", filepath.Base(paths[1]), "brochure-DE.sla")
+	}
+	if _, err := os.Stat(paths[1]); err != nil {
+		t.Errorf("error: %v", err)
+	}
+	de, err := NewScribusDocumentFromFile(paths[1])
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if de.DOCUMENT.GetLayerByName("EN") != nil || len(de.DOCUMENT.PAGEOBJECT) != 3 {
+		t.Errorf("layer EN was not removed from variant DE")
+	}
+}
@@ -0,0 +1,127 @@
+package scribus
+
+import (
+	"fmt"
+)
+
+// stack returns the slice that holds the item, its index there and the indexes of the items
+// it is stacked with: the items on the same layer for items on pages and master pages, all
+// items of the group for items inside groups. Returns error if there is no such item
+func (doc *DOCUMENT) stack(po *PAGEOBJECT) (*[]PAGEOBJECT, int, []int, error) {
+	parent, index := doc.findItem(po.ItemID)
+	if parent == nil {
+		return nil, -1, nil, fmt.Errorf("item %v not found", po.ItemID)
+	}
+	inGroup := parent != &doc.PAGEOBJECT && parent != &doc.MASTEROBJECT
+	var peers []int
+	for i, item := range *parent {
+		if inGroup || item.LAYER == (*parent)[index].LAYER {
+			peers = append(peers, i)
+		}
+	}
+	return parent, index, peers, nil
+}
+
+// moveItem moves the item at the index from in objs so that it ends up at the index to
+func moveItem(objs []PAGEOBJECT, from int, to int) {
+	item := objs[from]
+	if from < to {
+		copy(objs[from:to], objs[from+1:to+1])
+	} else {
+		copy(objs[to+1:from+1], objs[to:from])
+	}
+	objs[to] = item
+}
+
+// BringToFront puts the item on top of the other items on its layer (or in its group),
+// returns error
+func (doc *DOCUMENT) BringToFront(po *PAGEOBJECT) error {
+	parent, index, peers, err := doc.stack(po)
+	if err != nil {
+		return err
+	}
+	moveItem(*parent, index, peers[len(peers)-1])
+	return nil
+}
+
+// SendToBack puts the item below the other items on its layer (or in its group), returns error
+func (doc *DOCUMENT) SendToBack(po *PAGEOBJECT) error {
+	parent, index, peers, err := doc.stack(po)
+	if err != nil {
+		return err
+	}
+	moveItem(*parent, index, peers[0])
+	return nil
+}
+
+// Raise moves the item one step up among the items on its layer (or in its group), returns
+// error. The topmost item stays where it is
+func (doc *DOCUMENT) Raise(po *PAGEOBJECT) error {
+	parent, index, peers, err := doc.stack(po)
+	if err != nil {
+		return err
+	}
+	for _, i := range peers {
+		if i > index {
+			moveItem(*parent, index, i)
+			break
+		}
+	}
+	return nil
+}
+
+// Lower moves the item one step down among the items on its layer (or in its group), returns
+// error. The bottom item stays where it is
+func (doc *DOCUMENT) Lower(po *PAGEOBJECT) error {
+	parent, index, peers, err := doc.stack(po)
+	if err != nil {
+		return err
+	}
+	for n := len(peers) - 1; n >= 0; n-- {
+		if peers[n] < index {
+			moveItem(*parent, index, peers[n])
+			break
+		}
+	}
+	return nil
+}
+
+// MoveAbove puts the item directly above other, which must be on the same layer (or in the
+// same group), returns error
+func (doc *DOCUMENT) MoveAbove(po *PAGEOBJECT, other *PAGEOBJECT) error {
+	return doc.moveNextTo(po, other, true)
+}
+
+// MoveBelow puts the item directly below other, which must be on the same layer (or in the
+// same group), returns error
+func (doc *DOCUMENT) MoveBelow(po *PAGEOBJECT, other *PAGEOBJECT) error {
+	return doc.moveNextTo(po, other, false)
+}
+
+// moveNextTo puts the item directly above or below other
+func (doc *DOCUMENT) moveNextTo(po *PAGEOBJECT, other *PAGEOBJECT, above bool) error {
+	parent, index, peers, err := doc.stack(po)
+	if err != nil {
+		return err
+	}
+	target := -1
+	for _, i := range peers {
+		if (*parent)[i].ItemID == other.ItemID {
+			target = i
+		}
+	}
+	if target < 0 {
+		return fmt.Errorf("item %v is not on the layer or in the group of item %v", other.ItemID, po.ItemID)
+	}
+	if target == index {
+		return fmt.Errorf("cannot move item %v next to itself", po.ItemID)
+	}
+	switch {
+	case above && index > target:
+		target++
+	case !above && index < target:
+		target--
+	}
+	moveItem(*parent, index, target)
+	return nil
+}
@@ -0,0 +1,72 @@
+package scribus
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestZOrder(t *testing.T) {
+	document, err := NewScribusDocumentFromFile("Document-1.sla")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	doc := &document.DOCUMENT
+	names := []string{"a", "b", "c", "d"}
+	for i := range doc.PAGEOBJECT {
+		doc.PAGEOBJECT[i].ANNAME = names[i]
+	}
+	order := func() []string {
+		var got []string
+		for _, po := range doc.PAGEOBJECT {
+			got = append(got, po.ANNAME)
+		}
+		return got
+	}
+	item := func(name string) *PAGEOBJECT { return doc.GetPageObjectByName(name) }
+	check := func(op string, err error, want ...string) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%v error: %v", op, err)
+		}
+		if got := order(); !reflect.DeepEqual(got, want) {
+			t.Errorf("%v was incorrect, got: %v, want: %v.", op, got, want)
+		}
+	}
+
+	check("BringToFront()", doc.BringToFront(item("a")), "b", "c", "d", "a")
+	check("SendToBack()", doc.SendToBack(item("d")), "d", "b", "c", "a")
+	check("Raise()", doc.Raise(item("b")), "d", "c", "b", "a")
+	check("Raise() of the topmost item", doc.Raise(item("a")), "d", "c", "b", "a")
+	check("Lower()", doc.Lower(item("a")), "d", "c", "a", "b")
+	check("MoveAbove()", doc.MoveAbove(item("d"), item("a")), "c", "a", "d", "b")
+	check("MoveBelow()", doc.MoveBelow(item("b"), item("a")), "c", "b", "a", "d")
+	check("MoveAbove() of a lower item", doc.MoveAbove(item("a"), item("c")), "c", "a", "b", "d")
+
+	// Items on other layers are skipped
+	layer, err := doc.AddLayer("Top")
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	item("a").LAYER = layer.NUMMER
+	check("Raise() past another layer", doc.Raise(item("c")), "a", "b", "c", "d")
+	check("BringToFront() on a layer", doc.BringToFront(item("b")), "a", "c", "d", "b")
+	check("SendToBack() on a layer", doc.SendToBack(item("d")), "a", "d", "c", "b")
+	if err := doc.MoveAbove(item("b"), item("a")); err == nil {
+		t.Errorf("MoveAbove() of an item on another layer should be an error")
+	}
+
+	// Items inside groups are stacked within the group
+	group, err := doc.Group(item("b"), item("c"))
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if err := doc.BringToFront(&group.PAGEOBJECT[0]); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if group = item(""); group.PAGEOBJECT[0].ANNAME != "b" || group.PAGEOBJECT[1].ANNAME != "c" || len(doc.PAGEOBJECT) != 3 {
+		t.Errorf("BringToFront() in a group was incorrect, got: %+v", group.PAGEOBJECT)
+	}
+	if err := doc.MoveBelow(&group.PAGEOBJECT[0], item("d")); err == nil {
+		t.Errorf("MoveBelow() of an item outside the group should be an error")
+	}
+}